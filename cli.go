@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go-dummy-monitor/collectors"
+	"go-dummy-monitor/record"
+	"go-dummy-monitor/utils"
+)
+
+// options holds the settings given on the command line
+type options struct {
+	hostOptions
+	watch       []collectors.WatchGroup // Process groups tracked with their own widgets
+	filesystems collectors.FilesystemFilter
+}
+
+// hostOptions holds the settings shared by the monitor and the run command
+type hostOptions struct {
+	hostRoot string // Where the monitored host's / is mounted, empty for the local system
+	backend  string // How the CPU, memory, disk and network counters are read
+}
+
+// addFlags defines the flags of the shared settings on flags
+func (h *hostOptions) addFlags(flags *flag.FlagSet) {
+	flags.StringVar(&h.hostRoot, "host-root", "",
+		"monitor the host whose root filesystem is mounted at `path`, such as /host in a container")
+	flags.StringVar(&h.backend, "backend", collectors.DefaultBackend,
+		"read CPU, memory, disk and network counters through `backend`: "+strings.Join(collectors.Backends, " or ")+
+			", "+collectors.BackendProcfs+" is Linux only and cheaper")
+}
+
+// validate checks the shared settings before any collector reads from the host
+func (h hostOptions) validate() error {
+	if h.hostRoot != "" {
+		if info, err := os.Stat(h.hostRoot); err != nil || !info.IsDir() {
+			return fmt.Errorf("host root %q is not a directory", h.hostRoot)
+		}
+	}
+	_, err := collectors.NewBackend(h.backend)
+	return err
+}
+
+// apply points every collector, and gopsutil underneath, at the host filesystems and selects the backend
+func (h hostOptions) apply() error {
+	if h.hostRoot != "" {
+		if err := utils.SetHostRoot(h.hostRoot); err != nil {
+			return err
+		}
+	}
+	collectors.DefaultBackend = h.backend
+	return nil
+}
+
+// stringList collects the values of a flag that may be given several times
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// parseOptions parses the command line arguments, writing usage and errors to output
+func parseOptions(args []string, output io.Writer) (options, error) {
+	flags := flag.NewFlagSet("go-dummy-monitor", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	var pids, names, patterns stringList
+	flags.Var(&pids, "watch-pid", "watch the process with this `PID`, may be repeated")
+	flags.Var(&names, "watch-name", "watch all processes with this executable `name`, may be repeated")
+	flags.Var(&patterns, "watch-regex", "watch all processes whose command line matches this `regex`, may be repeated")
+
+	var includeFstypes, excludeFstypes stringList
+	flags.Var(&includeFstypes, "fs-include", "only list filesystems of this `type`, may be repeated")
+	flags.Var(&excludeFstypes, "fs-exclude", "also leave out filesystems of this `type`, may be repeated")
+
+	var opts options
+	opts.addFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return options{}, err
+	}
+	if flags.NArg() > 0 {
+		return options{}, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	if err := opts.validate(); err != nil {
+		return options{}, err
+	}
+	opts.filesystems = collectors.DefaultFilesystemFilter().WithFstypes(includeFstypes, excludeFstypes)
+
+	seen := make(map[string]bool)
+	for _, values := range []struct {
+		kind   collectors.WatchKind
+		values []string
+	}{
+		{collectors.WatchByPID, pids},
+		{collectors.WatchByName, names},
+		{collectors.WatchByPattern, patterns},
+	} {
+		for _, value := range values.values {
+			group, err := collectors.NewWatchGroup(values.kind, value)
+			if err != nil {
+				return options{}, err
+			}
+			// Groups are told apart by name in the metric names
+			if seen[group.Name] {
+				return options{}, fmt.Errorf("%s is watched twice", group.Name)
+			}
+			seen[group.Name] = true
+			opts.watch = append(opts.watch, group)
+		}
+	}
+	return opts, nil
+}
+
+// runOptions holds the settings of the run command
+type runOptions struct {
+	hostOptions
+	command  []string
+	interval time.Duration
+	output   string // Path of the JSON summary and HTML report without extension
+}
+
+// parseRunOptions parses the arguments of the run command, writing usage and errors to output
+func parseRunOptions(args []string, output io.Writer, now time.Time) (runOptions, error) {
+	flags := flag.NewFlagSet("go-dummy-monitor run", flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.Usage = func() {
+		fmt.Fprintln(output, "Usage: go-dummy-monitor run [flags] -- command [arguments]")
+		flags.PrintDefaults()
+	}
+
+	opts := runOptions{}
+	flags.DurationVar(&opts.interval, "interval", record.DefaultInterval, "sampling `interval`")
+	flags.StringVar(&opts.output, "out", "record-"+now.Format("20060102-150405"),
+		"`path` of the summary and report, .json and .html are appended")
+	opts.addFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return runOptions{}, err
+	}
+	if flags.NArg() == 0 {
+		return runOptions{}, fmt.Errorf("no command to run")
+	}
+	if opts.interval <= 0 {
+		return runOptions{}, fmt.Errorf("invalid interval %s", opts.interval)
+	}
+	if err := opts.validate(); err != nil {
+		return runOptions{}, err
+	}
+	opts.command = flags.Args()
+	return opts, nil
+}
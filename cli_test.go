@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"go-dummy-monitor/collectors"
+)
+
+func TestParseOptionsWatch(t *testing.T) {
+	opts, err := parseOptions([]string{
+		"--watch-name", "gopls",
+		"--watch-pid=42",
+		"-watch-regex", "postgres: .*",
+		"--watch-name", "node",
+	}, io.Discard)
+	if err != nil {
+		t.Fatalf("Expected the options to parse, got %v", err)
+	}
+
+	// Groups are ordered by kind, then as given
+	want := []struct {
+		kind collectors.WatchKind
+		name string
+	}{
+		{collectors.WatchByPID, "pid 42"},
+		{collectors.WatchByName, "gopls"},
+		{collectors.WatchByName, "node"},
+		{collectors.WatchByPattern, "/postgres: .*/"},
+	}
+	if len(opts.watch) != len(want) {
+		t.Fatalf("Expected %d watched groups, got %+v", len(want), opts.watch)
+	}
+	for i, group := range opts.watch {
+		if group.Kind != want[i].kind || group.Name != want[i].name {
+			t.Errorf("Expected group %d to be %s %q, got %s %q", i, want[i].kind, want[i].name, group.Kind, group.Name)
+		}
+	}
+}
+
+func TestParseOptionsErrors(t *testing.T) {
+	cases := [][]string{
+		{"--watch-pid", "abc"},
+		{"--watch-regex", "("},
+		{"--watch-name", "gopls", "--watch-name", "gopls"},
+		{"--unknown"},
+		{"stray"},
+	}
+	for _, args := range cases {
+		if _, err := parseOptions(args, io.Discard); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+
+	if _, err := parseOptions([]string{"-h"}, io.Discard); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Expected help to be requested, got %v", err)
+	}
+}
+
+func TestParseOptionsEmpty(t *testing.T) {
+	opts, err := parseOptions(nil, io.Discard)
+	if err != nil || len(opts.watch) != 0 {
+		t.Errorf("Expected no watched groups without arguments, got %+v and %v", opts.watch, err)
+	}
+}
+
+func TestParseOptionsFilesystems(t *testing.T) {
+	opts, err := parseOptions(nil, io.Discard)
+	if err != nil || opts.filesystems.Matches("tmpfs", "/run") || !opts.filesystems.Matches("ext4", "/") {
+		t.Errorf("Expected the default filesystem filter, got %+v and %v", opts.filesystems, err)
+	}
+
+	opts, err = parseOptions([]string{"--fs-include", "tmpfs", "--fs-include", "ext4", "--fs-exclude", "vfat"}, io.Discard)
+	if err != nil {
+		t.Fatalf("Expected the options to parse, got %v", err)
+	}
+	if !opts.filesystems.Matches("tmpfs", "/run") || opts.filesystems.Matches("xfs", "/data") || opts.filesystems.Matches("vfat", "/boot/efi") {
+		t.Errorf("Expected only tmpfs and ext4 to be listed, got %+v", opts.filesystems)
+	}
+}
+
+func TestParseOptionsHostRoot(t *testing.T) {
+	root := t.TempDir()
+	opts, err := parseOptions([]string{"--host-root", root}, io.Discard)
+	if err != nil || opts.hostRoot != root {
+		t.Errorf("Expected the host root %q, got %q and %v", root, opts.hostRoot, err)
+	}
+
+	// A missing directory is reported before any collector reads from it
+	if _, err := parseOptions([]string{"--host-root", filepath.Join(root, "missing")}, io.Discard); err == nil {
+		t.Error("Expected an error for a missing host root")
+	}
+}
+
+func TestParseOptionsBackend(t *testing.T) {
+	opts, err := parseOptions(nil, io.Discard)
+	if err != nil || opts.backend != collectors.BackendGopsutil {
+		t.Errorf("Expected the gopsutil backend by default, got %q and %v", opts.backend, err)
+	}
+
+	if _, err := parseOptions([]string{"--backend", "sysctl"}, io.Discard); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+
+	opts, err = parseOptions([]string{"--backend", collectors.BackendProcfs}, io.Discard)
+	if runtime.GOOS == "linux" && (err != nil || opts.backend != collectors.BackendProcfs) {
+		t.Errorf("Expected the procfs backend, got %q and %v", opts.backend, err)
+	}
+	if runtime.GOOS != "linux" && err == nil {
+		t.Error("Expected the procfs backend to be refused outside Linux")
+	}
+}
+
+func TestParseRunOptions(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+
+	opts, err := parseRunOptions([]string{"--interval", "100ms", "--", "make", "-j8", "build"}, io.Discard, now)
+	if err != nil {
+		t.Fatalf("Expected the options to parse, got %v", err)
+	}
+	// Flags after the separator belong to the command
+	if strings.Join(opts.command, " ") != "make -j8 build" {
+		t.Errorf("Expected the command after the separator, got %q", opts.command)
+	}
+	if opts.interval != 100*time.Millisecond {
+		t.Errorf("Expected a 100ms interval, got %s", opts.interval)
+	}
+	if opts.output != "record-20240501-093000" {
+		t.Errorf("Expected the default output named after the start time, got %q", opts.output)
+	}
+
+	opts, err = parseRunOptions([]string{"--out", "build/run", "sleep", "1"}, io.Discard, now)
+	if err != nil || opts.output != "build/run" || len(opts.command) != 2 {
+		t.Errorf("Expected the command without a separator, got %+v and %v", opts, err)
+	}
+
+	// The host settings of the monitor apply to the recorded system too
+	root := t.TempDir()
+	opts, err = parseRunOptions([]string{"--host-root", root, "--backend", collectors.BackendGopsutil, "--", "make"}, io.Discard, now)
+	if err != nil || opts.hostRoot != root || opts.backend != collectors.BackendGopsutil || len(opts.command) != 1 {
+		t.Errorf("Expected the host root and backend before the separator, got %+v and %v", opts, err)
+	}
+
+	for _, args := range [][]string{
+		{}, {"--"}, {"--interval", "0s", "--", "make"}, {"--interval", "soon", "make"},
+		{"--backend", "sysctl", "--", "make"}, {"--host-root", filepath.Join(root, "missing"), "--", "make"},
+	} {
+		if _, err := parseRunOptions(args, io.Discard, now); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/mem"
+	psnet "github.com/shirou/gopsutil/net"
+
+	"go-dummy-monitor/utils"
+)
+
+// Backend names, selecting how the CPU, memory, disk and network counters are read
+const (
+	BackendGopsutil = "gopsutil" // Portable, through gopsutil
+	BackendProcfs   = "procfs"   // Linux only, parsing /proc directly without allocating
+)
+
+// Backends lists the names accepted by NewBackend
+var Backends = []string{BackendGopsutil, BackendProcfs}
+
+// DefaultBackend names the backend of the collectors created by Builtin
+var DefaultBackend = BackendGopsutil
+
+// Backend reads the raw counters behind the CPU, memory, disk and network collectors.
+// Methods may be called concurrently, returned values are owned by the backend and only
+// valid until the next call of the same method.
+type Backend interface {
+	// Name returns the backend name
+	Name() string
+	// CPUTimes returns the cumulative times of all CPUs together and of every logical CPU
+	CPUTimes(ctx context.Context) (cpu.TimesStat, []cpu.TimesStat, error)
+	// VirtualMemory returns the RAM usage
+	VirtualMemory(ctx context.Context) (*mem.VirtualMemoryStat, error)
+	// SwapMemory returns the swap usage and the bytes swapped in and out since boot
+	SwapMemory(ctx context.Context) (*mem.SwapMemoryStat, error)
+	// DiskIOCounters returns the cumulative I/O counters of every block device with activity
+	DiskIOCounters(ctx context.Context) ([]disk.IOCountersStat, error)
+	// NetIOCounters returns the cumulative counters of every network interface
+	NetIOCounters(ctx context.Context) ([]psnet.IOCountersStat, error)
+}
+
+// NewBackend creates the backend with the given name, the procfs one reading the host procfs
+func NewBackend(name string) (Backend, error) {
+	switch name {
+	case BackendGopsutil:
+		return NewGopsutilBackend(), nil
+	case BackendProcfs:
+		if runtime.GOOS != "linux" {
+			return nil, fmt.Errorf("the %s backend is only available on Linux", name)
+		}
+		return NewProcfsBackend(utils.HostProc()), nil
+	}
+	return nil, fmt.Errorf("unknown backend %q", name)
+}
+
+// NewDefaultBackend creates the backend named by DefaultBackend, or the gopsutil one when it is unavailable
+func NewDefaultBackend() Backend {
+	backend, err := NewBackend(DefaultBackend)
+	if err != nil {
+		return NewGopsutilBackend()
+	}
+	return backend
+}
+
+// GopsutilBackend reads the counters through gopsutil
+type GopsutilBackend struct {
+	disks []disk.IOCountersStat
+}
+
+// NewGopsutilBackend creates a new GopsutilBackend
+func NewGopsutilBackend() *GopsutilBackend {
+	return &GopsutilBackend{}
+}
+
+// Name returns the backend name
+func (g *GopsutilBackend) Name() string {
+	return BackendGopsutil
+}
+
+// CPUTimes returns the cumulative times of all CPUs together and of every logical CPU
+func (g *GopsutilBackend) CPUTimes(ctx context.Context) (cpu.TimesStat, []cpu.TimesStat, error) {
+	total, err := cpu.TimesWithContext(ctx, false)
+	if err != nil {
+		return cpu.TimesStat{}, nil, err
+	}
+	if len(total) == 0 {
+		return cpu.TimesStat{}, nil, fmt.Errorf("no CPU times")
+	}
+	perCore, err := cpu.TimesWithContext(ctx, true)
+	return total[0], perCore, err
+}
+
+// VirtualMemory returns the RAM usage
+func (g *GopsutilBackend) VirtualMemory(ctx context.Context) (*mem.VirtualMemoryStat, error) {
+	return mem.VirtualMemoryWithContext(ctx)
+}
+
+// SwapMemory returns the swap usage and the bytes swapped in and out since boot
+func (g *GopsutilBackend) SwapMemory(ctx context.Context) (*mem.SwapMemoryStat, error) {
+	return mem.SwapMemoryWithContext(ctx)
+}
+
+// DiskIOCounters returns the cumulative I/O counters of every block device with activity
+func (g *GopsutilBackend) DiskIOCounters(ctx context.Context) ([]disk.IOCountersStat, error) {
+	counters, err := disk.IOCountersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g.disks = g.disks[:0]
+	for _, stats := range counters {
+		g.disks = append(g.disks, stats)
+	}
+	return g.disks, nil
+}
+
+// NetIOCounters returns the cumulative counters of every network interface
+func (g *GopsutilBackend) NetIOCounters(ctx context.Context) ([]psnet.IOCountersStat, error) {
+	return psnet.IOCountersWithContext(ctx, true)
+}
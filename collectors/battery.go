@@ -0,0 +1,212 @@
+package collectors
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// BatteryInterval is how often batteries are read, the kernel updates them every few seconds
+const BatteryInterval = 5 * time.Second
+
+// Battery metric names, summed over every system battery
+const (
+	MetricBatteryPercent   = "battery.percent"   // Remaining charge in percent of the full capacity
+	MetricBatteryCharge    = "battery.charge"    // W flowing into the charging batteries
+	MetricBatteryDischarge = "battery.discharge" // W drawn from the discharging batteries
+	MetricBatteryEmpty     = "battery.empty"     // Minutes until empty at the current net drain, zero unless draining
+	MetricBatteryAC        = "battery.ac"        // 1 when on external power
+	MetricBatteryCycles    = "battery.cycles"    // Charge cycles of the most used battery
+)
+
+// Battery charging states as reported by the kernel
+const (
+	BatteryCharging    = "Charging"
+	BatteryDischarging = "Discharging"
+	BatteryFull        = "Full"
+)
+
+// BatteryInfo is the latest state of one battery
+type BatteryInfo struct {
+	Name       string  // Power supply name, e.g. BAT0
+	Status     string  // BatteryCharging, BatteryDischarging, BatteryFull, "Not charging" or "Unknown"
+	Percent    float64 // Remaining charge in percent of the full capacity
+	PowerW     float64 // Charge rate while charging, discharge rate while discharging
+	EnergyWh   float64 // Remaining energy, zero when the battery only reports a percentage
+	FullWh     float64 // Capacity when fully charged
+	DesignWh   float64 // Capacity when new
+	CycleCount int     // Zero when unknown
+}
+
+// Health returns the full capacity in percent of the design capacity, zero when unknown
+func (b BatteryInfo) Health() float64 {
+	if b.DesignWh <= 0 {
+		return 0
+	}
+	return b.FullWh / b.DesignWh * 100
+}
+
+// BatteryCollector reads the batteries and the AC adapter from the power supply class in sysfs
+type BatteryCollector struct {
+	interval  time.Duration
+	sysPath   string
+	batteries []BatteryInfo
+}
+
+// NewBatteryCollector creates a new BatteryCollector reading the sysfs mounted at sysPath
+func NewBatteryCollector(interval time.Duration, sysPath string) *BatteryCollector {
+	return &BatteryCollector{
+		interval: interval,
+		sysPath:  sysPath,
+	}
+}
+
+// Name returns the collector name
+func (b *BatteryCollector) Name() string {
+	return "battery"
+}
+
+// Interval returns the collection interval
+func (b *BatteryCollector) Interval() time.Duration {
+	return b.interval
+}
+
+// Collect reads every battery, reporting nothing on machines without one
+func (b *BatteryCollector) Collect(_ context.Context) ([]Sample, error) {
+	batteries, online, hasAdapter := readPowerSupplies(filepath.Join(b.sysPath, "class", "power_supply"))
+	b.batteries = batteries
+	if len(batteries) == 0 {
+		return nil, nil
+	}
+	return batterySamples(batteries, online, hasAdapter), nil
+}
+
+// Details returns the batteries of the latest collection sorted by name
+func (b *BatteryCollector) Details() any {
+	return b.batteries
+}
+
+// HasBattery reports whether the sysfs mounted at sysPath shows a system battery
+func HasBattery(sysPath string) bool {
+	batteries, _, _ := readPowerSupplies(filepath.Join(sysPath, "class", "power_supply"))
+	return len(batteries) > 0
+}
+
+// batterySamples combines the batteries into the battery metrics, weighing them by capacity
+func batterySamples(batteries []BatteryInfo, online, hasAdapter bool) []Sample {
+	var energy, full, percent, charge, discharge float64
+	var cycles int
+	discharging := false
+	for _, battery := range batteries {
+		energy += battery.EnergyWh
+		full += battery.FullWh
+		percent += battery.Percent
+		if battery.CycleCount > cycles {
+			cycles = battery.CycleCount
+		}
+		switch battery.Status {
+		case BatteryDischarging:
+			discharging = true
+			discharge += battery.PowerW
+		case BatteryCharging:
+			charge += battery.PowerW
+		}
+	}
+
+	// Batteries reporting only a percentage are averaged instead
+	if full > 0 {
+		percent = energy / full * 100
+	} else {
+		percent /= float64(len(batteries))
+	}
+
+	// One battery may charge while another one discharges, only the net drain empties them
+	var minutesLeft float64
+	if drain := discharge - charge; discharging && drain > 0 && energy > 0 {
+		minutesLeft = energy / drain * 60
+	}
+
+	// Without an adapter in sysfs, not discharging is the best hint of external power
+	if !hasAdapter {
+		online = !discharging
+	}
+	ac := 0.0
+	if online {
+		ac = 1
+	}
+
+	return []Sample{
+		{Name: MetricBatteryPercent, Value: math.Min(percent, 100)},
+		{Name: MetricBatteryCharge, Value: charge},
+		{Name: MetricBatteryDischarge, Value: discharge},
+		{Name: MetricBatteryEmpty, Value: minutesLeft},
+		{Name: MetricBatteryAC, Value: ac},
+		{Name: MetricBatteryCycles, Value: float64(cycles)},
+	}
+}
+
+// readPowerSupplies reads every system battery, and whether an adapter is online if there is one
+func readPowerSupplies(powerSupplyPath string) (batteries []BatteryInfo, online, hasAdapter bool) {
+	supplies, _ := filepath.Glob(filepath.Join(powerSupplyPath, "*"))
+	sort.Strings(supplies)
+
+	for _, supply := range supplies {
+		switch readSysString(filepath.Join(supply, "type")) {
+		case "Mains", "USB":
+			hasAdapter = true
+			if value, ok := readSysNumber(filepath.Join(supply, "online")); ok && value > 0 {
+				online = true
+			}
+		case "Battery":
+			// Mice, keyboards and headsets report their batteries with a device scope
+			if readSysString(filepath.Join(supply, "scope")) == "Device" {
+				continue
+			}
+			if value, ok := readSysNumber(filepath.Join(supply, "present")); ok && value == 0 {
+				continue
+			}
+			batteries = append(batteries, readBattery(supply))
+		}
+	}
+	return batteries, online, hasAdapter
+}
+
+// readBattery reads one battery, which reports either energy in µWh and power in µW,
+// or charge in µAh and current in µA to be multiplied by the voltage in µV
+func readBattery(path string) BatteryInfo {
+	attribute := func(name string) float64 {
+		value, _ := readSysNumber(filepath.Join(path, name))
+		return value
+	}
+
+	battery := BatteryInfo{
+		Name:       filepath.Base(path),
+		Status:     readSysString(filepath.Join(path, "status")),
+		CycleCount: int(attribute("cycle_count")),
+	}
+
+	const micro = 1e6
+	if _, ok := readSysNumber(filepath.Join(path, "energy_now")); ok {
+		battery.EnergyWh = attribute("energy_now") / micro
+		battery.FullWh = attribute("energy_full") / micro
+		battery.DesignWh = attribute("energy_full_design") / micro
+		battery.PowerW = attribute("power_now") / micro
+	} else {
+		volts := attribute("voltage_now") / micro
+		battery.EnergyWh = attribute("charge_now") / micro * volts
+		battery.FullWh = attribute("charge_full") / micro * volts
+		battery.DesignWh = attribute("charge_full_design") / micro * volts
+		battery.PowerW = attribute("current_now") / micro * volts
+	}
+	// Some firmware reports the discharge rate as a negative number, the status tells the direction
+	battery.PowerW = math.Abs(battery.PowerW)
+
+	if capacity, ok := readSysNumber(filepath.Join(path, "capacity")); ok {
+		battery.Percent = capacity
+	} else if battery.FullWh > 0 {
+		battery.Percent = battery.EnergyWh / battery.FullWh * 100
+	}
+	return battery
+}
@@ -0,0 +1,128 @@
+package collectors
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestBatteryCollector(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/power_supply/AC/type":   "Mains",
+		"class/power_supply/AC/online": "0",
+		// An energy reporting battery, 40 of 50 Wh left at 10 W
+		"class/power_supply/BAT0/type":               "Battery",
+		"class/power_supply/BAT0/status":             "Discharging",
+		"class/power_supply/BAT0/capacity":           "80",
+		"class/power_supply/BAT0/energy_now":         "40000000",
+		"class/power_supply/BAT0/energy_full":        "50000000",
+		"class/power_supply/BAT0/energy_full_design": "57000000",
+		"class/power_supply/BAT0/power_now":          "10000000",
+		"class/power_supply/BAT0/cycle_count":        "312",
+		// A charge reporting battery, 2 of 2.5 Ah at 10 V draining 0.5 A
+		"class/power_supply/BAT1/type":        "Battery",
+		"class/power_supply/BAT1/status":      "Discharging",
+		"class/power_supply/BAT1/charge_now":  "2000000",
+		"class/power_supply/BAT1/charge_full": "2500000",
+		"class/power_supply/BAT1/current_now": "-500000",
+		"class/power_supply/BAT1/voltage_now": "10000000",
+		// Peripheral batteries are not system batteries
+		"class/power_supply/hidpp_battery_0/type":     "Battery",
+		"class/power_supply/hidpp_battery_0/scope":    "Device",
+		"class/power_supply/hidpp_battery_0/capacity": "5",
+	})
+
+	collector := NewBatteryCollector(BatteryInterval, root)
+	samples, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+	// 60 of 75 Wh left, drained at 15 W, lasts 4 hours
+	expected := map[string]float64{
+		MetricBatteryPercent:   80,
+		MetricBatteryCharge:    0,
+		MetricBatteryDischarge: 15,
+		MetricBatteryEmpty:     240,
+		MetricBatteryAC:        0,
+		MetricBatteryCycles:    312,
+	}
+	for name, want := range expected {
+		if got, ok := values[name]; !ok || math.Abs(got-want) > 1e-9 {
+			t.Errorf("Expected %s to be %f, got %f (present %v)", name, want, got, ok)
+		}
+	}
+
+	batteries, ok := collector.Details().([]BatteryInfo)
+	if !ok || len(batteries) != 2 {
+		t.Fatalf("Expected 2 batteries, got %v", collector.Details())
+	}
+	if batteries[1].Name != "BAT1" || batteries[1].Percent != 80 || batteries[1].PowerW != 5 {
+		t.Errorf("Unexpected charge based battery %+v", batteries[1])
+	}
+	if health := batteries[0].Health(); math.Abs(health-87.719) > 0.001 {
+		t.Errorf("Expected a health of 87.7%%, got %f", health)
+	}
+}
+
+func TestBatteryCollectorWithoutBattery(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/power_supply/AC/type":   "Mains",
+		"class/power_supply/AC/online": "1",
+	})
+
+	if HasBattery(root) {
+		t.Error("Expected no battery behind an AC adapter only")
+	}
+	samples, err := NewBatteryCollector(BatteryInterval, root).Collect(context.Background())
+	if err != nil || len(samples) != 0 {
+		t.Errorf("Expected no samples and no error, got %v and %v", samples, err)
+	}
+}
+
+func TestBatterySamplesWithoutAdapter(t *testing.T) {
+	// Without an adapter in sysfs, a charging battery means external power
+	samples := batterySamples([]BatteryInfo{{Status: BatteryCharging, Percent: 50, PowerW: 20}}, false, false)
+	for _, sample := range samples {
+		switch sample.Name {
+		case MetricBatteryAC:
+			if sample.Value != 1 {
+				t.Errorf("Expected external power while charging, got %f", sample.Value)
+			}
+		case MetricBatteryEmpty:
+			if sample.Value != 0 {
+				t.Errorf("Expected no time to empty while charging, got %f", sample.Value)
+			}
+		case MetricBatteryPercent:
+			if sample.Value != 50 {
+				t.Errorf("Expected the reported percentage without energy counters, got %f", sample.Value)
+			}
+		}
+	}
+}
+
+func TestBatterySamplesChargingAndDischarging(t *testing.T) {
+	// The second battery charges from the first one at 4 of its 10 W
+	samples := batterySamples([]BatteryInfo{
+		{Name: "BAT0", Status: BatteryDischarging, PowerW: 10, EnergyWh: 30, FullWh: 50},
+		{Name: "BAT1", Status: BatteryCharging, PowerW: 4, EnergyWh: 10, FullWh: 50},
+	}, false, true)
+
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+	// 40 Wh left at a net drain of 6 W last 400 minutes
+	if values[MetricBatteryCharge] != 4 || values[MetricBatteryDischarge] != 10 || values[MetricBatteryEmpty] != 400 {
+		t.Errorf("Expected 4 W in, 10 W out and 400 minutes left, got %v", values)
+	}
+	if values[MetricBatteryPercent] != 40 || values[MetricBatteryAC] != 0 {
+		t.Errorf("Expected 40%% on battery power, got %v", values)
+	}
+}
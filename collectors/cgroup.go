@@ -0,0 +1,290 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CgroupPrefix starts the metric names of every cgroup
+const CgroupPrefix = "cgroup."
+
+// Cgroup measurement kinds, the last part of a cgroup metric name
+const (
+	CgroupCPU    = "cpu"    // Percent of the cgroup CPU limit, or of all CPUs without one
+	CgroupMemory = "memory" // Percent of memory.max, or of the host RAM without a limit
+	CgroupRead   = "read"   // MB/s
+	CgroupWrite  = "write"  // MB/s
+	CgroupPids   = "pids"   // Processes and threads in the cgroup and below it
+)
+
+// DefaultCgroupDepth is how many levels below the root are walked, enough for
+// system.slice/docker-<id>.scope without descending into every nested service
+const DefaultCgroupDepth = 3
+
+// CgroupMetric returns the name of one measurement kind of the cgroup at path, e.g. cgroup./system.slice.cpu
+func CgroupMetric(path, kind string) string {
+	return CgroupPrefix + path + "." + kind
+}
+
+// CgroupUsage is the resource usage of one cgroup against its limits
+type CgroupUsage struct {
+	Path        string  // Relative to the cgroup root, "/" for the root itself
+	CPUCores    float64 // CPUs used
+	CPULimit    float64 // CPUs allowed by cpu.max, zero when unlimited
+	MemoryBytes uint64
+	MemoryMax   uint64  // Zero when unlimited
+	ReadRate    float64 // Bytes/s
+	WriteRate   float64 // Bytes/s
+	Pids        uint64
+	PidsMax     uint64 // Zero when unlimited
+}
+
+// CPUPercent returns the CPU usage in percent of the limit, or of the given CPU count without one
+func (c CgroupUsage) CPUPercent(cpus int) float64 {
+	limit := c.CPULimit
+	if limit <= 0 {
+		limit = float64(cpus)
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return c.CPUCores / limit * 100
+}
+
+// MemoryPercent returns the memory usage in percent of the limit, or of the given total without one
+func (c CgroupUsage) MemoryPercent(total uint64) float64 {
+	limit := c.MemoryMax
+	if limit == 0 {
+		limit = total
+	}
+	if limit == 0 {
+		return 0
+	}
+	return float64(c.MemoryBytes) / float64(limit) * 100
+}
+
+// CgroupCollector walks the cgroup v2 hierarchy and reports the usage of every cgroup
+type CgroupCollector struct {
+	interval time.Duration
+	backend  Backend
+	sysPath  string
+	maxDepth int
+	hostCPUs int
+	rates    *CounterRate
+	cgroups  []CgroupUsage
+}
+
+// NewCgroupCollector creates a new CgroupCollector walking maxDepth levels of the cgroup
+// hierarchy of the sysfs mounted at sysPath, with the host RAM read from backend
+func NewCgroupCollector(interval time.Duration, backend Backend, sysPath string, maxDepth int) *CgroupCollector {
+	return &CgroupCollector{
+		interval: interval,
+		backend:  backend,
+		sysPath:  sysPath,
+		maxDepth: maxDepth,
+		rates:    NewCounterRate(),
+	}
+}
+
+// Name returns the collector name
+func (c *CgroupCollector) Name() string {
+	return "cgroups"
+}
+
+// Interval returns the collection interval
+func (c *CgroupCollector) Interval() time.Duration {
+	return c.interval
+}
+
+// MetricPrefix returns the prefix of the cgroup metrics, those of removed cgroups are dropped
+func (c *CgroupCollector) MetricPrefix() string {
+	return CgroupPrefix
+}
+
+// errNoCgroupV2 is returned where there is no unified cgroup hierarchy, such as with cgroup v1 only
+var errNoCgroupV2 = errors.New("no cgroup v2 hierarchy")
+
+// Collect reads the usage of every cgroup, usage without a limit is relative to the host
+func (c *CgroupCollector) Collect(ctx context.Context) ([]Sample, error) {
+	root, ok := cgroupRoot(c.sysPath)
+	if !ok {
+		c.cgroups = nil
+		return nil, errNoCgroupV2
+	}
+
+	var hostMemory uint64
+	if v, err := c.backend.VirtualMemory(ctx); err == nil {
+		hostMemory = v.Total
+	}
+	if c.hostCPUs == 0 {
+		c.hostCPUs = HostLogicalCores(ctx)
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	var cgroups []CgroupUsage
+	_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		// Past the deadline the cgroups read so far are reported
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		name, depth := "/", 0
+		if rel != "." {
+			name = "/" + filepath.ToSlash(rel)
+			depth = strings.Count(name, "/")
+		}
+		if depth > c.maxDepth {
+			return fs.SkipDir
+		}
+		if usage, ok := c.readCgroup(path, name, now, seen); ok {
+			cgroups = append(cgroups, usage)
+		}
+		return nil
+	})
+	// A walk cut short by the deadline fails, so the cgroups it did not reach keep their metrics
+	interrupted := ctx.Err()
+	if interrupted == nil {
+		c.rates.Forget(seen)
+	}
+
+	sort.Slice(cgroups, func(i, j int) bool { return cgroups[i].Path < cgroups[j].Path })
+	c.cgroups = cgroups
+
+	samples := make([]Sample, 0, len(cgroups)*5)
+	for _, usage := range cgroups {
+		samples = append(samples,
+			Sample{Name: CgroupMetric(usage.Path, CgroupCPU), Value: usage.CPUPercent(c.hostCPUs)},
+			Sample{Name: CgroupMetric(usage.Path, CgroupMemory), Value: usage.MemoryPercent(hostMemory)},
+			Sample{Name: CgroupMetric(usage.Path, CgroupRead), Value: usage.ReadRate / bytesPerMB},
+			Sample{Name: CgroupMetric(usage.Path, CgroupWrite), Value: usage.WriteRate / bytesPerMB},
+			Sample{Name: CgroupMetric(usage.Path, CgroupPids), Value: float64(usage.Pids)},
+		)
+	}
+	return samples, interrupted
+}
+
+// Details returns the cgroups of the latest collection sorted by path
+func (c *CgroupCollector) Details() any {
+	return c.cgroups
+}
+
+// cgroupRoot returns the unified hierarchy, mounted on its own or next to v1 controllers in hybrid mode
+func cgroupRoot(sysPath string) (string, bool) {
+	for _, root := range []string{
+		filepath.Join(sysPath, "fs", "cgroup"),
+		filepath.Join(sysPath, "fs", "cgroup", "unified"),
+	} {
+		if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+// readCgroup reads the usage of the cgroup directory at dir, false when it accounts for nothing
+func (c *CgroupCollector) readCgroup(dir, path string, now time.Time, seen map[string]bool) (CgroupUsage, bool) {
+	usage := CgroupUsage{Path: path}
+	cpuStat, cpuErr := os.ReadFile(filepath.Join(dir, "cpu.stat"))
+	memory, hasMemory := readSysNumber(filepath.Join(dir, "memory.current"))
+	if cpuErr != nil && !hasMemory {
+		return usage, false
+	}
+
+	if cpuErr == nil {
+		if usec, ok := keyedValue(string(cpuStat), "usage_usec"); ok {
+			seen[path+".cpu"] = true
+			// Microseconds of CPU time per second are millionths of a CPU
+			if perSecond, ok := c.rates.Rate(path+".cpu", usec, now); ok {
+				usage.CPUCores = perSecond / 1e6
+			}
+		}
+	}
+	usage.CPULimit = parseCPUMax(readSysString(filepath.Join(dir, "cpu.max")))
+
+	if hasMemory {
+		usage.MemoryBytes = uint64(memory)
+	}
+	usage.MemoryMax = parseLimit(readSysString(filepath.Join(dir, "memory.max")))
+
+	if ioStat, err := os.ReadFile(filepath.Join(dir, "io.stat")); err == nil {
+		read, written := parseIOStat(string(ioStat))
+		seen[path+".read"], seen[path+".write"] = true, true
+		usage.ReadRate, _ = c.rates.Rate(path+".read", read, now)
+		usage.WriteRate, _ = c.rates.Rate(path+".write", written, now)
+	}
+
+	if pids, ok := readSysNumber(filepath.Join(dir, "pids.current")); ok {
+		usage.Pids = uint64(pids)
+	}
+	usage.PidsMax = parseLimit(readSysString(filepath.Join(dir, "pids.max")))
+	return usage, true
+}
+
+// keyedValue returns the value of a "key value" line of a flat keyed file such as cpu.stat
+func keyedValue(data, key string) (uint64, bool) {
+	for _, line := range strings.Split(data, "\n") {
+		if name, value, ok := strings.Cut(line, " "); ok && name == key {
+			parsed, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+			return parsed, err == nil
+		}
+	}
+	return 0, false
+}
+
+// parseCPUMax converts a cpu.max file, "$MAX $PERIOD" in microseconds, into CPUs; zero when unlimited
+func parseCPUMax(data string) float64 {
+	fields := strings.Fields(data)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	quota, errQuota := strconv.ParseFloat(fields[0], 64)
+	period, errPeriod := strconv.ParseFloat(fields[1], 64)
+	if errQuota != nil || errPeriod != nil || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
+
+// parseLimit converts a limit file holding a number or "max" into the number, zero when unlimited
+func parseLimit(data string) uint64 {
+	limit, err := strconv.ParseUint(data, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return limit
+}
+
+// parseIOStat sums the bytes read and written over every device of an io.stat file,
+// lines like "8:0 rbytes=1459200 wbytes=314773504 rios=192 wios=353 dbytes=0 dios=0"
+func parseIOStat(data string) (read, written uint64) {
+	for _, line := range strings.Split(data, "\n") {
+		for _, field := range strings.Fields(line) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			parsed, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				read += parsed
+			case "wbytes":
+				written += parsed
+			}
+		}
+	}
+	return read, written
+}
@@ -0,0 +1,139 @@
+package collectors
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-dummy-monitor/utils"
+)
+
+func TestCgroupCollector(t *testing.T) {
+	root := t.TempDir()
+	writeCgroups := func(usec, rbytes string) {
+		writeSysfs(t, root, map[string]string{
+			"fs/cgroup/cgroup.controllers": "cpuset cpu io memory pids",
+			"fs/cgroup/cpu.stat":           "usage_usec 1000000\nuser_usec 600000\nsystem_usec 400000",
+			// A container limited to half a CPU, 256 MB and 100 tasks
+			"fs/cgroup/system.slice/docker-1.scope/cpu.stat":       "usage_usec " + usec,
+			"fs/cgroup/system.slice/docker-1.scope/cpu.max":        "50000 100000",
+			"fs/cgroup/system.slice/docker-1.scope/memory.current": "67108864",
+			"fs/cgroup/system.slice/docker-1.scope/memory.max":     "268435456",
+			"fs/cgroup/system.slice/docker-1.scope/io.stat":        "8:0 rbytes=" + rbytes + " wbytes=0 rios=1 wios=0\n259:0 rbytes=0 wbytes=4096",
+			"fs/cgroup/system.slice/docker-1.scope/pids.current":   "12",
+			"fs/cgroup/system.slice/docker-1.scope/pids.max":       "100",
+			// Unlimited slices report max
+			"fs/cgroup/user.slice/cpu.stat":       "usage_usec 5",
+			"fs/cgroup/user.slice/cpu.max":        "max 100000",
+			"fs/cgroup/user.slice/memory.current": "1024",
+			"fs/cgroup/user.slice/memory.max":     "max",
+			// Too deep to be walked
+			"fs/cgroup/user.slice/user-1000.slice/session-2.scope/app/cpu.stat": "usage_usec 1",
+		})
+	}
+	writeCgroups("0", "0")
+	// Cgroups without a CPU limit are relative to the host CPUs, not to those this process may use
+	writeSysfs(t, root, map[string]string{
+		"proc/cpuinfo": "processor\t: 0\n\nprocessor\t: 1\n\nprocessor\t: 2\n",
+		"proc/meminfo": "MemTotal:       4 kB\nMemFree:        1 kB",
+	})
+	t.Setenv(utils.EnvHostProc, filepath.Join(root, "proc"))
+	backend := NewProcfsBackend(filepath.Join(root, "proc"))
+	defer backend.Close()
+
+	collector := NewCgroupCollector(DefaultInterval, backend, root, DefaultCgroupDepth)
+	if _, err := collector.Collect(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Half a CPU used for ~100ms is the whole limit, 2 MB read in the same time is ~20 MB/s
+	time.Sleep(100 * time.Millisecond)
+	writeCgroups("50000", "2097152")
+	samples, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+	container := "/system.slice/docker-1.scope"
+	if cpu := values[CgroupMetric(container, CgroupCPU)]; cpu < 50 || cpu > 100 {
+		t.Errorf("Expected the container near its CPU limit, got %f%%", cpu)
+	}
+	if memory := values[CgroupMetric(container, CgroupMemory)]; memory != 25 {
+		t.Errorf("Expected the container at 25%% of its memory limit, got %f", memory)
+	}
+	if read := values[CgroupMetric(container, CgroupRead)]; read < 10 || read > 20 {
+		t.Errorf("Expected about 20 MB/s read, got %f", read)
+	}
+	// Without a limit the memory is relative to the host RAM of the backend
+	if memory := values[CgroupMetric("/user.slice", CgroupMemory)]; memory != 25 {
+		t.Errorf("Expected the unlimited slice at 25%% of the host RAM, got %f", memory)
+	}
+	if pids := values[CgroupMetric(container, CgroupPids)]; pids != 12 {
+		t.Errorf("Expected 12 pids, got %f", pids)
+	}
+	if collector.hostCPUs != 3 {
+		t.Errorf("Expected the 3 CPUs of the host procfs, got %d", collector.hostCPUs)
+	}
+
+	cgroups, ok := collector.Details().([]CgroupUsage)
+	if !ok {
+		t.Fatalf("Expected cgroup details, got %v", collector.Details())
+	}
+	var paths []string
+	for _, usage := range cgroups {
+		paths = append(paths, usage.Path)
+	}
+	// Directories without accounting files, and those too deep, are left out
+	expected := []string{"/", container, "/user.slice"}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected cgroups %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Expected cgroups %v, got %v", expected, paths)
+			break
+		}
+	}
+
+	limited, unlimited := cgroups[1], cgroups[2]
+	if limited.CPULimit != 0.5 || limited.MemoryMax != 268435456 || limited.PidsMax != 100 {
+		t.Errorf("Unexpected container limits %+v", limited)
+	}
+	if unlimited.CPULimit != 0 || unlimited.MemoryMax != 0 || unlimited.MemoryBytes != 1024 {
+		t.Errorf("Unexpected unlimited slice %+v", unlimited)
+	}
+}
+
+func TestCgroupUsagePercent(t *testing.T) {
+	usage := CgroupUsage{CPUCores: 1, MemoryBytes: 512}
+	if got := usage.CPUPercent(4); got != 25 {
+		t.Errorf("Expected 25%% of 4 CPUs without a limit, got %f", got)
+	}
+	if got := usage.MemoryPercent(2048); got != 25 {
+		t.Errorf("Expected 25%% of the host memory without a limit, got %f", got)
+	}
+	usage.CPULimit, usage.MemoryMax = 2, 1024
+	if got := usage.CPUPercent(4); got != 50 {
+		t.Errorf("Expected 50%% of a 2 CPU limit, got %f", got)
+	}
+	if got := usage.MemoryPercent(2048); math.Abs(got-50) > 1e-9 {
+		t.Errorf("Expected 50%% of the memory limit, got %f", got)
+	}
+}
+
+func TestCgroupCollectorWithoutV2(t *testing.T) {
+	root := t.TempDir()
+	// A cgroup v1 only machine has controller directories but no unified hierarchy
+	writeSysfs(t, root, map[string]string{"fs/cgroup/memory/memory.usage_in_bytes": "1024"})
+
+	samples, err := NewCgroupCollector(DefaultInterval, NewGopsutilBackend(), root, DefaultCgroupDepth).Collect(context.Background())
+	if err == nil || len(samples) != 0 {
+		t.Errorf("Expected an error and no samples without cgroup v2, got %v and %v", samples, err)
+	}
+}
@@ -0,0 +1,97 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-dummy-monitor/constants"
+	"go-dummy-monitor/utils"
+)
+
+// Metric names produced by the built-in collectors
+const (
+	MetricCPUUsage  = "cpu.usage"
+	MetricRAMUsage  = "ram.usage"
+	MetricDiskUsage = "disk.usage"
+	MetricDiskRead  = "disk.read"
+	MetricDiskWrite = "disk.write"
+	MetricNetRead   = "net.read"
+	MetricNetWrite  = "net.write"
+)
+
+// Metric names of the CPU time breakdown, each a percentage of total CPU time
+const (
+	MetricCPUUser    = "cpu.time.user"
+	MetricCPUSystem  = "cpu.time.system"
+	MetricCPUNice    = "cpu.time.nice"
+	MetricCPUIowait  = "cpu.time.iowait"
+	MetricCPUIrq     = "cpu.time.irq"
+	MetricCPUSoftirq = "cpu.time.softirq"
+	MetricCPUSteal   = "cpu.time.steal"
+	MetricCPUGuest   = "cpu.time.guest"
+)
+
+// CoreMetric returns the name of the utilization metric of one logical CPU
+func CoreMetric(core int) string {
+	return fmt.Sprintf("cpu.core.%d", core)
+}
+
+// DefaultInterval is the collection interval used by the built-in collectors
+const DefaultInterval = time.Millisecond * constants.STATS_UPDATE_INTERVAL
+
+// Sample is a single named measurement produced by a collector
+type Sample struct {
+	Name  string
+	Value float64
+}
+
+// Collector gathers a group of related metrics
+type Collector interface {
+	// Name returns a unique name identifying the collector
+	Name() string
+	// Interval returns how often the collector should run, zero means on every update
+	Interval() time.Duration
+	// Collect gathers the current samples
+	Collect(ctx context.Context) ([]Sample, error)
+}
+
+// DetailCollector is implemented by collectors that also publish structured data,
+// such as tables, alongside their samples
+type DetailCollector interface {
+	Collector
+	// Details returns the structured data of the latest collection; the returned
+	// value must not be modified afterwards, a new value is built on every collection
+	Details() any
+}
+
+// MetricOwner is implemented by collectors reporting a changing set of metrics under one prefix,
+// such as one per cgroup. Metrics under the prefix missing from a successful collection belong to
+// something that is gone.
+type MetricOwner interface {
+	Collector
+	// MetricPrefix returns the prefix of the metric names the collector owns
+	MetricPrefix() string
+}
+
+// Builtin returns the default set of collectors: CPU, Load, RAM, Disk, Filesystem, Network,
+// Connections, Processes, Sensors, Battery, Pressure and Cgroups. CPU, RAM, Disk and Network
+// share one backend, the one named by DefaultBackend. Cgroups read the RAM as well, concurrently
+// with the RAM collector, so they get a backend of their own.
+func Builtin() []Collector {
+	backend := NewDefaultBackend()
+	return []Collector{
+		NewCPUCollector(DefaultInterval, backend),
+		NewLoadCollector(DefaultInterval),
+		NewMemoryCollector(DefaultInterval, backend),
+		NewDiskCollector(DefaultInterval, backend),
+		NewFilesystemCollector(FilesystemInterval, BuiltinFilesystemFilter),
+		NewNetworkCollector(DefaultInterval, backend),
+		NewConnectionsCollector(DefaultInterval),
+		NewProcessCollector(ProcessInterval),
+		NewSensorsCollector(DefaultInterval, utils.HostSys()),
+		NewBatteryCollector(BatteryInterval, utils.HostSys()),
+		NewPressureCollector(DefaultInterval, utils.HostProc()),
+		NewCgroupCollector(DefaultInterval, NewDefaultBackend(), utils.HostSys(), DefaultCgroupDepth),
+	}
+}
@@ -0,0 +1,375 @@
+package collectors
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/net"
+	"github.com/shirou/gopsutil/process"
+
+	"go-dummy-monitor/utils"
+)
+
+// TCPStates lists the TCP connection states that are counted separately, in the order
+// of the state numbers of the kernel starting at 1
+var TCPStates = []string{
+	"ESTABLISHED", "SYN_SENT", "SYN_RECV", "FIN_WAIT1", "FIN_WAIT2", "TIME_WAIT",
+	"CLOSE", "CLOSE_WAIT", "LAST_ACK", "LISTEN", "CLOSING",
+}
+
+// Socket counts produced by the connections collector
+const (
+	MetricConnTCP       = "conn.tcp"       // all TCP sockets
+	MetricConnUDP       = "conn.udp"       // all UDP sockets
+	MetricConnListening = "conn.listening" // listening TCP and bound UDP sockets
+)
+
+// Socket types and address families as reported in psnet.ConnectionStat
+const (
+	sockStream = 1
+	sockDgram  = 2
+	afInet     = 2
+	afInet6    = 10
+)
+
+// procNetTables lists the socket tables of /proc/net with the sockets they hold
+var procNetTables = []struct {
+	file     string
+	sockType uint32
+	family   uint32
+}{
+	{"tcp", sockStream, afInet},
+	{"tcp6", sockStream, afInet6},
+	{"udp", sockDgram, afInet},
+	{"udp6", sockDgram, afInet6},
+}
+
+// TCPStateMetric returns the name of the socket count metric of one TCP state
+func TCPStateMetric(state string) string {
+	return "conn.tcp." + strings.ToLower(state)
+}
+
+// ListeningSocket describes a socket waiting for connections or datagrams
+type ListeningSocket struct {
+	Protocol string // tcp, tcp6, udp or udp6
+	Address  string
+	Port     uint32
+	PID      int32 // Zero when the owner cannot be read
+	Process  string
+}
+
+// ConnectionsCollector counts TCP sockets per state and lists listening sockets
+type ConnectionsCollector struct {
+	interval  time.Duration
+	procPath  string
+	listeners []ListeningSocket
+	owners    map[uint64]int32 // PIDs owning the listening sockets by inode, zero when unknown
+	names     map[int32]string
+}
+
+// NewConnectionsCollector creates a new ConnectionsCollector
+func NewConnectionsCollector(interval time.Duration) *ConnectionsCollector {
+	return &ConnectionsCollector{
+		interval: interval,
+		procPath: utils.HostProc(),
+		owners:   make(map[uint64]int32),
+		names:    make(map[int32]string),
+	}
+}
+
+// Name returns the collector name
+func (c *ConnectionsCollector) Name() string {
+	return "connections"
+}
+
+// Interval returns the collection interval
+func (c *ConnectionsCollector) Interval() time.Duration {
+	return c.interval
+}
+
+// Collect gathers socket counts per TCP state and the listening sockets with their owners
+func (c *ConnectionsCollector) Collect(ctx context.Context) ([]Sample, error) {
+	conns, err := c.connections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	samples, listeners := connectionSamples(conns)
+	c.resolveProcessNames(ctx, listeners)
+	c.listeners = listeners
+
+	return samples, nil
+}
+
+// Details returns the listening sockets of the latest collection sorted by port
+func (c *ConnectionsCollector) Details() any {
+	return c.listeners
+}
+
+// procSocket is one socket of a /proc/net table
+type procSocket struct {
+	conn  psnet.ConnectionStat
+	inode uint64
+}
+
+// connections returns every TCP and UDP socket. On Linux they are read from the socket tables of
+// the procfs, gopsutil would walk the descriptors of every process each time to find all owners.
+func (c *ConnectionsCollector) connections(ctx context.Context) ([]psnet.ConnectionStat, error) {
+	if runtime.GOOS != "linux" {
+		return psnet.ConnectionsWithContext(ctx, "inet")
+	}
+
+	var sockets []procSocket
+	var firstErr error
+	read := 0
+	for _, table := range procNetTables {
+		// The IPv6 tables are missing when IPv6 is disabled
+		data, err := os.ReadFile(filepath.Join(c.procPath, "net", table.file))
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		read++
+		sockets = append(sockets, parseProcNet(string(data), table.sockType, table.family)...)
+	}
+	if read == 0 {
+		return nil, firstErr
+	}
+
+	c.resolveOwners(ctx, sockets)
+	conns := make([]psnet.ConnectionStat, len(sockets))
+	for i, socket := range sockets {
+		conns[i] = socket.conn
+		conns[i].Pid = c.owners[socket.inode]
+	}
+	return conns, nil
+}
+
+// parseProcNet parses a socket table of /proc/net, lines like
+// "0: 0100007F:0277 00000000:0000 0A 00000000:00000000 00:00000000 00000000 0 0 12345 1 ..."
+func parseProcNet(data string, sockType, family uint32) []procSocket {
+	lines := strings.Split(data, "\n")
+	sockets := make([]procSocket, 0, len(lines))
+	// The first line holds the column names
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+		laddr, localOK := parseProcNetAddr(fields[1])
+		raddr, remoteOK := parseProcNetAddr(fields[2])
+		state, stateErr := strconv.ParseUint(fields[3], 16, 8)
+		inode, inodeErr := strconv.ParseUint(fields[9], 10, 64)
+		if !localOK || !remoteOK || stateErr != nil || inodeErr != nil {
+			continue
+		}
+
+		conn := psnet.ConnectionStat{Family: family, Type: sockType, Laddr: laddr, Raddr: raddr, Status: "NONE"}
+		if sockType == sockStream && state >= 1 && int(state) <= len(TCPStates) {
+			conn.Status = TCPStates[state-1]
+		}
+		sockets = append(sockets, procSocket{conn: conn, inode: inode})
+	}
+	return sockets
+}
+
+// parseProcNetAddr parses an address of a /proc/net table, the hex IP followed by the hex port,
+// e.g. "0100007F:0277" for 127.0.0.1:631
+func parseProcNetAddr(field string) (psnet.Addr, bool) {
+	ip, port, ok := strings.Cut(field, ":")
+	if !ok {
+		return psnet.Addr{}, false
+	}
+	parsedPort, err := strconv.ParseUint(port, 16, 16)
+	if err != nil {
+		return psnet.Addr{}, false
+	}
+	raw, err := hex.DecodeString(ip)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return psnet.Addr{}, false
+	}
+	// The IP is printed as 32-bit words in host byte order
+	for i := 0; i < len(raw); i += 4 {
+		binary.BigEndian.PutUint32(raw[i:], binary.NativeEndian.Uint32(raw[i:]))
+	}
+	return psnet.Addr{IP: net.IP(raw).String(), Port: uint32(parsedPort)}, true
+}
+
+// resolveOwners looks up the PIDs owning the listening sockets. That walks the descriptors of every
+// process, so it only happens when a listening socket shows up whose owner was not looked up yet.
+func (c *ConnectionsCollector) resolveOwners(ctx context.Context, sockets []procSocket) {
+	listening := make(map[uint64]bool)
+	unknown := false
+	for _, socket := range sockets {
+		if socket.inode == 0 || !isListening(socket.conn) {
+			continue
+		}
+		listening[socket.inode] = true
+		if _, ok := c.owners[socket.inode]; !ok {
+			unknown = true
+		}
+	}
+	for inode := range c.owners {
+		if !listening[inode] {
+			delete(c.owners, inode)
+		}
+	}
+	if !unknown {
+		return
+	}
+
+	found, complete := socketOwners(ctx, c.procPath, listening)
+	for inode := range listening {
+		if pid, ok := found[inode]; ok {
+			c.owners[inode] = pid
+		} else if _, ok := c.owners[inode]; !ok && complete {
+			// Owners that cannot be read, such as other users' without privileges, are not looked for again
+			c.owners[inode] = 0
+		}
+	}
+}
+
+// socketOwners walks the descriptors of every process below procPath for the sockets with the given
+// inodes, false when the deadline cut the walk short
+func socketOwners(ctx context.Context, procPath string, inodes map[uint64]bool) (map[uint64]int32, bool) {
+	owners := make(map[uint64]int32, len(inodes))
+	entries, err := os.ReadDir(procPath)
+	if err != nil {
+		return owners, true
+	}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return owners, false
+		}
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join(procPath, entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 64)
+			if err == nil && inodes[inode] {
+				owners[inode] = int32(pid)
+			}
+		}
+		if len(owners) == len(inodes) {
+			break
+		}
+	}
+	return owners, true
+}
+
+// isListening reports whether the socket waits for connections or datagrams
+func isListening(conn psnet.ConnectionStat) bool {
+	switch conn.Type {
+	case sockStream:
+		return conn.Status == "LISTEN"
+	case sockDgram:
+		// A UDP socket without a peer receives datagrams from anyone
+		return conn.Raddr.IP == "" || conn.Raddr.Port == 0
+	}
+	return false
+}
+
+// connectionSamples counts sockets per state and extracts the listening sockets
+func connectionSamples(conns []psnet.ConnectionStat) ([]Sample, []ListeningSocket) {
+	states := make(map[string]float64, len(TCPStates))
+	var tcp, udp float64
+	var listeners []ListeningSocket
+
+	for _, conn := range conns {
+		switch conn.Type {
+		case sockStream:
+			tcp++
+			states[conn.Status]++
+			if isListening(conn) {
+				listeners = append(listeners, listeningSocket("tcp", conn))
+			}
+		case sockDgram:
+			udp++
+			if isListening(conn) {
+				listeners = append(listeners, listeningSocket("udp", conn))
+			}
+		}
+	}
+
+	sort.Slice(listeners, func(i, j int) bool {
+		if listeners[i].Port != listeners[j].Port {
+			return listeners[i].Port < listeners[j].Port
+		}
+		return listeners[i].Protocol < listeners[j].Protocol
+	})
+
+	samples := make([]Sample, 0, len(TCPStates)+3)
+	for _, state := range TCPStates {
+		samples = append(samples, Sample{Name: TCPStateMetric(state), Value: states[state]})
+	}
+	return append(samples,
+		Sample{Name: MetricConnTCP, Value: tcp},
+		Sample{Name: MetricConnUDP, Value: udp},
+		Sample{Name: MetricConnListening, Value: float64(len(listeners))},
+	), listeners
+}
+
+// listeningSocket converts a connection into a listening socket entry
+func listeningSocket(protocol string, conn psnet.ConnectionStat) ListeningSocket {
+	if conn.Family != afInet {
+		protocol += "6"
+	}
+	return ListeningSocket{
+		Protocol: protocol,
+		Address:  conn.Laddr.IP,
+		Port:     conn.Laddr.Port,
+		PID:      conn.Pid,
+	}
+}
+
+// resolveProcessNames fills in the owning process names, keeping names of PIDs that are still seen
+func (c *ConnectionsCollector) resolveProcessNames(ctx context.Context, listeners []ListeningSocket) {
+	seen := make(map[int32]bool, len(listeners))
+	for i := range listeners {
+		// Past the deadline the remaining sockets are listed without their owner
+		if ctx.Err() != nil {
+			return
+		}
+		pid := listeners[i].PID
+		if pid <= 0 {
+			continue
+		}
+		seen[pid] = true
+
+		name, ok := c.names[pid]
+		if !ok {
+			if proc, err := process.NewProcessWithContext(ctx, pid); err == nil {
+				name, _ = proc.NameWithContext(ctx)
+			}
+			c.names[pid] = name
+		}
+		listeners[i].Process = name
+	}
+
+	for pid := range c.names {
+		if !seen[pid] {
+			delete(c.names, pid)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	psnet "github.com/shirou/gopsutil/net"
+)
+
+func TestConnectionSamples(t *testing.T) {
+	conns := []psnet.ConnectionStat{
+		{Type: sockStream, Family: afInet, Status: "ESTABLISHED", Laddr: psnet.Addr{IP: "10.0.0.2", Port: 40000}, Raddr: psnet.Addr{IP: "10.0.0.1", Port: 443}},
+		{Type: sockStream, Family: afInet, Status: "CLOSE_WAIT", Laddr: psnet.Addr{IP: "10.0.0.2", Port: 40001}, Raddr: psnet.Addr{IP: "10.0.0.1", Port: 443}},
+		{Type: sockStream, Family: afInet, Status: "CLOSE_WAIT", Laddr: psnet.Addr{IP: "10.0.0.2", Port: 40002}, Raddr: psnet.Addr{IP: "10.0.0.1", Port: 443}},
+		{Type: sockStream, Family: 10, Status: "LISTEN", Laddr: psnet.Addr{IP: "::", Port: 8080}, Pid: 42},
+		{Type: sockStream, Family: afInet, Status: "LISTEN", Laddr: psnet.Addr{IP: "0.0.0.0", Port: 22}, Pid: 1},
+		{Type: sockDgram, Family: afInet, Status: "NONE", Laddr: psnet.Addr{IP: "0.0.0.0", Port: 53}},
+		{Type: sockDgram, Family: afInet, Status: "NONE", Laddr: psnet.Addr{IP: "10.0.0.2", Port: 5353}, Raddr: psnet.Addr{IP: "10.0.0.1", Port: 53}},
+	}
+
+	samples, listeners := connectionSamples(conns)
+
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+
+	expected := map[string]float64{
+		TCPStateMetric("ESTABLISHED"): 1,
+		TCPStateMetric("CLOSE_WAIT"):  2,
+		TCPStateMetric("LISTEN"):      2,
+		TCPStateMetric("TIME_WAIT"):   0,
+		MetricConnTCP:                 5,
+		MetricConnUDP:                 2,
+		MetricConnListening:           3,
+	}
+	for name, want := range expected {
+		if got, ok := values[name]; !ok || got != want {
+			t.Errorf("Expected %s to be %f, got %f (present %v)", name, want, got, ok)
+		}
+	}
+
+	// Listeners are sorted by port, IPv6 sockets are marked as such
+	if len(listeners) != 3 {
+		t.Fatalf("Expected 3 listeners, got %d", len(listeners))
+	}
+	if listeners[0].Port != 22 || listeners[1].Protocol != "udp" || listeners[2].Protocol != "tcp6" || listeners[2].PID != 42 {
+		t.Errorf("Unexpected listeners %+v", listeners)
+	}
+}
+
+// procNetHeader is the first line of the /proc/net socket tables
+const procNetHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+func TestParseProcNet(t *testing.T) {
+	tcp := parseProcNet(procNetHeader+
+		"   0: 0100007F:0277 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 12345 1 0 100 0 0 10 0\n"+
+		"   1: 0200000A:9C40 0100000A:01BB 01 00000000:00000000 02:00000A3B 00000000  1000        0 23456 2 0 20 4 30 10 -1\n"+
+		"   2: garbage\n", sockStream, afInet)
+	if len(tcp) != 2 {
+		t.Fatalf("Expected 2 sockets, got %+v", tcp)
+	}
+	if tcp[0].conn.Laddr != (psnet.Addr{IP: "127.0.0.1", Port: 631}) || tcp[0].conn.Status != "LISTEN" || tcp[0].inode != 12345 {
+		t.Errorf("Unexpected listening socket %+v", tcp[0])
+	}
+	if tcp[1].conn.Raddr != (psnet.Addr{IP: "10.0.0.1", Port: 443}) || tcp[1].conn.Status != "ESTABLISHED" {
+		t.Errorf("Unexpected established socket %+v", tcp[1])
+	}
+
+	udp6 := parseProcNet(procNetHeader+
+		"  0: 00000000000000000000000001000000:14E9 00000000000000000000000000000000:0000 07 00000000:00000000 00:00000000 00000000   0  0 34567 2 0 0\n",
+		sockDgram, afInet6)
+	if len(udp6) != 1 || udp6[0].conn.Laddr != (psnet.Addr{IP: "::1", Port: 5353}) || !isListening(udp6[0].conn) {
+		t.Errorf("Unexpected UDP6 sockets %+v", udp6)
+	}
+}
+
+func TestConnectionsCollectorProcfs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the socket tables are only read from /proc on linux")
+	}
+
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"net/tcp": procNetHeader +
+			"   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 12345 1 0 100 0 0 10 0\n" +
+			"   1: 0200000A:9C40 0100000A:01BB 06 00000000:00000000 02:00000A3B 00000000  1000        0 0 2 0 20 4 30 10 -1",
+		"net/udp": procNetHeader,
+	})
+	// The test process owns the SSH socket, so the owner found is a running process
+	pid := strconv.Itoa(os.Getpid())
+	if err := os.MkdirAll(filepath.Join(root, pid, "fd"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for fd, target := range map[string]string{"0": "/dev/null", "3": "socket:[999]", "4": "socket:[12345]"} {
+		if err := os.Symlink(target, filepath.Join(root, pid, "fd", fd)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collector := NewConnectionsCollector(DefaultInterval)
+	collector.procPath = root
+	samples, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the sockets without the IPv6 tables, got %v", err)
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+	if values[TCPStateMetric("LISTEN")] != 1 || values[TCPStateMetric("TIME_WAIT")] != 1 || values[MetricConnUDP] != 0 {
+		t.Errorf("Unexpected socket counts %v", values)
+	}
+
+	listeners, _ := collector.Details().([]ListeningSocket)
+	if len(listeners) != 1 || listeners[0].Port != 22 || listeners[0].PID != int32(os.Getpid()) {
+		t.Errorf("Expected the SSH socket owned by the test process, got %+v", listeners)
+	}
+	if collector.owners[12345] != int32(os.Getpid()) {
+		t.Errorf("Expected the owner to be kept for the next collection, got %v", collector.owners)
+	}
+
+	collector.procPath = filepath.Join(root, "missing")
+	if _, err := collector.Collect(context.Background()); err == nil {
+		t.Error("Expected an error without any socket table")
+	}
+}
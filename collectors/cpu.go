@@ -0,0 +1,109 @@
+package collectors
+
+import (
+	"context"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+)
+
+// CPUCollector collects the aggregate and per-core CPU utilization and the CPU time breakdown
+type CPUCollector struct {
+	interval  time.Duration
+	backend   Backend
+	prevTimes *cpu.TimesStat
+	prevCores []cpu.TimesStat
+}
+
+// NewCPUCollector creates a new CPUCollector reading the CPU times from backend
+func NewCPUCollector(interval time.Duration, backend Backend) *CPUCollector {
+	return &CPUCollector{interval: interval, backend: backend}
+}
+
+// Name returns the collector name
+func (c *CPUCollector) Name() string {
+	return "cpu"
+}
+
+// Interval returns the collection interval
+func (c *CPUCollector) Interval() time.Duration {
+	return c.interval
+}
+
+// Collect gathers the total and per logical CPU usage percentages since the previous call,
+// or since boot on the first call
+func (c *CPUCollector) Collect(ctx context.Context) ([]Sample, error) {
+	total, cores, err := c.backend.CPUTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var prevTotal cpu.TimesStat
+	if c.prevTimes != nil {
+		prevTotal = *c.prevTimes
+	}
+
+	samples := make([]Sample, 0, 1+len(cores)+8)
+	samples = append(samples, Sample{Name: MetricCPUUsage, Value: cpuBusyPercent(prevTotal, total)})
+	for core, times := range cores {
+		var prev cpu.TimesStat
+		if core < len(c.prevCores) {
+			prev = c.prevCores[core]
+		}
+		samples = append(samples, Sample{Name: CoreMetric(core), Value: cpuBusyPercent(prev, times)})
+	}
+
+	if c.prevTimes != nil {
+		samples = append(samples, cpuTimeSamples(*c.prevTimes, total)...)
+	}
+	c.prevTimes = &total
+	// The backend reuses its slice, so keep a copy of the readings
+	c.prevCores = append(c.prevCores[:0], cores...)
+
+	return samples, nil
+}
+
+// cpuBusyPercent returns the percentage of CPU time between two readings not spent idle, as gopsutil does
+func cpuBusyPercent(prev, cur cpu.TimesStat) float64 {
+	busy := func(t cpu.TimesStat) float64 {
+		return t.User + t.System + t.Nice + t.Iowait + t.Irq + t.Softirq + t.Steal
+	}
+
+	busyDelta := busy(cur) - busy(prev)
+	totalDelta := busyDelta + cur.Idle - prev.Idle
+	if busyDelta <= 0 {
+		return 0
+	}
+	if totalDelta <= 0 {
+		return 100
+	}
+	return min(100, busyDelta/totalDelta*100)
+}
+
+// cpuTimeSamples converts the difference between two CPU time readings into percentages
+func cpuTimeSamples(prev, cur cpu.TimesStat) []Sample {
+	// Total excludes guest time, which the kernel already accounts for in user time
+	total := cur.Total() - prev.Total()
+	if total <= 0 {
+		return nil
+	}
+
+	percent := func(curValue, prevValue float64) float64 {
+		delta := curValue - prevValue
+		if delta < 0 {
+			return 0
+		}
+		return delta / total * 100
+	}
+
+	return []Sample{
+		{Name: MetricCPUUser, Value: percent(cur.User, prev.User)},
+		{Name: MetricCPUSystem, Value: percent(cur.System, prev.System)},
+		{Name: MetricCPUNice, Value: percent(cur.Nice, prev.Nice)},
+		{Name: MetricCPUIowait, Value: percent(cur.Iowait, prev.Iowait)},
+		{Name: MetricCPUIrq, Value: percent(cur.Irq, prev.Irq)},
+		{Name: MetricCPUSoftirq, Value: percent(cur.Softirq, prev.Softirq)},
+		{Name: MetricCPUSteal, Value: percent(cur.Steal, prev.Steal)},
+		{Name: MetricCPUGuest, Value: percent(cur.Guest, prev.Guest)},
+	}
+}
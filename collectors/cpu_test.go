@@ -0,0 +1,78 @@
+package collectors
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/shirou/gopsutil/cpu"
+)
+
+func TestCPUTimeSamples(t *testing.T) {
+	prev := cpu.TimesStat{User: 100, System: 50, Idle: 800, Iowait: 10, Steal: 5, Guest: 20}
+	cur := cpu.TimesStat{User: 130, System: 60, Idle: 850, Iowait: 15, Steal: 10, Guest: 30}
+
+	samples := cpuTimeSamples(prev, cur)
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+
+	// Total delta: 30 user + 10 system + 50 idle + 5 iowait + 5 steal = 100
+	expected := map[string]float64{
+		MetricCPUUser:   30,
+		MetricCPUSystem: 10,
+		MetricCPUIowait: 5,
+		MetricCPUSteal:  5,
+		MetricCPUGuest:  10,
+		MetricCPUNice:   0,
+	}
+	for name, want := range expected {
+		if got := values[name]; got != want {
+			t.Errorf("Expected %s to be %.1f%%, got %.1f%%", name, want, got)
+		}
+	}
+}
+
+func TestCPUTimeSamplesNoElapsedTime(t *testing.T) {
+	times := cpu.TimesStat{User: 100, Idle: 800}
+
+	if samples := cpuTimeSamples(times, times); samples != nil {
+		t.Errorf("Expected no samples without elapsed CPU time, got %v", samples)
+	}
+}
+
+func TestCPUCollector(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{"stat": "cpu  100 0 100 800 0 0 0 0 0 0\ncpu0 100 0 100 800 0 0 0 0 0 0"})
+	collector := NewCPUCollector(DefaultInterval, NewProcfsBackend(root))
+
+	values := func() map[string]float64 {
+		samples, err := collector.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		values := make(map[string]float64)
+		for _, sample := range samples {
+			values[sample.Name] = sample.Value
+		}
+		return values
+	}
+
+	// The first collection reports the usage since boot, without a time breakdown
+	first := values()
+	if first[MetricCPUUsage] != 20 || first[CoreMetric(0)] != 20 {
+		t.Errorf("Expected 20%% since boot, got %v", first)
+	}
+	if _, ok := first[MetricCPUUser]; ok {
+		t.Error("Expected no time breakdown on the first collection")
+	}
+
+	// 60 busy out of 100 ticks since the previous collection
+	writeSysfs(t, root, map[string]string{"stat": "cpu  150 0 110 840 0 0 0 0 0 0\ncpu0 150 0 110 840 0 0 0 0 0 0"})
+	second := values()
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
+	if !near(second[MetricCPUUsage], 60) || !near(second[CoreMetric(0)], 60) || !near(second[MetricCPUUser], 50) {
+		t.Errorf("Expected 60%% since the previous collection, got %v", second)
+	}
+}
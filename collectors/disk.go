@@ -0,0 +1,251 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/disk"
+
+	"go-dummy-monitor/utils"
+)
+
+// DiskDevicePrefix starts the names of all per-device disk metrics
+const DiskDevicePrefix = "disk.device."
+
+// Kinds of disk metrics, each reported in total as "disk.<kind>" and per device
+const (
+	DiskRead      = "read"       // MB/s
+	DiskWrite     = "write"      // MB/s
+	DiskReadIOPS  = "read_iops"  // operations/s
+	DiskWriteIOPS = "write_iops" // operations/s
+	DiskAwait     = "await"      // average milliseconds per operation
+	DiskQueue     = "queue"      // average number of operations in flight
+	DiskUtil      = "util"       // percentage of time the device was busy
+)
+
+// diskMetricKinds lists the disk metric kinds in reporting order
+var diskMetricKinds = []string{DiskRead, DiskWrite, DiskReadIOPS, DiskWriteIOPS, DiskAwait, DiskQueue, DiskUtil}
+
+// Total disk metrics not covered by the built-in metric names
+const (
+	MetricDiskReadIOPS  = "disk." + DiskReadIOPS
+	MetricDiskWriteIOPS = "disk." + DiskWriteIOPS
+	MetricDiskAwait     = "disk." + DiskAwait
+	MetricDiskQueue     = "disk." + DiskQueue
+	MetricDiskUtil      = "disk." + DiskUtil
+)
+
+// DiskDeviceMetric returns the name of a metric of the given kind for one device
+func DiskDeviceMetric(device, kind string) string {
+	return DiskDevicePrefix + device + "." + kind
+}
+
+// DiskDeviceReadMetric returns the name of the read throughput metric of one device
+func DiskDeviceReadMetric(device string) string {
+	return DiskDeviceMetric(device, DiskRead)
+}
+
+// DiskDeviceWriteMetric returns the name of the write throughput metric of one device
+func DiskDeviceWriteMetric(device string) string {
+	return DiskDeviceMetric(device, DiskWrite)
+}
+
+// DiskDevices returns the sorted device names found among the given metric names
+func DiskDevices(metricNames []string) []string {
+	var devices []string
+	for _, name := range metricNames {
+		if strings.HasPrefix(name, DiskDevicePrefix) && strings.HasSuffix(name, ".read") {
+			devices = append(devices, strings.TrimSuffix(strings.TrimPrefix(name, DiskDevicePrefix), ".read"))
+		}
+	}
+	sort.Strings(devices)
+	return devices
+}
+
+// virtualDiskPattern matches devices that duplicate or do not represent physical disk I/O
+var virtualDiskPattern = regexp.MustCompile(`^(loop|ram|zram|dm-|md|sr|fd)\d*$`)
+
+// partitionPattern matches partitions of sd/hd/vd/xvd disks, nvme and mmc namespaces and darwin slices
+var partitionPattern = regexp.MustCompile(`^((s|h|v|xv)d[a-z]+\d+|(nvme\d+n\d+|mmcblk\d+)p\d+|disk\d+s\d+)$`)
+
+// DiskCollector collects root filesystem usage and disk throughput
+type DiskCollector struct {
+	interval     time.Duration
+	backend      Backend
+	rates        *CounterRate
+	sysBlockPath string
+	physical     map[string]bool
+}
+
+// NewDiskCollector creates a new DiskCollector reading the I/O counters from backend
+func NewDiskCollector(interval time.Duration, backend Backend) *DiskCollector {
+	return &DiskCollector{
+		interval:     interval,
+		backend:      backend,
+		rates:        NewCounterRate(),
+		sysBlockPath: utils.HostSys("block"),
+		physical:     make(map[string]bool),
+	}
+}
+
+// Name returns the collector name
+func (d *DiskCollector) Name() string {
+	return "disk"
+}
+
+// Interval returns the collection interval
+func (d *DiskCollector) Interval() time.Duration {
+	return d.interval
+}
+
+// MetricPrefix returns the prefix of the per-device metrics, those of removed devices are dropped
+func (d *DiskCollector) MetricPrefix() string {
+	return DiskDevicePrefix
+}
+
+// Collect gathers root filesystem usage plus throughput, IOPS, latency, queue depth
+// and utilization of every physical disk and of all physical disks together
+func (d *DiskCollector) Collect(ctx context.Context) ([]Sample, error) {
+	ioStats, err := d.backend.DiskIOCounters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	// Walk devices in a stable order so samples are reported consistently
+	devices := make([]disk.IOCountersStat, 0, len(ioStats))
+	present := make(map[string]bool, len(ioStats))
+	for _, stats := range ioStats {
+		present[stats.Name] = true
+		if d.isPhysicalDisk(stats.Name) {
+			devices = append(devices, stats)
+		}
+	}
+	// A device name may come back for another disk, such as the next USB stick plugged in
+	for name := range d.physical {
+		if !present[name] {
+			delete(d.physical, name)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Name < devices[j].Name })
+
+	samples := make([]Sample, 0, 8+len(devices)*7)
+	var total diskActivity
+
+	seen := make(map[string]bool, len(devices)*7)
+	for _, stats := range devices {
+		activity := d.deviceActivity(stats.Name, stats, now, seen)
+		total.add(activity)
+
+		values := activity.values()
+		for _, kind := range diskMetricKinds {
+			samples = append(samples, Sample{Name: DiskDeviceMetric(stats.Name, kind), Value: values[kind]})
+		}
+	}
+	d.rates.Forget(seen)
+
+	// An unreadable root filesystem only leaves its usage untouched, the I/O rates are still reported
+	if usage, err := disk.UsageWithContext(ctx, utils.HostRoot()); err == nil {
+		samples = append(samples, Sample{Name: MetricDiskUsage, Value: usage.UsedPercent})
+	}
+	values := total.values()
+	for _, kind := range diskMetricKinds {
+		samples = append(samples, Sample{Name: "disk." + kind, Value: values[kind]})
+	}
+	return samples, nil
+}
+
+// diskActivity holds the per-second rates of one device, or the sum over several devices
+type diskActivity struct {
+	readBytes, writeBytes float64
+	readOps, writeOps     float64
+	opTime                float64 // milliseconds spent on operations per second
+	weightedTime          float64 // milliseconds weighted by operations in flight per second
+	busyTime              float64 // milliseconds the device was busy per second
+}
+
+// deviceActivity updates the counters of one device and returns its rates
+func (d *DiskCollector) deviceActivity(name string, stats disk.IOCountersStat, now time.Time, seen map[string]bool) diskActivity {
+	rate := func(counter string, value uint64) float64 {
+		key := name + "." + counter
+		seen[key] = true
+		r, _ := d.rates.Rate(key, value, now)
+		return r
+	}
+
+	return diskActivity{
+		readBytes:    rate("read", stats.ReadBytes),
+		writeBytes:   rate("write", stats.WriteBytes),
+		readOps:      rate("reads", stats.ReadCount),
+		writeOps:     rate("writes", stats.WriteCount),
+		opTime:       rate("readtime", stats.ReadTime) + rate("writetime", stats.WriteTime),
+		weightedTime: rate("weighted", stats.WeightedIO),
+		busyTime:     rate("iotime", stats.IoTime),
+	}
+}
+
+// add accumulates the activity of another device; utilization keeps the busiest device
+// since the percentage of a set of disks running in parallel is not a sum
+func (a *diskActivity) add(other diskActivity) {
+	a.readBytes += other.readBytes
+	a.writeBytes += other.writeBytes
+	a.readOps += other.readOps
+	a.writeOps += other.writeOps
+	a.opTime += other.opTime
+	a.weightedTime += other.weightedTime
+	if other.busyTime > a.busyTime {
+		a.busyTime = other.busyTime
+	}
+}
+
+// values converts the activity into metric values keyed by disk metric kind
+func (a diskActivity) values() map[string]float64 {
+	await := 0.0
+	if ops := a.readOps + a.writeOps; ops > 0 {
+		await = a.opTime / ops
+	}
+
+	util := a.busyTime / 1000 * 100
+	if util > 100 {
+		util = 100
+	}
+
+	return map[string]float64{
+		DiskRead:      a.readBytes / bytesPerMB,
+		DiskWrite:     a.writeBytes / bytesPerMB,
+		DiskReadIOPS:  a.readOps,
+		DiskWriteIOPS: a.writeOps,
+		DiskAwait:     await,
+		DiskQueue:     a.weightedTime / 1000,
+		DiskUtil:      util,
+	}
+}
+
+// isPhysicalDisk reports whether the device is a whole physical disk rather than
+// a partition or a virtual device whose I/O is already counted on another device
+func (d *DiskCollector) isPhysicalDisk(name string) bool {
+	if physical, ok := d.physical[name]; ok {
+		return physical
+	}
+
+	physical := !virtualDiskPattern.MatchString(name) && !partitionPattern.MatchString(name)
+
+	// On Linux only whole disks backed by hardware have a device link in sysfs, optical drives
+	// such as sr0 have one too and are still left out by name
+	if runtime.GOOS == "linux" {
+		if _, err := os.Stat(d.sysBlockPath); err == nil {
+			_, err := os.Stat(filepath.Join(d.sysBlockPath, name, "device"))
+			physical = physical && err == nil
+		}
+	}
+
+	d.physical[name] = physical
+	return physical
+}
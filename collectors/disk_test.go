@@ -0,0 +1,192 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"go-dummy-monitor/utils"
+)
+
+func TestIsPhysicalDiskByName(t *testing.T) {
+	collector := NewDiskCollector(DefaultInterval, NewGopsutilBackend())
+	collector.sysBlockPath = filepath.Join(t.TempDir(), "missing")
+
+	cases := map[string]bool{
+		"sda":       true,
+		"sda1":      false,
+		"nvme0n1":   true,
+		"nvme0n1p2": false,
+		"mmcblk0":   true,
+		"mmcblk0p1": false,
+		"vda":       true,
+		"vda3":      false,
+		"disk0":     true,
+		"disk0s1":   false,
+		"loop0":     false,
+		"dm-0":      false,
+		"md127":     false,
+		"zram0":     false,
+		"sr0":       false,
+		"mdxyz":     true,
+	}
+	for name, want := range cases {
+		if got := collector.isPhysicalDisk(name); got != want {
+			t.Errorf("Expected isPhysicalDisk(%q) to be %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestIsPhysicalDiskBySysfs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("sysfs is only consulted on linux")
+	}
+
+	root := t.TempDir()
+	for _, dir := range []string{"sda/device", "nvme0n1/device", "sr0/device", "dm-0", "loop0"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collector := NewDiskCollector(DefaultInterval, NewGopsutilBackend())
+	collector.sysBlockPath = root
+
+	cases := map[string]bool{
+		"sda":     true,
+		"sda1":    false,
+		"nvme0n1": true,
+		"sr0":     false,
+		"dm-0":    false,
+		"loop0":   false,
+	}
+	for name, want := range cases {
+		if got := collector.isPhysicalDisk(name); got != want {
+			t.Errorf("Expected isPhysicalDisk(%q) to be %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestDiskDevices(t *testing.T) {
+	names := []string{
+		MetricDiskRead,
+		DiskDeviceWriteMetric("sdb"),
+		DiskDeviceReadMetric("sdb"),
+		DiskDeviceReadMetric("nvme0n1"),
+		DiskDeviceWriteMetric("nvme0n1"),
+	}
+
+	devices := DiskDevices(names)
+	if len(devices) != 2 || devices[0] != "nvme0n1" || devices[1] != "sdb" {
+		t.Errorf("Expected [nvme0n1 sdb], got %v", devices)
+	}
+}
+
+func TestDiskActivityValues(t *testing.T) {
+	activity := diskActivity{
+		readBytes:    2 * bytesPerMB,
+		writeBytes:   bytesPerMB,
+		readOps:      30,
+		writeOps:     10,
+		opTime:       80,
+		weightedTime: 500,
+		busyTime:     250,
+	}
+
+	values := activity.values()
+	expected := map[string]float64{
+		DiskRead:      2,
+		DiskWrite:     1,
+		DiskReadIOPS:  30,
+		DiskWriteIOPS: 10,
+		DiskAwait:     2,
+		DiskQueue:     0.5,
+		DiskUtil:      25,
+	}
+	for kind, want := range expected {
+		if got := values[kind]; got != want {
+			t.Errorf("Expected %s to be %f, got %f", kind, want, got)
+		}
+	}
+}
+
+func TestDiskActivityAdd(t *testing.T) {
+	var total diskActivity
+	total.add(diskActivity{readOps: 10, opTime: 10, busyTime: 900})
+	total.add(diskActivity{readOps: 30, opTime: 110, busyTime: 300})
+
+	values := total.values()
+	if values[DiskReadIOPS] != 40 {
+		t.Errorf("Expected summed IOPS of 40, got %f", values[DiskReadIOPS])
+	}
+	if values[DiskAwait] != 3 {
+		t.Errorf("Expected overall await of 3ms, got %f", values[DiskAwait])
+	}
+	if values[DiskUtil] != 90 {
+		t.Errorf("Expected utilization of the busiest disk (90%%), got %f", values[DiskUtil])
+	}
+}
+
+func TestDiskCollectorWithoutRootUsage(t *testing.T) {
+	root := t.TempDir()
+	writeProcfs(t, root)
+	backend := NewProcfsBackend(root)
+	defer backend.Close()
+	t.Setenv(utils.EnvHostRoot, filepath.Join(root, "missing"))
+
+	collector := NewDiskCollector(DefaultInterval, backend)
+	collector.sysBlockPath = filepath.Join(root, "missing")
+	samples, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the I/O samples despite the unreadable root, got %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, sample := range samples {
+		names[sample.Name] = true
+	}
+	if names[MetricDiskUsage] || !names[MetricDiskRead] || !names[DiskDeviceReadMetric("sda")] {
+		t.Errorf("Expected the I/O samples without the usage, got %v", samples)
+	}
+}
+
+func TestRemovedDisksExpire(t *testing.T) {
+	root := t.TempDir()
+	writeProcfs(t, root)
+	writeSysfs(t, root, map[string]string{
+		"diskstats": "   8       0 sda 100 5 2048 300 50 2 1024 400 1 500 700 0 0 0 0 0 0\n" +
+			"   8      16 sdb 10 0 64 3 5 0 32 4 0 5 7 0 0 0 0 0 0",
+	})
+	backend := NewProcfsBackend(root)
+	defer backend.Close()
+
+	collector := NewDiskCollector(DefaultInterval, backend)
+	collector.sysBlockPath = filepath.Join(root, "missing")
+	registry := NewRegistry()
+	_ = registry.Register(collector)
+	start := time.Now()
+	if _, err := registry.CollectDue(context.Background(), start); err != nil {
+		t.Fatal(err)
+	}
+
+	// The USB disk is unplugged
+	writeSysfs(t, root, map[string]string{
+		"diskstats": "   8       0 sda 100 5 2048 300 50 2 1024 400 1 500 700 0 0 0 0 0 0",
+	})
+	if _, err := registry.CollectDue(context.Background(), start.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !registry.Expired(DiskDeviceReadMetric("sdb")) {
+		t.Error("Expected the metrics of the removed disk to expire")
+	}
+	if registry.Expired(DiskDeviceReadMetric("sda")) || registry.Expired(MetricDiskRead) {
+		t.Error("Expected the metrics of the remaining disk and the totals to be kept")
+	}
+	if _, ok := collector.physical["sdb"]; ok {
+		t.Error("Expected the removed disk to be forgotten")
+	}
+}
@@ -0,0 +1,202 @@
+package collectors
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/disk"
+
+	"go-dummy-monitor/utils"
+)
+
+// FilesystemInterval is how often mounted filesystems are re-read, usage changes slowly
+const FilesystemInterval = 5 * time.Second
+
+// FilesystemPrefix starts the metric names of every mounted filesystem
+const FilesystemPrefix = "fs."
+
+// DefaultExcludedFstypes lists pseudo filesystems that hold no user data
+var DefaultExcludedFstypes = []string{
+	"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs",
+	"devpts", "devtmpfs", "efivarfs", "fusectl", "hugetlbfs", "mqueue", "nsfs",
+	"proc", "pstore", "ramfs", "rpc_pipefs", "securityfs", "squashfs", "sysfs",
+	"tmpfs", "tracefs",
+}
+
+// DefaultExcludedMountPrefixes lists mount point trees that only hold pseudo filesystems
+var DefaultExcludedMountPrefixes = []string{"/proc", "/sys", "/dev"}
+
+// FilesystemUsage describes the space and inode usage of one mounted filesystem
+type FilesystemUsage struct {
+	Mountpoint        string
+	Device            string
+	Fstype            string
+	Total             uint64
+	Used              uint64
+	Free              uint64
+	UsedPercent       float64
+	InodesTotal       uint64
+	InodesUsed        uint64
+	InodesUsedPercent float64
+}
+
+// FilesystemFilter decides which mounted filesystems are reported
+type FilesystemFilter struct {
+	IncludeFstypes       []string // When set, only these filesystem types are reported
+	ExcludeFstypes       []string
+	ExcludeMountPrefixes []string
+}
+
+// DefaultFilesystemFilter returns a filter that skips pseudo filesystems
+func DefaultFilesystemFilter() FilesystemFilter {
+	return FilesystemFilter{
+		ExcludeFstypes:       DefaultExcludedFstypes,
+		ExcludeMountPrefixes: DefaultExcludedMountPrefixes,
+	}
+}
+
+// BuiltinFilesystemFilter decides which filesystems the collector created by Builtin reports
+var BuiltinFilesystemFilter = DefaultFilesystemFilter()
+
+// WithFstypes returns the filter reporting only the included types when any are given, and leaving out
+// the excluded ones too. An included type is reported even when the filter left it out before.
+func (f FilesystemFilter) WithFstypes(include, exclude []string) FilesystemFilter {
+	filtered := FilesystemFilter{IncludeFstypes: include, ExcludeMountPrefixes: f.ExcludeMountPrefixes}
+	for _, fstype := range f.ExcludeFstypes {
+		if !containsString(include, fstype) {
+			filtered.ExcludeFstypes = append(filtered.ExcludeFstypes, fstype)
+		}
+	}
+	filtered.ExcludeFstypes = append(filtered.ExcludeFstypes, exclude...)
+	return filtered
+}
+
+// Matches reports whether a filesystem passes the include and exclude rules
+func (f FilesystemFilter) Matches(fstype, mountpoint string) bool {
+	if len(f.IncludeFstypes) > 0 && !containsString(f.IncludeFstypes, fstype) {
+		return false
+	}
+	if containsString(f.ExcludeFstypes, fstype) {
+		return false
+	}
+	for _, prefix := range f.ExcludeMountPrefixes {
+		if mountpoint == prefix || strings.HasPrefix(mountpoint, prefix+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// FilesystemCollector collects usage of every mounted filesystem
+type FilesystemCollector struct {
+	interval    time.Duration
+	filter      FilesystemFilter
+	filesystems []FilesystemUsage
+}
+
+// NewFilesystemCollector creates a new FilesystemCollector
+func NewFilesystemCollector(interval time.Duration, filter FilesystemFilter) *FilesystemCollector {
+	return &FilesystemCollector{
+		interval: interval,
+		filter:   filter,
+	}
+}
+
+// Name returns the collector name
+func (f *FilesystemCollector) Name() string {
+	return "filesystem"
+}
+
+// Interval returns the collection interval
+func (f *FilesystemCollector) Interval() time.Duration {
+	return f.interval
+}
+
+// MetricPrefix returns the prefix of the filesystem metrics, those of unmounted filesystems are dropped
+func (f *FilesystemCollector) MetricPrefix() string {
+	return FilesystemPrefix
+}
+
+// Collect gathers the usage of every mounted filesystem passing the filter
+func (f *FilesystemCollector) Collect(ctx context.Context) ([]Sample, error) {
+	partitions, err := disk.PartitionsWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := f.visibleMounts(partitions)
+	filesystems := make([]FilesystemUsage, 0, len(visible))
+	for _, partition := range visible {
+		// Mount points are host paths, found below the host root when monitoring from a container
+		usage, err := disk.UsageWithContext(ctx, utils.HostRoot(partition.Mountpoint))
+		if err != nil || usage.Total == 0 {
+			continue
+		}
+
+		filesystems = append(filesystems, FilesystemUsage{
+			Mountpoint:        partition.Mountpoint,
+			Device:            partition.Device,
+			Fstype:            partition.Fstype,
+			Total:             usage.Total,
+			Used:              usage.Used,
+			Free:              usage.Free,
+			UsedPercent:       usage.UsedPercent,
+			InodesTotal:       usage.InodesTotal,
+			InodesUsed:        usage.InodesUsed,
+			InodesUsedPercent: usage.InodesUsedPercent,
+		})
+	}
+
+	sort.Slice(filesystems, func(i, j int) bool {
+		return filesystems[i].Mountpoint < filesystems[j].Mountpoint
+	})
+	f.filesystems = filesystems
+
+	samples := make([]Sample, 0, len(filesystems))
+	for _, fs := range filesystems {
+		samples = append(samples, Sample{Name: FilesystemUsageMetric(fs.Mountpoint), Value: fs.UsedPercent})
+	}
+	return samples, nil
+}
+
+// visibleMounts returns the partitions passing the filter, one per mount point. The same mount point
+// may be listed more than once, only its last mount is visible, hiding those below it.
+func (f *FilesystemCollector) visibleMounts(partitions []disk.PartitionStat) []disk.PartitionStat {
+	last := make(map[string]int, len(partitions))
+	for i, partition := range partitions {
+		last[partition.Mountpoint] = i
+	}
+
+	visible := make([]disk.PartitionStat, 0, len(last))
+	for i, partition := range partitions {
+		if last[partition.Mountpoint] == i && f.filter.Matches(partition.Fstype, partition.Mountpoint) {
+			visible = append(visible, partition)
+		}
+	}
+	return visible
+}
+
+// Details returns the filesystems found by the latest collection as []FilesystemUsage
+func (f *FilesystemCollector) Details() any {
+	if f.filesystems == nil {
+		return nil
+	}
+	return f.filesystems
+}
+
+// FilesystemUsageMetric returns the name of the used space percentage metric of one mount point
+func FilesystemUsageMetric(mountpoint string) string {
+	return FilesystemPrefix + mountpoint + ".usage"
+}
+
+// containsString reports whether the list contains the value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package collectors
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/shirou/gopsutil/disk"
+)
+
+func TestFilesystemFilter(t *testing.T) {
+	filter := DefaultFilesystemFilter()
+
+	cases := []struct {
+		fstype     string
+		mountpoint string
+		want       bool
+	}{
+		{"ext4", "/", true},
+		{"xfs", "/home", true},
+		{"overlay", "/", true},
+		{"proc", "/proc", false},
+		{"tmpfs", "/run", false},
+		{"ext4", "/sys/fs/something", false},
+		{"ext4", "/devices", true},
+		{"squashfs", "/snap/core/1", false},
+	}
+	for _, c := range cases {
+		if got := filter.Matches(c.fstype, c.mountpoint); got != c.want {
+			t.Errorf("Expected Matches(%q, %q) to be %v, got %v", c.fstype, c.mountpoint, c.want, got)
+		}
+	}
+}
+
+func TestFilesystemFilterInclude(t *testing.T) {
+	filter := FilesystemFilter{IncludeFstypes: []string{"ext4"}}
+
+	if !filter.Matches("ext4", "/") {
+		t.Error("Expected included fstype to match")
+	}
+	if filter.Matches("xfs", "/data") {
+		t.Error("Expected fstype outside the include list not to match")
+	}
+}
+
+func TestFilesystemFilterWithFstypes(t *testing.T) {
+	filter := DefaultFilesystemFilter().WithFstypes([]string{"ext4", "tmpfs"}, []string{"vfat"})
+
+	// tmpfs is asked for, overriding its default exclusion
+	cases := []struct {
+		fstype     string
+		mountpoint string
+		want       bool
+	}{
+		{"ext4", "/", true},
+		{"tmpfs", "/run/user/1000", true},
+		{"xfs", "/data", false},
+		{"tmpfs", "/dev/shm", false},
+	}
+	for _, c := range cases {
+		if got := filter.Matches(c.fstype, c.mountpoint); got != c.want {
+			t.Errorf("Expected Matches(%q, %q) to be %v, got %v", c.fstype, c.mountpoint, c.want, got)
+		}
+	}
+
+	excluded := DefaultFilesystemFilter().WithFstypes(nil, []string{"vfat"})
+	if excluded.Matches("vfat", "/boot/efi") || excluded.Matches("proc", "/mnt/proc") || !excluded.Matches("ext4", "/") {
+		t.Error("Expected vfat left out next to the default pseudo filesystems")
+	}
+	if len(DefaultExcludedFstypes) != len(DefaultFilesystemFilter().ExcludeFstypes) || containsString(DefaultExcludedFstypes, "vfat") {
+		t.Error("Expected the default exclusions to be left as they are")
+	}
+}
+
+func TestFilesystemCollector(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("mount table is read from /proc on linux")
+	}
+
+	collector := NewFilesystemCollector(FilesystemInterval, FilesystemFilter{})
+	if collector.Details() != nil {
+		t.Error("Expected no details before the first collection")
+	}
+
+	samples, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	filesystems, ok := collector.Details().([]FilesystemUsage)
+	if !ok {
+		t.Fatalf("Expected []FilesystemUsage details, got %T", collector.Details())
+	}
+	if len(samples) != len(filesystems) {
+		t.Errorf("Expected one sample per filesystem, got %d samples for %d filesystems", len(samples), len(filesystems))
+	}
+	// Owned by the collector, so those of unmounted filesystems expire
+	for _, sample := range samples {
+		if !strings.HasPrefix(sample.Name, collector.MetricPrefix()) {
+			t.Errorf("Expected %q to start with the collector prefix", sample.Name)
+		}
+	}
+	for i := 1; i < len(filesystems); i++ {
+		if filesystems[i-1].Mountpoint >= filesystems[i].Mountpoint {
+			t.Errorf("Expected filesystems sorted by unique mount point, got %q before %q",
+				filesystems[i-1].Mountpoint, filesystems[i].Mountpoint)
+		}
+	}
+}
+
+func TestVisibleMounts(t *testing.T) {
+	collector := NewFilesystemCollector(FilesystemInterval, DefaultFilesystemFilter())
+	visible := collector.visibleMounts([]disk.PartitionStat{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+		// /data is mounted over, the NFS share hides the local disk
+		{Device: "/dev/sdb1", Mountpoint: "/data", Fstype: "xfs"},
+		{Device: "nas:/export", Mountpoint: "/data", Fstype: "nfs4"},
+		// A pseudo filesystem mounted over a disk hides it as well
+		{Device: "/dev/sdc1", Mountpoint: "/scratch", Fstype: "ext4"},
+		{Device: "tmpfs", Mountpoint: "/scratch", Fstype: "tmpfs"},
+	})
+
+	if len(visible) != 2 || visible[0].Device != "/dev/sda1" || visible[1].Device != "nas:/export" || visible[1].Fstype != "nfs4" {
+		t.Errorf("Expected / and the NFS share on /data, got %+v", visible)
+	}
+}
@@ -0,0 +1,108 @@
+package collectors
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/host"
+	"github.com/shirou/gopsutil/mem"
+
+	"go-dummy-monitor/constants"
+)
+
+// HostInfo describes the hardware, operating system and identity of the host. It does not
+// change while running, or only rarely, so it is collected once and again only on request.
+type HostInfo struct {
+	Hostname        string
+	OS              string // e.g. linux or darwin
+	Platform        string // Distribution or product, e.g. ubuntu
+	PlatformVersion string
+	KernelVersion   string
+	KernelArch      string
+	CPUModel        string
+	Sockets         int // Physical CPU packages, zero when unknown
+	PhysicalCores   int
+	LogicalCores    int
+	TotalRAM        uint64 // Bytes
+	BootTime        time.Time
+	Virtualization  string // e.g. "kvm guest" or "docker guest", empty on bare metal or when unknown
+	CollectedAt     time.Time
+}
+
+// LoadHostInfo collects the host information, leaving out whatever cannot be read
+func LoadHostInfo(ctx context.Context) HostInfo {
+	info := HostInfo{
+		CPUModel:    constants.UNKNOWN_CPU,
+		CollectedAt: time.Now(),
+	}
+
+	if h, err := host.InfoWithContext(ctx); err == nil {
+		info.Hostname = h.Hostname
+		info.OS = h.OS
+		info.Platform = h.Platform
+		info.PlatformVersion = h.PlatformVersion
+		info.KernelVersion = h.KernelVersion
+		info.KernelArch = h.KernelArch
+		if h.BootTime > 0 {
+			info.BootTime = time.Unix(int64(h.BootTime), 0)
+		}
+		if h.VirtualizationSystem != "" {
+			info.Virtualization = strings.TrimSpace(h.VirtualizationSystem + " " + h.VirtualizationRole)
+		}
+	}
+
+	// Linux lists every logical CPU with the package it belongs to
+	if cpus, err := cpu.InfoWithContext(ctx); err == nil && len(cpus) > 0 {
+		if cpus[0].ModelName != "" {
+			info.CPUModel = cpus[0].ModelName
+		}
+		packages := make(map[string]bool)
+		for _, c := range cpus {
+			if c.PhysicalID != "" {
+				packages[c.PhysicalID] = true
+			}
+		}
+		info.Sockets = len(packages)
+	} else if runtime.GOOS == "darwin" {
+		// gopsutil fails on Apple silicon, which has no fixed CPU frequency to report
+		if output, err := exec.CommandContext(ctx, "sysctl", "-n", "machdep.cpu.brand_string").Output(); err == nil {
+			info.CPUModel = strings.TrimSpace(string(output))
+		}
+	}
+
+	info.LogicalCores = HostLogicalCores(ctx)
+	if info.LogicalCores == 0 {
+		info.LogicalCores = runtime.NumCPU()
+	}
+	info.PhysicalCores = info.LogicalCores
+	if count, err := cpu.CountsWithContext(ctx, false); err == nil && count > 0 {
+		info.PhysicalCores = count
+	}
+
+	if v, err := mem.VirtualMemoryWithContext(ctx); err == nil {
+		info.TotalRAM = v.Total
+	}
+	return info
+}
+
+// HostLogicalCores returns the logical CPUs of the host, zero when unknown. They are read from the
+// host procfs, so unlike runtime.NumCPU the count is not narrowed by a cpuset restricting this process.
+func HostLogicalCores(ctx context.Context) int {
+	count, err := cpu.CountsWithContext(ctx, true)
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// Uptime returns how long the host has been running at now, zero when the boot time is unknown
+func (h HostInfo) Uptime(now time.Time) time.Duration {
+	if h.BootTime.IsZero() {
+		return 0
+	}
+	return now.Sub(h.BootTime)
+}
@@ -0,0 +1,37 @@
+package collectors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLoadHostInfo(t *testing.T) {
+	info := LoadHostInfo(context.Background())
+
+	if info.CPUModel == "" {
+		t.Error("Expected a CPU model, or the unknown CPU placeholder")
+	}
+	if info.LogicalCores < 1 || info.PhysicalCores < 1 || info.PhysicalCores > info.LogicalCores {
+		t.Errorf("Expected at least one core and no more physical than logical ones, got %d / %d",
+			info.PhysicalCores, info.LogicalCores)
+	}
+	if info.TotalRAM == 0 {
+		t.Error("Expected the total RAM to be known")
+	}
+	if info.CollectedAt.IsZero() {
+		t.Error("Expected the collection time to be set")
+	}
+}
+
+func TestHostInfoUptime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	info := HostInfo{BootTime: now.Add(-26 * time.Hour)}
+	if uptime := info.Uptime(now); uptime != 26*time.Hour {
+		t.Errorf("Expected 26h of uptime, got %s", uptime)
+	}
+	if uptime := (HostInfo{}).Uptime(now); uptime != 0 {
+		t.Errorf("Expected no uptime without a boot time, got %s", uptime)
+	}
+}
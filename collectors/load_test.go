@@ -0,0 +1,31 @@
+package collectors
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestLoadCollector(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("load averages are read from /proc on linux")
+	}
+
+	samples, err := NewLoadCollector(DefaultInterval).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, sample := range samples {
+		names[sample.Name] = true
+		if sample.Value < 0 {
+			t.Errorf("Expected non-negative %s, got %f", sample.Name, sample.Value)
+		}
+	}
+	for _, name := range []string{MetricLoad1, MetricLoad5, MetricLoad15, MetricProcsRunning, MetricProcsBlocked} {
+		if !names[name] {
+			t.Errorf("Expected sample %s", name)
+		}
+	}
+}
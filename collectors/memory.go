@@ -0,0 +1,108 @@
+package collectors
+
+import (
+	"context"
+	"time"
+
+	"github.com/shirou/gopsutil/mem"
+)
+
+// Metric names of the memory composition, each a percentage of total RAM.
+// Used, buffers, cached and free add up to the total and can be stacked;
+// shared, dirty and slab overlap with them and are reported on their own.
+const (
+	MetricRAMTotal     = "ram.total" // bytes
+	MetricRAMAvailable = "ram.available"
+	MetricRAMUsed      = "ram.composition.used"
+	MetricRAMBuffers   = "ram.composition.buffers"
+	MetricRAMCached    = "ram.composition.cached"
+	MetricRAMFree      = "ram.composition.free"
+	MetricRAMShared    = "ram.shared"
+	MetricRAMDirty     = "ram.dirty"
+	MetricRAMSlab      = "ram.slab"
+)
+
+// Metric names of swap usage
+const (
+	MetricSwapUsage = "swap.usage"
+	MetricSwapTotal = "swap.total" // bytes
+	MetricSwapUsed  = "swap.used"  // bytes
+	MetricSwapIn    = "swap.in"    // MB/s
+	MetricSwapOut   = "swap.out"   // MB/s
+)
+
+// MemoryCollector collects RAM utilization, memory composition and swap activity
+type MemoryCollector struct {
+	interval time.Duration
+	backend  Backend
+	rates    *CounterRate
+}
+
+// NewMemoryCollector creates a new MemoryCollector reading the memory usage from backend
+func NewMemoryCollector(interval time.Duration, backend Backend) *MemoryCollector {
+	return &MemoryCollector{
+		interval: interval,
+		backend:  backend,
+		rates:    NewCounterRate(),
+	}
+}
+
+// Name returns the collector name
+func (m *MemoryCollector) Name() string {
+	return "memory"
+}
+
+// Interval returns the collection interval
+func (m *MemoryCollector) Interval() time.Duration {
+	return m.interval
+}
+
+// Collect gathers the used RAM percentage, the memory composition and swap usage
+func (m *MemoryCollector) Collect(ctx context.Context) ([]Sample, error) {
+	memStats, err := m.backend.VirtualMemory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := append([]Sample{{Name: MetricRAMUsage, Value: memStats.UsedPercent}}, memorySamples(memStats)...)
+
+	swapStats, err := m.backend.SwapMemory(ctx)
+	if err != nil {
+		return samples, err
+	}
+	now := time.Now()
+
+	swapIn, _ := m.rates.Rate("sin", swapStats.Sin, now)
+	swapOut, _ := m.rates.Rate("sout", swapStats.Sout, now)
+
+	return append(samples,
+		Sample{Name: MetricSwapUsage, Value: swapStats.UsedPercent},
+		Sample{Name: MetricSwapTotal, Value: float64(swapStats.Total)},
+		Sample{Name: MetricSwapUsed, Value: float64(swapStats.Used)},
+		Sample{Name: MetricSwapIn, Value: swapIn / bytesPerMB},
+		Sample{Name: MetricSwapOut, Value: swapOut / bytesPerMB},
+	), nil
+}
+
+// memorySamples converts memory stats into composition percentages of total RAM
+func memorySamples(v *mem.VirtualMemoryStat) []Sample {
+	if v.Total == 0 {
+		return nil
+	}
+
+	percent := func(value uint64) float64 {
+		return float64(value) / float64(v.Total) * 100
+	}
+
+	return []Sample{
+		{Name: MetricRAMTotal, Value: float64(v.Total)},
+		{Name: MetricRAMAvailable, Value: percent(v.Available)},
+		{Name: MetricRAMUsed, Value: percent(v.Used)},
+		{Name: MetricRAMBuffers, Value: percent(v.Buffers)},
+		{Name: MetricRAMCached, Value: percent(v.Cached)},
+		{Name: MetricRAMFree, Value: percent(v.Free)},
+		{Name: MetricRAMShared, Value: percent(v.Shared)},
+		{Name: MetricRAMDirty, Value: percent(v.Dirty)},
+		{Name: MetricRAMSlab, Value: percent(v.Slab)},
+	}
+}
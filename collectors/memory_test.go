@@ -0,0 +1,55 @@
+package collectors
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/mem"
+)
+
+func TestMemorySamples(t *testing.T) {
+	stats := &mem.VirtualMemoryStat{
+		Total:     1000,
+		Available: 600,
+		Used:      400,
+		Buffers:   50,
+		Cached:    300,
+		Free:      250,
+		Shared:    20,
+		Dirty:     10,
+		Slab:      40,
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range memorySamples(stats) {
+		values[sample.Name] = sample.Value
+	}
+
+	expected := map[string]float64{
+		MetricRAMTotal:     1000,
+		MetricRAMAvailable: 60,
+		MetricRAMUsed:      40,
+		MetricRAMBuffers:   5,
+		MetricRAMCached:    30,
+		MetricRAMFree:      25,
+		MetricRAMShared:    2,
+		MetricRAMDirty:     1,
+		MetricRAMSlab:      4,
+	}
+	for name, want := range expected {
+		if got := values[name]; got != want {
+			t.Errorf("Expected %s to be %.1f, got %.1f", name, want, got)
+		}
+	}
+
+	// The stackable layers must add up to the whole
+	stacked := values[MetricRAMUsed] + values[MetricRAMBuffers] + values[MetricRAMCached] + values[MetricRAMFree]
+	if stacked != 100 {
+		t.Errorf("Expected stacked composition to be 100%%, got %.1f%%", stacked)
+	}
+}
+
+func TestMemorySamplesZeroTotal(t *testing.T) {
+	if samples := memorySamples(&mem.VirtualMemoryStat{}); samples != nil {
+		t.Errorf("Expected no samples without total memory, got %v", samples)
+	}
+}
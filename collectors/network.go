@@ -0,0 +1,253 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/net"
+
+	"go-dummy-monitor/utils"
+)
+
+// NetInterfacePrefix starts the names of all per-interface network metrics
+const NetInterfacePrefix = "net.iface."
+
+// Kinds of network metrics, each reported for the active interface as "net.<kind>",
+// for all physical interfaces as "net.all.<kind>" and per interface
+const (
+	NetRead       = "read"        // MB/s received
+	NetWrite      = "write"       // MB/s sent
+	NetPacketsIn  = "packets_in"  // packets/s received
+	NetPacketsOut = "packets_out" // packets/s sent
+	NetErrorsIn   = "errors_in"   // receive errors/s
+	NetErrorsOut  = "errors_out"  // transmit errors/s
+	NetDropsIn    = "drops_in"    // dropped incoming packets/s
+	NetDropsOut   = "drops_out"   // dropped outgoing packets/s
+)
+
+// netMetricKinds lists the network metric kinds in reporting order
+var netMetricKinds = []string{NetRead, NetWrite, NetPacketsIn, NetPacketsOut, NetErrorsIn, NetErrorsOut, NetDropsIn, NetDropsOut}
+
+// NetAllPrefix starts the names of the network metrics summed over all physical interfaces
+const NetAllPrefix = "net.all."
+
+// Network metrics not covered by the built-in metric names
+const (
+	MetricNetMaxSpeed = "net.max_speed" // link speed of the active interface in MB/s
+	MetricNetAllRead  = NetAllPrefix + NetRead
+	MetricNetAllWrite = NetAllPrefix + NetWrite
+)
+
+// NetInterfaceMetric returns the name of a metric of the given kind for one interface
+func NetInterfaceMetric(iface, kind string) string {
+	return NetInterfacePrefix + iface + "." + kind
+}
+
+// NetInterfaces returns the sorted interface names found among the given metric names
+func NetInterfaces(metricNames []string) []string {
+	var interfaces []string
+	for _, name := range metricNames {
+		if strings.HasPrefix(name, NetInterfacePrefix) && strings.HasSuffix(name, "."+NetRead) {
+			interfaces = append(interfaces, strings.TrimSuffix(strings.TrimPrefix(name, NetInterfacePrefix), "."+NetRead))
+		}
+	}
+	sort.Strings(interfaces)
+	return interfaces
+}
+
+// virtualInterfacePattern matches loopback, bridge, tunnel and container interfaces
+var virtualInterfacePattern = regexp.MustCompile(`^(lo|veth|docker|br-|virbr|vnet|tun|tap|wg|vmnet|utun|awdl|llw|bridge|gif|stf|anpi)\d*`)
+
+// NetworkCollector collects download and upload speeds of the active interface,
+// of every interface and of all physical interfaces together
+type NetworkCollector struct {
+	interval   time.Duration
+	backend    Backend
+	rates      *CounterRate
+	resolver   *utils.InterfaceResolver
+	iface      string
+	maxSpeed   float64
+	sysNetPath string
+	physical   map[string]bool
+	sorted     []psnet.IOCountersStat // Copy of the counters, the backend owns and reuses its slice
+}
+
+// NewNetworkCollector creates a new NetworkCollector reading the interface counters from backend
+// and following the default interface resolver
+func NewNetworkCollector(interval time.Duration, backend Backend) *NetworkCollector {
+	return &NetworkCollector{
+		interval:   interval,
+		backend:    backend,
+		rates:      NewCounterRate(),
+		resolver:   utils.DefaultInterfaceResolver,
+		sysNetPath: utils.HostSys("class", "net"),
+		physical:   make(map[string]bool),
+	}
+}
+
+// Name returns the collector name
+func (n *NetworkCollector) Name() string {
+	return "network"
+}
+
+// Interval returns the collection interval
+func (n *NetworkCollector) Interval() time.Duration {
+	return n.interval
+}
+
+// MetricPrefix returns the prefix of the per-interface metrics, those of removed interfaces are dropped
+func (n *NetworkCollector) MetricPrefix() string {
+	return NetInterfacePrefix
+}
+
+// Collect gathers network read/write speeds in MB/s
+func (n *NetworkCollector) Collect(ctx context.Context) ([]Sample, error) {
+	netStats, err := n.backend.NetIOCounters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The link speed is only looked up again when the active interface changes
+	activeNetInterfaceName := n.resolver.Active()
+	if activeNetInterfaceName != n.iface || n.maxSpeed == 0 {
+		n.iface = activeNetInterfaceName
+		n.maxSpeed = utils.NetworkSpeed(activeNetInterfaceName)
+	}
+
+	samples := n.interfaceSamples(netStats, activeNetInterfaceName, time.Now())
+	return append(samples, Sample{Name: MetricNetMaxSpeed, Value: n.maxSpeed}), nil
+}
+
+// interfaceSamples computes the per-interface, active interface and physical aggregate samples
+func (n *NetworkCollector) interfaceSamples(netStats []psnet.IOCountersStat, active string, now time.Time) []Sample {
+	// Sort a copy for a stable sample order
+	n.sorted = append(n.sorted[:0], netStats...)
+	netStats = n.sorted
+	sort.Slice(netStats, func(i, j int) bool { return netStats[i].Name < netStats[j].Name })
+
+	// The active interface reads zero until it shows up in the counters
+	var activeActivity, total netActivity
+
+	samples := make([]Sample, 0, (len(netStats)+2)*len(netMetricKinds))
+	seen := make(map[string]bool, len(netStats)*len(netMetricKinds))
+	present := make(map[string]bool, len(netStats))
+	for _, stats := range netStats {
+		present[stats.Name] = true
+		activity := n.interfaceActivity(stats, now, seen)
+		values := activity.values()
+		for _, kind := range netMetricKinds {
+			samples = append(samples, Sample{Name: NetInterfaceMetric(stats.Name, kind), Value: values[kind]})
+		}
+
+		// Interface names are matched exactly so that "veth123" never stands in for "eth0"
+		if stats.Name == active {
+			activeActivity = activity
+		}
+		// The active interface always counts, so a container whose only interface is a veth still reports traffic
+		if stats.Name == active || n.isPhysicalInterface(stats.Name) {
+			total.add(activity)
+		}
+	}
+	n.rates.Forget(seen)
+	for name := range n.physical {
+		if !present[name] {
+			delete(n.physical, name)
+		}
+	}
+
+	activeValues, totalValues := activeActivity.values(), total.values()
+	for _, kind := range netMetricKinds {
+		samples = append(samples,
+			Sample{Name: "net." + kind, Value: activeValues[kind]},
+			Sample{Name: NetAllPrefix + kind, Value: totalValues[kind]},
+		)
+	}
+	return samples
+}
+
+// netActivity holds the per-second rates of one interface or of several interfaces summed up
+type netActivity struct {
+	bytesIn    float64
+	bytesOut   float64
+	packetsIn  float64
+	packetsOut float64
+	errorsIn   float64
+	errorsOut  float64
+	dropsIn    float64
+	dropsOut   float64
+}
+
+// interfaceActivity computes the rates of one interface from its counters
+func (n *NetworkCollector) interfaceActivity(stats psnet.IOCountersStat, now time.Time, seen map[string]bool) netActivity {
+	// Update the counters of every interface so switching interfaces keeps valid rates
+	rate := func(kind string, value uint64) float64 {
+		key := stats.Name + "." + kind
+		seen[key] = true
+		perSecond, _ := n.rates.Rate(key, value, now)
+		return perSecond
+	}
+
+	return netActivity{
+		bytesIn:    rate("recv", stats.BytesRecv),
+		bytesOut:   rate("sent", stats.BytesSent),
+		packetsIn:  rate(NetPacketsIn, stats.PacketsRecv),
+		packetsOut: rate(NetPacketsOut, stats.PacketsSent),
+		errorsIn:   rate(NetErrorsIn, stats.Errin),
+		errorsOut:  rate(NetErrorsOut, stats.Errout),
+		dropsIn:    rate(NetDropsIn, stats.Dropin),
+		dropsOut:   rate(NetDropsOut, stats.Dropout),
+	}
+}
+
+// add sums the rates of another interface into a
+func (a *netActivity) add(other netActivity) {
+	a.bytesIn += other.bytesIn
+	a.bytesOut += other.bytesOut
+	a.packetsIn += other.packetsIn
+	a.packetsOut += other.packetsOut
+	a.errorsIn += other.errorsIn
+	a.errorsOut += other.errorsOut
+	a.dropsIn += other.dropsIn
+	a.dropsOut += other.dropsOut
+}
+
+// values returns the rates keyed by network metric kind, throughput in MB/s
+func (a netActivity) values() map[string]float64 {
+	return map[string]float64{
+		NetRead:       a.bytesIn / bytesPerMB,
+		NetWrite:      a.bytesOut / bytesPerMB,
+		NetPacketsIn:  a.packetsIn,
+		NetPacketsOut: a.packetsOut,
+		NetErrorsIn:   a.errorsIn,
+		NetErrorsOut:  a.errorsOut,
+		NetDropsIn:    a.dropsIn,
+		NetDropsOut:   a.dropsOut,
+	}
+}
+
+// isPhysicalInterface reports whether the interface is backed by a network device
+// rather than being a loopback, bridge, tunnel or container interface
+func (n *NetworkCollector) isPhysicalInterface(name string) bool {
+	if physical, ok := n.physical[name]; ok {
+		return physical
+	}
+
+	physical := !virtualInterfacePattern.MatchString(name)
+
+	// On Linux only interfaces backed by hardware have a device link in sysfs
+	if runtime.GOOS == "linux" {
+		if _, err := os.Stat(n.sysNetPath); err == nil {
+			_, err := os.Stat(filepath.Join(n.sysNetPath, name, "device"))
+			physical = err == nil
+		}
+	}
+
+	n.physical[name] = physical
+	return physical
+}
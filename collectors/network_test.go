@@ -0,0 +1,163 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/net"
+)
+
+func TestIsPhysicalInterfaceByName(t *testing.T) {
+	collector := NewNetworkCollector(DefaultInterval, NewGopsutilBackend())
+	collector.sysNetPath = filepath.Join(t.TempDir(), "missing")
+
+	cases := map[string]bool{
+		"eth0":        true,
+		"enp3s0":      true,
+		"wlan0":       true,
+		"en0":         true,
+		"lo":          false,
+		"lo0":         false,
+		"veth1234":    false,
+		"docker0":     false,
+		"br-5f3a":     false,
+		"virbr0":      false,
+		"tun0":        false,
+		"wg0":         false,
+		"utun3":       false,
+		"awdl0":       false,
+		"vmnet8":      false,
+		"bridge100":   false,
+		"tailscale0":  true,
+		"enx00e04c68": true,
+	}
+	for name, want := range cases {
+		if got := collector.isPhysicalInterface(name); got != want {
+			t.Errorf("Expected isPhysicalInterface(%q) to be %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestIsPhysicalInterfaceBySysfs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("sysfs is only consulted on linux")
+	}
+
+	root := t.TempDir()
+	for _, dir := range []string{"eth0/device", "wlan0/device", "lo", "docker0"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collector := NewNetworkCollector(DefaultInterval, NewGopsutilBackend())
+	collector.sysNetPath = root
+
+	cases := map[string]bool{
+		"eth0":    true,
+		"wlan0":   true,
+		"lo":      false,
+		"docker0": false,
+	}
+	for name, want := range cases {
+		if got := collector.isPhysicalInterface(name); got != want {
+			t.Errorf("Expected isPhysicalInterface(%q) to be %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestNetInterfaces(t *testing.T) {
+	names := []string{
+		MetricNetRead,
+		MetricNetAllRead,
+		NetInterfaceMetric("wlan0", NetRead),
+		NetInterfaceMetric("wlan0", NetWrite),
+		NetInterfaceMetric("eth0.100", NetRead),
+	}
+
+	interfaces := NetInterfaces(names)
+	if len(interfaces) != 2 || interfaces[0] != "eth0.100" || interfaces[1] != "wlan0" {
+		t.Errorf("Expected [eth0.100 wlan0], got %v", interfaces)
+	}
+}
+
+func TestInterfaceSamples(t *testing.T) {
+	collector := NewNetworkCollector(DefaultInterval, NewGopsutilBackend())
+	collector.sysNetPath = filepath.Join(t.TempDir(), "missing")
+
+	start := time.Now()
+	counters := func(eth0, veth uint64) []psnet.IOCountersStat {
+		return []psnet.IOCountersStat{
+			{Name: "veth123", BytesRecv: veth, BytesSent: veth},
+			{Name: "eth0", BytesRecv: eth0, BytesSent: eth0 / 2, PacketsRecv: eth0 / 1024, Errin: eth0 / bytesPerMB, Dropout: 1},
+			{Name: "lo", BytesRecv: 1 << 30, BytesSent: 1 << 30},
+		}
+	}
+	collector.interfaceSamples(counters(0, 0), "eth0", start)
+	stats := counters(2*bytesPerMB, 4*bytesPerMB)
+	samples := collector.interfaceSamples(stats, "eth0", start.Add(time.Second))
+	// The counters belong to the backend and are left as they are
+	if stats[0].Name != "veth123" || stats[2].Name != "lo" {
+		t.Errorf("Expected the backend counters in their order, got %v", stats)
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+
+	// The active interface is matched exactly, so veth123 does not stand in for eth0
+	if values[MetricNetRead] != 2 || values[MetricNetWrite] != 1 {
+		t.Errorf("Expected active interface rates 2/1, got %f/%f", values[MetricNetRead], values[MetricNetWrite])
+	}
+	if values[NetInterfaceMetric("veth123", NetRead)] != 4 {
+		t.Errorf("Expected veth123 read rate 4, got %f", values[NetInterfaceMetric("veth123", NetRead)])
+	}
+
+	// Packet, error and drop counters become per-second rates
+	if values["net."+NetPacketsIn] != 2048 || values["net."+NetErrorsIn] != 2 || values["net."+NetDropsOut] != 0 {
+		t.Errorf("Expected packet, error and drop rates 2048/2/0, got %f/%f/%f",
+			values["net."+NetPacketsIn], values["net."+NetErrorsIn], values["net."+NetDropsOut])
+	}
+
+	// Only physical interfaces are summed, loopback and veth traffic is left out
+	if values[MetricNetAllRead] != 2 || values[MetricNetAllWrite] != 1 {
+		t.Errorf("Expected aggregate rates 2/1, got %f/%f", values[MetricNetAllRead], values[MetricNetAllWrite])
+	}
+	if values[NetAllPrefix+NetErrorsIn] != 2 {
+		t.Errorf("Expected aggregate error rate 2, got %f", values[NetAllPrefix+NetErrorsIn])
+	}
+}
+
+func TestRemovedInterfacesExpire(t *testing.T) {
+	root := t.TempDir()
+	writeProcfs(t, root)
+	backend := NewProcfsBackend(root)
+	defer backend.Close()
+
+	registry := NewRegistry()
+	_ = registry.Register(NewNetworkCollector(DefaultInterval, backend))
+	start := time.Now()
+	if _, err := registry.CollectDue(context.Background(), start); err != nil {
+		t.Fatal(err)
+	}
+
+	// The container interface goes away
+	writeSysfs(t, root, map[string]string{
+		"net/dev": "Inter-|   Receive\n face |bytes\n    lo: 1000 10 0 0 0 0 0 0 1000 10 0 0 0 0 0 0",
+	})
+	if _, err := registry.CollectDue(context.Background(), start.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !registry.Expired(NetInterfaceMetric("eth0", NetRead)) {
+		t.Error("Expected the metrics of the removed interface to expire")
+	}
+	if registry.Expired(NetInterfaceMetric("lo", NetRead)) || registry.Expired(MetricNetRead) {
+		t.Error("Expected the metrics of the remaining interface and the active one to be kept")
+	}
+}
@@ -0,0 +1,138 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PressureResources lists the resources the kernel reports stall information for
+var PressureResources = []string{"cpu", "memory", "io"}
+
+// Pressure lines: some tasks stalled, or all non-idle tasks stalled at once
+const (
+	PressureSome = "some"
+	PressureFull = "full"
+)
+
+// Pressure fields, each a percentage of wall time
+const (
+	PressureAvg10  = "avg10"  // Averaged by the kernel over 10 seconds
+	PressureAvg60  = "avg60"  // Averaged by the kernel over 60 seconds
+	PressureAvg300 = "avg300" // Averaged by the kernel over 300 seconds
+	PressureStall  = "stall"  // Since the previous collection, from the total stall time
+)
+
+// MetricPressure is the highest "some" 10 second average across resources, the overall contention
+const MetricPressure = "pressure.max"
+
+// PressureMetric returns the name of one pressure field of a resource, e.g. pressure.io.full.avg10
+func PressureMetric(resource, line, field string) string {
+	return "pressure." + resource + "." + line + "." + field
+}
+
+// PressureCollector reads Pressure Stall Information from /proc/pressure
+type PressureCollector struct {
+	interval time.Duration
+	procPath string
+	rates    *CounterRate
+}
+
+// NewPressureCollector creates a new PressureCollector reading the procfs mounted at procPath
+func NewPressureCollector(interval time.Duration, procPath string) *PressureCollector {
+	return &PressureCollector{
+		interval: interval,
+		procPath: procPath,
+		rates:    NewCounterRate(),
+	}
+}
+
+// Name returns the collector name
+func (p *PressureCollector) Name() string {
+	return "pressure"
+}
+
+// Interval returns the collection interval
+func (p *PressureCollector) Interval() time.Duration {
+	return p.interval
+}
+
+// Collect reads the stall averages and total stall time of every resource. Kernels
+// without PSI, and systems other than Linux, have no /proc/pressure and report an error.
+func (p *PressureCollector) Collect(_ context.Context) ([]Sample, error) {
+	now := time.Now()
+	var samples []Sample
+	var errs []error
+	var highest float64
+
+	for _, resource := range PressureResources {
+		data, err := os.ReadFile(filepath.Join(p.procPath, "pressure", resource))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, line := range parsePressure(string(data)) {
+			samples = append(samples,
+				Sample{Name: PressureMetric(resource, line.kind, PressureAvg10), Value: line.avg10},
+				Sample{Name: PressureMetric(resource, line.kind, PressureAvg60), Value: line.avg60},
+				Sample{Name: PressureMetric(resource, line.kind, PressureAvg300), Value: line.avg300},
+			)
+			// Totals are in microseconds, so microseconds per second divided by 10^4 is a percentage
+			if perSecond, ok := p.rates.Rate(resource+"."+line.kind, line.total, now); ok {
+				samples = append(samples, Sample{Name: PressureMetric(resource, line.kind, PressureStall), Value: perSecond / 1e4})
+			}
+			if line.kind == PressureSome && line.avg10 > highest {
+				highest = line.avg10
+			}
+		}
+	}
+
+	if len(samples) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return append(samples, Sample{Name: MetricPressure, Value: highest}), errors.Join(errs...)
+}
+
+// pressureLine is one line of a /proc/pressure file
+type pressureLine struct {
+	kind                 string // PressureSome or PressureFull
+	avg10, avg60, avg300 float64
+	total                uint64 // Microseconds
+}
+
+// parsePressure parses lines like "some avg10=0.12 avg60=0.05 avg300=0.01 total=123456",
+// skipping fields it does not know so newer kernels keep working
+func parsePressure(data string) []pressureLine {
+	var lines []pressureLine
+	for _, text := range strings.Split(data, "\n") {
+		fields := strings.Fields(text)
+		if len(fields) == 0 || (fields[0] != PressureSome && fields[0] != PressureFull) {
+			continue
+		}
+
+		line := pressureLine{kind: fields[0]}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case PressureAvg10:
+				line.avg10, _ = strconv.ParseFloat(value, 64)
+			case PressureAvg60:
+				line.avg60, _ = strconv.ParseFloat(value, 64)
+			case PressureAvg300:
+				line.avg300, _ = strconv.ParseFloat(value, 64)
+			case "total":
+				line.total, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
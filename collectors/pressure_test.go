@@ -0,0 +1,89 @@
+package collectors
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParsePressure(t *testing.T) {
+	lines := parsePressure("some avg10=2.45 avg60=1.89 avg300=1.36 total=70842964\n" +
+		"full avg10=0.50 avg60=0.25 avg300=0.00 total=2953250 future=1\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %+v", lines)
+	}
+	some, full := lines[0], lines[1]
+	if some.kind != PressureSome || some.avg10 != 2.45 || some.avg60 != 1.89 || some.avg300 != 1.36 || some.total != 70842964 {
+		t.Errorf("Unexpected some line %+v", some)
+	}
+	if full.kind != PressureFull || full.avg10 != 0.5 || full.total != 2953250 {
+		t.Errorf("Unexpected full line %+v", full)
+	}
+}
+
+func TestPressureCollector(t *testing.T) {
+	root := t.TempDir()
+	writePressure := func(ioTotal string) {
+		writeSysfs(t, root, map[string]string{
+			// Older kernels have no full line for the CPU
+			"pressure/cpu":    "some avg10=3.00 avg60=2.00 avg300=1.00 total=1000",
+			"pressure/memory": "some avg10=12.50 avg60=4.00 avg300=1.00 total=5000\nfull avg10=6.25 avg60=2.00 avg300=0.50 total=3000",
+			"pressure/io":     "some avg10=1.00 avg60=1.00 avg300=1.00 total=" + ioTotal + "\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0",
+		})
+	}
+	writePressure("0")
+
+	collector := NewPressureCollector(DefaultInterval, root)
+	samples, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+	expected := map[string]float64{
+		PressureMetric("cpu", PressureSome, PressureAvg10):     3,
+		PressureMetric("memory", PressureSome, PressureAvg60):  4,
+		PressureMetric("memory", PressureFull, PressureAvg10):  6.25,
+		PressureMetric("memory", PressureFull, PressureAvg300): 0.5,
+		PressureMetric("io", PressureSome, PressureAvg300):     1,
+		MetricPressure: 12.5,
+	}
+	for name, want := range expected {
+		if got, ok := values[name]; !ok || got != want {
+			t.Errorf("Expected %s to be %f, got %f (present %v)", name, want, got, ok)
+		}
+	}
+	if _, ok := values[PressureMetric("cpu", PressureFull, PressureAvg10)]; ok {
+		t.Error("Expected no full CPU pressure where the kernel does not report it")
+	}
+	// The stall time needs two readings
+	if _, ok := values[PressureMetric("io", PressureSome, PressureStall)]; ok {
+		t.Error("Expected no stall time on the first collection")
+	}
+
+	// 50ms of I/O stall over ~100ms is about half the time
+	time.Sleep(100 * time.Millisecond)
+	writePressure("50000")
+	samples, _ = collector.Collect(context.Background())
+	for _, sample := range samples {
+		if sample.Name == PressureMetric("io", PressureSome, PressureStall) {
+			if math.Abs(sample.Value-50) > 25 {
+				t.Errorf("Expected an I/O stall of about 50%%, got %f", sample.Value)
+			}
+			return
+		}
+	}
+	t.Error("Expected the I/O stall time on the second collection")
+}
+
+func TestPressureCollectorWithoutPSI(t *testing.T) {
+	samples, err := NewPressureCollector(DefaultInterval, t.TempDir()).Collect(context.Background())
+	if err == nil || len(samples) != 0 {
+		t.Errorf("Expected an error and no samples without PSI, got %v and %v", samples, err)
+	}
+}
@@ -0,0 +1,290 @@
+package collectors
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+// ProcessInterval is how often the process table is sampled, reading every process is comparatively expensive
+const ProcessInterval = 2 * time.Second
+
+// Process metrics produced by the process collector
+const (
+	MetricProcCount   = "proc.count"
+	MetricProcThreads = "proc.threads"
+)
+
+// ProcessInfo describes one process at the time of the latest sample
+type ProcessInfo struct {
+	PID        int32
+	PPID       int32
+	Name       string
+	User       string
+	CPUPercent float64 // Percent of one CPU, above 100 for processes busy on several CPUs
+	RSS        uint64  // Bytes
+	Threads    int32
+	ReadRate   float64 // Bytes/s
+	WriteRate  float64 // Bytes/s
+	CreateTime int64   // Milliseconds since the epoch, tells apart processes that reused a PID
+}
+
+// IORate returns the combined read and write rate in bytes/s
+func (p ProcessInfo) IORate() float64 {
+	return p.ReadRate + p.WriteRate
+}
+
+// ProcessSortKey names a process table ordering
+type ProcessSortKey string
+
+// Process table orderings, numeric ones list the biggest consumers first
+const (
+	SortByCPU    ProcessSortKey = "CPU"
+	SortByMemory ProcessSortKey = "Memory"
+	SortByIO     ProcessSortKey = "I/O"
+	SortByPID    ProcessSortKey = "PID"
+	SortByName   ProcessSortKey = "Name"
+)
+
+// ProcessSortKeys lists the supported orderings
+var ProcessSortKeys = []ProcessSortKey{SortByCPU, SortByMemory, SortByIO, SortByPID, SortByName}
+
+// SortProcesses orders the processes in place; ties are broken by PID so the order is stable between samples
+func SortProcesses(procs []ProcessInfo, key ProcessSortKey) {
+	sort.Slice(procs, func(i, j int) bool {
+		a, b := procs[i], procs[j]
+		switch key {
+		case SortByCPU:
+			if a.CPUPercent != b.CPUPercent {
+				return a.CPUPercent > b.CPUPercent
+			}
+		case SortByMemory:
+			if a.RSS != b.RSS {
+				return a.RSS > b.RSS
+			}
+		case SortByIO:
+			if a.IORate() != b.IORate() {
+				return a.IORate() > b.IORate()
+			}
+		case SortByName:
+			if a.Name != b.Name {
+				return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+			}
+		}
+		return a.PID < b.PID
+	})
+}
+
+// TopProcesses returns the first n processes in the given order without modifying procs
+func TopProcesses(procs []ProcessInfo, key ProcessSortKey, n int) []ProcessInfo {
+	sorted := append([]ProcessInfo(nil), procs...)
+	SortProcesses(sorted, key)
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// FilterProcesses returns the processes whose PID equals the filter or whose name
+// or user contains it, ignoring case; an empty filter matches every process
+func FilterProcesses(procs []ProcessInfo, filter string) []ProcessInfo {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	if filter == "" {
+		return procs
+	}
+
+	var matched []ProcessInfo
+	for _, proc := range procs {
+		if strconv.Itoa(int(proc.PID)) == filter ||
+			strings.Contains(strings.ToLower(proc.Name), filter) ||
+			strings.Contains(strings.ToLower(proc.User), filter) {
+			matched = append(matched, proc)
+		}
+	}
+	return matched
+}
+
+// ProcessKey identifies a process by PID and start time, so that a reused PID is a new process
+func ProcessKey(pid int32, createTime int64) string {
+	return strconv.Itoa(int(pid)) + "." + strconv.FormatInt(createTime, 10)
+}
+
+// ProcessUsage is the CPU time and I/O of one process, cumulative and per second
+type ProcessUsage struct {
+	CPUSeconds float64 // User and system time since the process started
+	ReadBytes  uint64
+	WriteBytes uint64
+	CPUPercent float64 // Percent of one CPU, above 100 for processes busy on several CPUs
+	ReadRate   float64 // Bytes/s
+	WriteRate  float64 // Bytes/s
+	HasTimes   bool    // Whether the CPU times could be read
+	HasIO      bool    // Whether the I/O counters could be read
+}
+
+// ProcessRates turns the CPU time and I/O counters of processes into rates
+type ProcessRates struct {
+	rates *CounterRate
+	seen  map[string]bool
+}
+
+// NewProcessRates creates a new ProcessRates
+func NewProcessRates() *ProcessRates {
+	return &ProcessRates{
+		rates: NewCounterRate(),
+		seen:  make(map[string]bool),
+	}
+}
+
+// Read returns the usage of the process started at createTime, rates are zero on its first read
+func (p *ProcessRates) Read(ctx context.Context, proc *process.Process, createTime int64, now time.Time) ProcessUsage {
+	key := ProcessKey(proc.Pid, createTime)
+	rate := func(kind string, value uint64) float64 {
+		p.seen[key+"."+kind] = true
+		perSecond, _ := p.rates.Rate(key+"."+kind, value, now)
+		return perSecond
+	}
+
+	var usage ProcessUsage
+	if times, err := proc.TimesWithContext(ctx); err == nil {
+		usage.CPUSeconds, usage.HasTimes = times.User+times.System, true
+		// Milliseconds of CPU time per second, divided by ten, are a percentage
+		usage.CPUPercent = rate("cpu", uint64(usage.CPUSeconds*1000)) / 10
+	}
+	// I/O counters of other users' processes are only readable with elevated privileges
+	if io, err := proc.IOCountersWithContext(ctx); err == nil {
+		usage.ReadBytes, usage.WriteBytes, usage.HasIO = io.ReadBytes, io.WriteBytes, true
+		usage.ReadRate = rate("read", io.ReadBytes)
+		usage.WriteRate = rate("write", io.WriteBytes)
+	}
+	return usage
+}
+
+// Forget drops the counters of the processes not read since the previous Forget, they have exited
+func (p *ProcessRates) Forget() {
+	p.rates.Forget(p.seen)
+	clear(p.seen)
+}
+
+// ProcessSampler reads all processes and turns their CPU time and I/O counters into rates
+type ProcessSampler struct {
+	rates *ProcessRates
+	users map[string]string
+}
+
+// NewProcessSampler creates a new ProcessSampler
+func NewProcessSampler() *ProcessSampler {
+	return &ProcessSampler{
+		rates: NewProcessRates(),
+		users: make(map[string]string),
+	}
+}
+
+// Sample reads every process that is still running; rates are zero on the first sample of a process
+func (s *ProcessSampler) Sample(ctx context.Context) ([]ProcessInfo, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	infos := make([]ProcessInfo, 0, len(procs))
+	seen := make(map[string]bool, len(procs))
+	for _, proc := range procs {
+		// Past the deadline the processes read so far are returned
+		if ctx.Err() != nil {
+			break
+		}
+		// Processes that exited since they were listed have no name any more
+		name, err := proc.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		info := ProcessInfo{PID: proc.Pid, Name: name}
+		info.PPID, _ = proc.PpidWithContext(ctx)
+		info.CreateTime, _ = proc.CreateTimeWithContext(ctx)
+		info.Threads, _ = proc.NumThreadsWithContext(ctx)
+		if mem, err := proc.MemoryInfoWithContext(ctx); err == nil {
+			info.RSS = mem.RSS
+		}
+
+		usage := s.rates.Read(ctx, proc, info.CreateTime, now)
+		info.CPUPercent, info.ReadRate, info.WriteRate = usage.CPUPercent, usage.ReadRate, usage.WriteRate
+
+		key := ProcessKey(proc.Pid, info.CreateTime)
+		user, ok := s.users[key]
+		if !ok {
+			user, _ = proc.UsernameWithContext(ctx)
+			s.users[key] = user
+		}
+		seen[key] = true
+		info.User = user
+
+		infos = append(infos, info)
+	}
+
+	// Processes left unread are not gone, keep their counters
+	if ctx.Err() == nil {
+		s.rates.Forget()
+		for key := range s.users {
+			if !seen[key] {
+				delete(s.users, key)
+			}
+		}
+	}
+
+	return infos, nil
+}
+
+// ProcessCollector samples every process and publishes the process table
+type ProcessCollector struct {
+	interval  time.Duration
+	sampler   *ProcessSampler
+	processes []ProcessInfo
+}
+
+// NewProcessCollector creates a new ProcessCollector
+func NewProcessCollector(interval time.Duration) *ProcessCollector {
+	return &ProcessCollector{
+		interval: interval,
+		sampler:  NewProcessSampler(),
+	}
+}
+
+// Name returns the collector name
+func (p *ProcessCollector) Name() string {
+	return "processes"
+}
+
+// Interval returns the collection interval
+func (p *ProcessCollector) Interval() time.Duration {
+	return p.interval
+}
+
+// Collect samples every process and reports the process and thread counts
+func (p *ProcessCollector) Collect(ctx context.Context) ([]Sample, error) {
+	procs, err := p.sampler.Sample(ctx)
+	if err != nil {
+		return nil, err
+	}
+	SortProcesses(procs, SortByCPU)
+	p.processes = procs
+
+	var threads float64
+	for _, proc := range procs {
+		threads += float64(proc.Threads)
+	}
+
+	return []Sample{
+		{Name: MetricProcCount, Value: float64(len(procs))},
+		{Name: MetricProcThreads, Value: threads},
+	}, nil
+}
+
+// Details returns the processes of the latest sample, busiest first
+func (p *ProcessCollector) Details() any {
+	return p.processes
+}
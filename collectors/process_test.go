@@ -0,0 +1,114 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+func testProcesses() []ProcessInfo {
+	return []ProcessInfo{
+		{PID: 30, Name: "postgres", User: "postgres", CPUPercent: 5, RSS: 300, ReadRate: 10},
+		{PID: 10, Name: "gopls", User: "dev", CPUPercent: 50, RSS: 100},
+		{PID: 20, Name: "Firefox", User: "dev", CPUPercent: 50, RSS: 900, WriteRate: 5},
+		{PID: 40, Name: "sshd", User: "root"},
+	}
+}
+
+func TestSortProcesses(t *testing.T) {
+	cases := map[ProcessSortKey][]int32{
+		SortByCPU:    {10, 20, 30, 40}, // Equal CPU is ordered by PID
+		SortByMemory: {20, 30, 10, 40},
+		SortByIO:     {30, 20, 10, 40},
+		SortByPID:    {10, 20, 30, 40},
+		SortByName:   {20, 10, 30, 40},
+	}
+	for key, want := range cases {
+		procs := testProcesses()
+		SortProcesses(procs, key)
+		for i, pid := range want {
+			if procs[i].PID != pid {
+				t.Errorf("Expected PID %d at position %d when sorting by %s, got %d", pid, i, key, procs[i].PID)
+			}
+		}
+	}
+}
+
+func TestTopProcesses(t *testing.T) {
+	procs := testProcesses()
+	top := TopProcesses(procs, SortByMemory, 2)
+	if len(top) != 2 || top[0].PID != 20 || top[1].PID != 30 {
+		t.Errorf("Expected PIDs 20 and 30, got %+v", top)
+	}
+	if procs[0].PID != 30 {
+		t.Errorf("Expected the input to be left untouched")
+	}
+}
+
+func TestFilterProcesses(t *testing.T) {
+	procs := testProcesses()
+
+	cases := map[string]int{
+		"":        4,
+		"fire":    1, // Case-insensitive name match
+		"dev":     2, // User match
+		"40":      1, // Exact PID match
+		"4":       0, // PIDs are not matched partially
+		"nothing": 0,
+	}
+	for filter, want := range cases {
+		if got := len(FilterProcesses(procs, filter)); got != want {
+			t.Errorf("Expected %d processes for filter %q, got %d", want, filter, got)
+		}
+	}
+}
+
+func TestProcessSamplerFindsItself(t *testing.T) {
+	sampler := NewProcessSampler()
+	procs, err := sampler.Sample(context.Background())
+	if err != nil {
+		t.Skipf("processes cannot be listed here: %v", err)
+	}
+
+	for _, proc := range procs {
+		if proc.PID == int32(os.Getpid()) {
+			if proc.Name == "" || proc.RSS == 0 {
+				t.Errorf("Expected name and RSS of the test process, got %+v", proc)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected the test process among %d processes", len(procs))
+}
+
+func TestProcessRates(t *testing.T) {
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		t.Skipf("the test process cannot be read here: %v", err)
+	}
+	createTime, _ := proc.CreateTime()
+	rates := NewProcessRates()
+	start := time.Now()
+
+	first := rates.Read(context.Background(), proc, createTime, start)
+	if !first.HasTimes || first.CPUPercent != 0 {
+		t.Fatalf("Expected CPU times without a rate on the first read, got %+v", first)
+	}
+
+	// A process seen again with another start time is a new one
+	rates.Forget()
+	if again := rates.Read(context.Background(), proc, createTime+1, start.Add(time.Second)); again.CPUPercent != 0 {
+		t.Errorf("Expected a reused PID to start without a rate, got %+v", again)
+	}
+
+	// Only the counters of processes read since the previous Forget are kept
+	rates.Forget()
+	_, old := rates.rates.prev[ProcessKey(proc.Pid, createTime)+".cpu"]
+	_, reused := rates.rates.prev[ProcessKey(proc.Pid, createTime+1)+".cpu"]
+	if old || !reused {
+		t.Errorf("Expected the counters of the exited process to be dropped, got %v", rates.rates.prev)
+	}
+}
@@ -0,0 +1,458 @@
+package collectors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/mem"
+	psnet "github.com/shirou/gopsutil/net"
+)
+
+// Units of the procfs counters
+const (
+	procPageSize   = 4096 // Swap counters in /proc/vmstat, as assumed by gopsutil
+	procSectorSize = 512  // Sectors in /proc/diskstats, whatever the sector size of the device
+)
+
+// maxInternedNames bounds the remembered device and interface names, short-lived
+// container interfaces would otherwise grow the set forever
+const maxInternedNames = 1024
+
+// ProcfsBackend reads the counters straight from a Linux procfs. Files are kept open and
+// re-read into reusable buffers, and parsed in place into reused results, so that sampling
+// does not allocate once the buffers have grown to fit.
+type ProcfsBackend struct {
+	mu sync.Mutex // Collectors sharing the backend read concurrently
+
+	stat, meminfo, vmstat, diskstats, netdev procFile
+
+	cores  []cpu.TimesStat
+	memory mem.VirtualMemoryStat
+	swap   mem.SwapMemoryStat
+	disks  []disk.IOCountersStat
+	nics   []psnet.IOCountersStat
+	names  map[string]string
+}
+
+// NewProcfsBackend creates a new ProcfsBackend reading the procfs mounted at procPath
+func NewProcfsBackend(procPath string) *ProcfsBackend {
+	return &ProcfsBackend{
+		stat:      procFile{path: filepath.Join(procPath, "stat")},
+		meminfo:   procFile{path: filepath.Join(procPath, "meminfo")},
+		vmstat:    procFile{path: filepath.Join(procPath, "vmstat")},
+		diskstats: procFile{path: filepath.Join(procPath, "diskstats")},
+		netdev:    procFile{path: filepath.Join(procPath, "net", "dev")},
+		names:     make(map[string]string),
+	}
+}
+
+// Name returns the backend name
+func (p *ProcfsBackend) Name() string {
+	return BackendProcfs
+}
+
+// Close releases the open files, they are opened again by the next read
+func (p *ProcfsBackend) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return errors.Join(p.stat.close(), p.meminfo.close(), p.vmstat.close(), p.diskstats.close(), p.netdev.close())
+}
+
+// errNoCPUTimes is returned for a /proc/stat without an aggregate cpu line
+var errNoCPUTimes = errors.New("no cpu line in /proc/stat")
+
+// CPUTimes parses the cpu lines of /proc/stat, "cpu3 user nice system idle iowait irq softirq steal guest guest_nice"
+func (p *ProcfsBackend) CPUTimes(_ context.Context) (cpu.TimesStat, []cpu.TimesStat, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := p.stat.read()
+	if err != nil {
+		return cpu.TimesStat{}, nil, err
+	}
+
+	var total cpu.TimesStat
+	var hasTotal bool
+	p.cores = p.cores[:0]
+	for len(data) > 0 {
+		var line []byte
+		line, data = nextLine(data)
+		name, rest := nextField(line)
+		// The cpu lines come first, the counters after them are not needed
+		if len(name) < 3 || string(name[:3]) != "cpu" {
+			break
+		}
+
+		var values [10]float64
+		for i := range values {
+			var field []byte
+			field, rest = nextField(rest)
+			value, ok := parseUint(field)
+			if !ok {
+				break
+			}
+			values[i] = float64(value) / cpu.ClocksPerSec
+		}
+		times := cpu.TimesStat{
+			User: values[0], Nice: values[1], System: values[2], Idle: values[3], Iowait: values[4],
+			Irq: values[5], Softirq: values[6], Steal: values[7], Guest: values[8], GuestNice: values[9],
+		}
+
+		if len(name) == 3 {
+			times.CPU = "cpu-total"
+			total, hasTotal = times, true
+		} else {
+			times.CPU = p.intern(name)
+			p.cores = append(p.cores, times)
+		}
+	}
+
+	if !hasTotal {
+		return cpu.TimesStat{}, nil, errNoCPUTimes
+	}
+	return total, p.cores, nil
+}
+
+// meminfo holds the /proc/meminfo fields both memory results are built from, in bytes
+type meminfo struct {
+	total, free, available, buffers, cached, shared, dirty, slab, sReclaimable uint64
+	swapTotal, swapFree                                                        uint64
+	hasAvailable                                                               bool
+}
+
+// readMeminfo parses the needed fields of /proc/meminfo, lines like "MemTotal:  16318888 kB"
+func (p *ProcfsBackend) readMeminfo() (meminfo, error) {
+	data, err := p.meminfo.read()
+	if err != nil {
+		return meminfo{}, err
+	}
+
+	var info meminfo
+	for len(data) > 0 {
+		var line []byte
+		line, data = nextLine(data)
+		colon := bytes.IndexByte(line, ':')
+		if colon < 0 {
+			continue
+		}
+		field, unit := nextField(line[colon+1:])
+		value, ok := parseUint(field)
+		if !ok {
+			continue
+		}
+		if unit, _ := nextField(unit); string(unit) == "kB" {
+			value *= 1024
+		}
+
+		switch string(line[:colon]) {
+		case "MemTotal":
+			info.total = value
+		case "MemFree":
+			info.free = value
+		case "MemAvailable":
+			info.available, info.hasAvailable = value, true
+		case "Buffers":
+			info.buffers = value
+		case "Cached":
+			info.cached = value
+		case "Shmem":
+			info.shared = value
+		case "Dirty":
+			info.dirty = value
+		case "Slab":
+			info.slab = value
+		case "SReclaimable":
+			info.sReclaimable = value
+		case "SwapTotal":
+			info.swapTotal = value
+		case "SwapFree":
+			info.swapFree = value
+		}
+	}
+	return info, nil
+}
+
+// VirtualMemory returns the RAM usage, computed from /proc/meminfo the way gopsutil does
+func (p *ProcfsBackend) VirtualMemory(_ context.Context) (*mem.VirtualMemoryStat, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := p.readMeminfo()
+	if err != nil {
+		return nil, err
+	}
+
+	// Reclaimable slab is counted as cache, as free(1) does
+	cached := info.cached + info.sReclaimable
+	available := info.available
+	if !info.hasAvailable {
+		// Kernels before 3.14 do not estimate the available memory
+		available = info.free + cached
+	}
+
+	p.memory = mem.VirtualMemoryStat{
+		Total:        info.total,
+		Available:    available,
+		Free:         info.free,
+		Buffers:      info.buffers,
+		Cached:       cached,
+		Shared:       info.shared,
+		Dirty:        info.dirty,
+		Slab:         info.slab,
+		SReclaimable: info.sReclaimable,
+	}
+	if info.total > 0 {
+		p.memory.Used = info.total - info.free - info.buffers - cached
+		p.memory.UsedPercent = float64(p.memory.Used) / float64(info.total) * 100
+	}
+	return &p.memory, nil
+}
+
+// SwapMemory returns the swap usage from /proc/meminfo and the pages swapped in and out from /proc/vmstat
+func (p *ProcfsBackend) SwapMemory(_ context.Context) (*mem.SwapMemoryStat, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := p.readMeminfo()
+	if err != nil {
+		return nil, err
+	}
+
+	p.swap = mem.SwapMemoryStat{
+		Total: info.swapTotal,
+		Free:  info.swapFree,
+		Used:  info.swapTotal - info.swapFree,
+	}
+	if info.swapTotal > 0 {
+		p.swap.UsedPercent = float64(p.swap.Used) / float64(info.swapTotal) * 100
+	}
+
+	data, err := p.vmstat.read()
+	if err != nil {
+		return &p.swap, err
+	}
+	for len(data) > 0 {
+		var line []byte
+		line, data = nextLine(data)
+		name, rest := nextField(line)
+		field, _ := nextField(rest)
+		switch string(name) {
+		case "pswpin":
+			pages, _ := parseUint(field)
+			p.swap.Sin = pages * procPageSize
+		case "pswpout":
+			pages, _ := parseUint(field)
+			p.swap.Sout = pages * procPageSize
+		}
+	}
+	return &p.swap, nil
+}
+
+// DiskIOCounters parses /proc/diskstats, lines like "8 0 sda reads merged sectors ms writes merged sectors ms
+// in_flight io_ms weighted_ms ...", skipping devices that never did any I/O as gopsutil does
+func (p *ProcfsBackend) DiskIOCounters(_ context.Context) ([]disk.IOCountersStat, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := p.diskstats.read()
+	if err != nil {
+		return nil, err
+	}
+
+	p.disks = p.disks[:0]
+	for len(data) > 0 {
+		var line []byte
+		line, data = nextLine(data)
+		_, rest := nextField(line) // Major
+		_, rest = nextField(rest)  // Minor
+		name, rest := nextField(rest)
+
+		var values [11]uint64
+		complete := len(name) > 0
+		for i := range values {
+			var field []byte
+			field, rest = nextField(rest)
+			if values[i], complete = parseUint(field); !complete {
+				break
+			}
+		}
+		if !complete || values == [11]uint64{} {
+			continue
+		}
+
+		p.disks = append(p.disks, disk.IOCountersStat{
+			Name:             p.intern(name),
+			ReadCount:        values[0],
+			MergedReadCount:  values[1],
+			ReadBytes:        values[2] * procSectorSize,
+			ReadTime:         values[3],
+			WriteCount:       values[4],
+			MergedWriteCount: values[5],
+			WriteBytes:       values[6] * procSectorSize,
+			WriteTime:        values[7],
+			IopsInProgress:   values[8],
+			IoTime:           values[9],
+			WeightedIO:       values[10],
+		})
+	}
+	return p.disks, nil
+}
+
+// NetIOCounters parses /proc/net/dev, two header lines then "eth0: bytes packets errs drop fifo frame
+// compressed multicast" received followed by "bytes packets errs drop fifo colls carrier compressed" sent
+func (p *ProcfsBackend) NetIOCounters(_ context.Context) ([]psnet.IOCountersStat, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := p.netdev.read()
+	if err != nil {
+		return nil, err
+	}
+
+	p.nics = p.nics[:0]
+	for len(data) > 0 {
+		var line []byte
+		line, data = nextLine(data)
+		// Old kernels run the name into the first counter, "eth0:123", so split at the colon
+		colon := bytes.IndexByte(line, ':')
+		if colon < 0 {
+			continue
+		}
+		name, _ := nextField(line[:colon])
+		rest := line[colon+1:]
+
+		var values [16]uint64
+		complete := len(name) > 0
+		for i := range values {
+			var field []byte
+			field, rest = nextField(rest)
+			if values[i], complete = parseUint(field); !complete {
+				break
+			}
+		}
+		if !complete {
+			continue
+		}
+
+		p.nics = append(p.nics, psnet.IOCountersStat{
+			Name:        p.intern(name),
+			BytesRecv:   values[0],
+			PacketsRecv: values[1],
+			Errin:       values[2],
+			Dropin:      values[3],
+			Fifoin:      values[4],
+			BytesSent:   values[8],
+			PacketsSent: values[9],
+			Errout:      values[10],
+			Dropout:     values[11],
+			Fifoout:     values[12],
+		})
+	}
+	return p.nics, nil
+}
+
+// intern returns name as a string, reusing the string of an earlier call with the same name
+func (p *ProcfsBackend) intern(name []byte) string {
+	if s, ok := p.names[string(name)]; ok {
+		return s
+	}
+	if len(p.names) >= maxInternedNames {
+		clear(p.names)
+	}
+	s := string(name)
+	p.names[s] = s
+	return s
+}
+
+// procFile is a procfs file kept open and read from the start into a reusable buffer
+type procFile struct {
+	path string
+	file *os.File
+	buf  []byte
+}
+
+// procFileBufferSize is the initial buffer size, doubled whenever a file does not fit
+const procFileBufferSize = 4096
+
+// read returns the current contents of the file, valid until the next read
+func (f *procFile) read() ([]byte, error) {
+	if f.file == nil {
+		file, err := os.Open(f.path)
+		if err != nil {
+			return nil, err
+		}
+		f.file = file
+	}
+	if f.buf == nil {
+		f.buf = make([]byte, procFileBufferSize)
+	}
+
+	for {
+		// procfs regenerates the contents on every read from offset zero
+		n, err := f.file.ReadAt(f.buf, 0)
+		if err == io.EOF {
+			return f.buf[:n], nil
+		}
+		if err != nil {
+			// Open the file again on the next read, the procfs may have been remounted
+			_ = f.close()
+			return nil, err
+		}
+		// A full buffer may have cut the file short
+		f.buf = make([]byte, len(f.buf)*2)
+	}
+}
+
+// close closes the file if it is open
+func (f *procFile) close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// nextLine splits data after its first line
+func nextLine(data []byte) (line, rest []byte) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return data[:i], data[i+1:]
+	}
+	return data, nil
+}
+
+// nextField splits line after its first space separated field, empty when there is none
+func nextField(line []byte) (field, rest []byte) {
+	start := 0
+	for start < len(line) && (line[start] == ' ' || line[start] == '\t') {
+		start++
+	}
+	end := start
+	for end < len(line) && line[end] != ' ' && line[end] != '\t' {
+		end++
+	}
+	return line[start:end], line[end:]
+}
+
+// parseUint parses a decimal counter without converting it to a string first
+func parseUint(field []byte) (uint64, bool) {
+	if len(field) == 0 {
+		return 0, false
+	}
+	var value uint64
+	for _, c := range field {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		value = value*10 + uint64(c-'0')
+	}
+	return value, true
+}
@@ -0,0 +1,250 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/shirou/gopsutil/cpu"
+)
+
+// writeProcfs writes a small procfs with two CPUs, one active disk and two interfaces
+func writeProcfs(t *testing.T, root string) {
+	t.Helper()
+	writeSysfs(t, root, map[string]string{
+		"stat": "cpu  400 10 200 3000 50 5 5 20 30 0\n" +
+			"cpu0 200 5 100 1500 25 3 2 10 15 0\n" +
+			"cpu1 200 5 100 1500 25 2 3 10 15 0\n" +
+			"intr 905792 0 0\nctxt 123\nbtime 1700000000",
+		"meminfo": "MemTotal:       16000000 kB\n" +
+			"MemFree:         4000000 kB\n" +
+			"MemAvailable:    9000000 kB\n" +
+			"Buffers:          500000 kB\n" +
+			"Cached:          3000000 kB\n" +
+			"SwapTotal:       2000000 kB\n" +
+			"SwapFree:        1500000 kB\n" +
+			"Dirty:               100 kB\n" +
+			"Shmem:            200000 kB\n" +
+			"Slab:             800000 kB\n" +
+			"SReclaimable:     500000 kB\n" +
+			"HugePages_Total:       0",
+		"vmstat": "nr_free_pages 1000\npswpin 10\npswpout 20\npgfault 5",
+		"diskstats": "   7       0 loop0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n" +
+			"   8       0 sda 100 5 2048 300 50 2 1024 400 1 500 700 0 0 0 0 0 0",
+		"net/dev": "Inter-|   Receive                                                |  Transmit\n" +
+			" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+			"    lo: 1000 10 0 0 0 0 0 0 1000 10 0 0 0 0 0 0\n" +
+			"  eth0:5000000 4000 1 2 0 0 0 0 3000000 2000 3 4 0 0 0 0",
+	})
+}
+
+func TestProcfsBackend(t *testing.T) {
+	root := t.TempDir()
+	writeProcfs(t, root)
+	backend := NewProcfsBackend(root)
+	defer backend.Close()
+	ctx := context.Background()
+
+	total, cores, err := backend.CPUTimes(ctx)
+	if err != nil {
+		t.Fatalf("Expected CPU times, got %v", err)
+	}
+	if total.User != 400/cpu.ClocksPerSec || total.Idle != 3000/cpu.ClocksPerSec || total.Guest != 30/cpu.ClocksPerSec {
+		t.Errorf("Unexpected total CPU times %+v", total)
+	}
+	if len(cores) != 2 || cores[1].CPU != "cpu1" || cores[1].Softirq != 3/cpu.ClocksPerSec {
+		t.Errorf("Unexpected per-core CPU times %+v", cores)
+	}
+
+	memory, err := backend.VirtualMemory(ctx)
+	if err != nil {
+		t.Fatalf("Expected the memory usage, got %v", err)
+	}
+	// Used excludes free, buffers and the cache including reclaimable slab
+	if memory.Total != 16000000*1024 || memory.Available != 9000000*1024 || memory.Cached != 3500000*1024 ||
+		memory.Used != 8000000*1024 || memory.UsedPercent != 50 || memory.Shared != 200000*1024 {
+		t.Errorf("Unexpected memory usage %+v", memory)
+	}
+
+	swap, err := backend.SwapMemory(ctx)
+	if err != nil {
+		t.Fatalf("Expected the swap usage, got %v", err)
+	}
+	if swap.Used != 500000*1024 || swap.UsedPercent != 25 || swap.Sin != 10*4096 || swap.Sout != 20*4096 {
+		t.Errorf("Unexpected swap usage %+v", swap)
+	}
+
+	// Devices without any I/O are skipped
+	disks, err := backend.DiskIOCounters(ctx)
+	if err != nil {
+		t.Fatalf("Expected disk counters, got %v", err)
+	}
+	if len(disks) != 1 || disks[0].Name != "sda" || disks[0].ReadBytes != 2048*512 || disks[0].WriteCount != 50 ||
+		disks[0].IoTime != 500 || disks[0].WeightedIO != 700 {
+		t.Errorf("Unexpected disk counters %+v", disks)
+	}
+
+	// The name may run into the first counter
+	nics, err := backend.NetIOCounters(ctx)
+	if err != nil {
+		t.Fatalf("Expected interface counters, got %v", err)
+	}
+	if len(nics) != 2 || nics[1].Name != "eth0" || nics[1].BytesRecv != 5000000 || nics[1].BytesSent != 3000000 ||
+		nics[1].Errin != 1 || nics[1].Dropin != 2 || nics[1].Errout != 3 || nics[1].Dropout != 4 {
+		t.Errorf("Unexpected interface counters %+v", nics)
+	}
+}
+
+func TestProcfsBackendRereads(t *testing.T) {
+	root := t.TempDir()
+	writeProcfs(t, root)
+	backend := NewProcfsBackend(root)
+	defer backend.Close()
+
+	if _, _, err := backend.CPUTimes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Many cores outgrow the initial buffer
+	var stat strings.Builder
+	stat.WriteString("cpu  1 2 3 4 5 6 7 8 9 10\n")
+	for core := 0; core < 256; core++ {
+		fmt.Fprintf(&stat, "cpu%d 1 2 3 4 5 6 7 8 9 10\n", core)
+	}
+	writeSysfs(t, root, map[string]string{"stat": stat.String()})
+
+	_, cores, err := backend.CPUTimes(context.Background())
+	if err != nil || len(cores) != 256 || cores[255].CPU != "cpu255" {
+		t.Errorf("Expected 256 cores from the rewritten file, got %d and %v", len(cores), err)
+	}
+}
+
+func TestProcfsBackendMissing(t *testing.T) {
+	backend := NewProcfsBackend(t.TempDir())
+	if _, _, err := backend.CPUTimes(context.Background()); err == nil {
+		t.Error("Expected an error without /proc/stat")
+	}
+	if _, err := backend.NetIOCounters(context.Background()); err == nil {
+		t.Error("Expected an error without /proc/net/dev")
+	}
+}
+
+func TestProcfsBackendDoesNotAllocate(t *testing.T) {
+	root := t.TempDir()
+	writeProcfs(t, root)
+	backend := NewProcfsBackend(root)
+	defer backend.Close()
+	ctx := context.Background()
+
+	read := func() {
+		_, _, _ = backend.CPUTimes(ctx)
+		_, _ = backend.VirtualMemory(ctx)
+		_, _ = backend.SwapMemory(ctx)
+		_, _ = backend.DiskIOCounters(ctx)
+		_, _ = backend.NetIOCounters(ctx)
+	}
+	// The first read opens the files and sizes the buffers
+	read()
+	if allocs := testing.AllocsPerRun(10, read); allocs != 0 {
+		t.Errorf("Expected no allocations once the buffers are sized, got %.1f per read", allocs)
+	}
+}
+
+func TestBackendsAgree(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the procfs backend is only available on Linux")
+	}
+	ctx := context.Background()
+	procfs := NewProcfsBackend("/proc")
+	defer procfs.Close()
+	gopsutil := NewGopsutilBackend()
+
+	_, procfsCores, err := procfs.CPUTimes(ctx)
+	if err != nil {
+		t.Skipf("no readable procfs: %v", err)
+	}
+	_, gopsutilCores, _ := gopsutil.CPUTimes(ctx)
+	if len(procfsCores) != len(gopsutilCores) {
+		t.Errorf("Expected %d cores as with gopsutil, got %d", len(gopsutilCores), len(procfsCores))
+	}
+
+	procfsMemory, _ := procfs.VirtualMemory(ctx)
+	gopsutilMemory, _ := gopsutil.VirtualMemory(ctx)
+	if procfsMemory.Total != gopsutilMemory.Total {
+		t.Errorf("Expected %d bytes of RAM as with gopsutil, got %d", gopsutilMemory.Total, procfsMemory.Total)
+	}
+
+	procfsNics, _ := procfs.NetIOCounters(ctx)
+	gopsutilNics, _ := gopsutil.NetIOCounters(ctx)
+	names := make(map[string]bool)
+	for _, nic := range gopsutilNics {
+		names[nic.Name] = true
+	}
+	for _, nic := range procfsNics {
+		if !names[nic.Name] {
+			t.Errorf("Expected interface %q to be known to gopsutil", nic.Name)
+		}
+	}
+	if len(procfsNics) != len(gopsutilNics) {
+		t.Errorf("Expected %d interfaces as with gopsutil, got %d", len(gopsutilNics), len(procfsNics))
+	}
+}
+
+// BenchmarkBackends measures one tick of the CPU, memory, disk and network collectors on each
+// backend, the time and allocations of sampling without the UI
+func BenchmarkBackends(b *testing.B) {
+	for _, name := range Backends {
+		b.Run(name, func(b *testing.B) {
+			backend, err := NewBackend(name)
+			if err != nil {
+				b.Skip(err)
+			}
+			if closer, ok := backend.(interface{ Close() error }); ok {
+				defer closer.Close()
+			}
+			collectors := []Collector{
+				NewCPUCollector(DefaultInterval, backend),
+				NewMemoryCollector(DefaultInterval, backend),
+				NewDiskCollector(DefaultInterval, backend),
+				NewNetworkCollector(DefaultInterval, backend),
+			}
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, collector := range collectors {
+					_, _ = collector.Collect(ctx)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBackendReads measures reading the raw counters alone on each backend
+func BenchmarkBackendReads(b *testing.B) {
+	for _, name := range Backends {
+		b.Run(name, func(b *testing.B) {
+			backend, err := NewBackend(name)
+			if err != nil {
+				b.Skip(err)
+			}
+			if closer, ok := backend.(interface{ Close() error }); ok {
+				defer closer.Close()
+			}
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _, _ = backend.CPUTimes(ctx)
+				_, _ = backend.VirtualMemory(ctx)
+				_, _ = backend.SwapMemory(ctx)
+				_, _ = backend.DiskIOCounters(ctx)
+				_, _ = backend.NetIOCounters(ctx)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package collectors
+
+import "sort"
+
+// ProcessTreeRow is a process placed in the parent/child hierarchy
+type ProcessTreeRow struct {
+	ProcessInfo
+	Depth int // Zero for processes without a sampled parent
+}
+
+// ProcessTree orders the processes depth first with children sorted by PID.
+// A non-empty filter keeps the matching processes, their ancestors so they stay
+// in context and their descendants so everything a match spawned is shown.
+func ProcessTree(procs []ProcessInfo, filter string) []ProcessTreeRow {
+	byPID := make(map[int32]ProcessInfo, len(procs))
+	for _, proc := range procs {
+		byPID[proc.PID] = proc
+	}
+
+	children := make(map[int32][]int32, len(procs))
+	var roots []int32
+	for _, proc := range procs {
+		// PID 0 and 1 may name themselves or each other as parent, treat them as roots
+		if _, ok := byPID[proc.PPID]; !ok || proc.PPID == proc.PID || proc.PPID == 0 {
+			roots = append(roots, proc.PID)
+			continue
+		}
+		children[proc.PPID] = append(children[proc.PPID], proc.PID)
+	}
+	sortPIDs(roots)
+	for _, pids := range children {
+		sortPIDs(pids)
+	}
+
+	keep := treeSelection(byPID, children, procs, filter)
+
+	rows := make([]ProcessTreeRow, 0, len(procs))
+	visited := make(map[int32]bool, len(procs))
+	var walk func(pid int32, depth int)
+	walk = func(pid int32, depth int) {
+		if visited[pid] || (keep != nil && !keep[pid]) {
+			return
+		}
+		visited[pid] = true
+		rows = append(rows, ProcessTreeRow{ProcessInfo: byPID[pid], Depth: depth})
+		for _, child := range children[pid] {
+			walk(child, depth+1)
+		}
+	}
+	for _, pid := range roots {
+		walk(pid, 0)
+	}
+	// Processes in a parent loop, seen when a PID is reused between reads, are never reached from a root
+	for _, proc := range procs {
+		walk(proc.PID, 0)
+	}
+	return rows
+}
+
+// treeSelection returns the PIDs kept by the filter, nil when every process is kept
+func treeSelection(byPID map[int32]ProcessInfo, children map[int32][]int32, procs []ProcessInfo, filter string) map[int32]bool {
+	matched := FilterProcesses(procs, filter)
+	if len(matched) == len(procs) {
+		return nil
+	}
+
+	keep := make(map[int32]bool, len(matched))
+	var keepDescendants func(pid int32)
+	keepDescendants = func(pid int32) {
+		for _, child := range children[pid] {
+			if !keep[child] {
+				keep[child] = true
+				keepDescendants(child)
+			}
+		}
+	}
+
+	for _, proc := range matched {
+		keep[proc.PID] = true
+		keepDescendants(proc.PID)
+
+		// Walk up until a root or an already kept ancestor, which also stops on PPID loops
+		for pid := proc.PPID; ; {
+			parent, ok := byPID[pid]
+			if !ok || keep[pid] {
+				break
+			}
+			keep[pid] = true
+			pid = parent.PPID
+		}
+	}
+	return keep
+}
+
+// sortPIDs sorts PIDs in ascending order
+func sortPIDs(pids []int32) {
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+}
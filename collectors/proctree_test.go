@@ -0,0 +1,77 @@
+package collectors
+
+import "testing"
+
+func testProcessTree() []ProcessInfo {
+	return []ProcessInfo{
+		{PID: 1, PPID: 0, Name: "init"},
+		{PID: 50, PPID: 10, Name: "cc1"},
+		{PID: 10, PPID: 1, Name: "make"},
+		{PID: 40, PPID: 10, Name: "ld"},
+		{PID: 20, PPID: 1, Name: "sshd"},
+		{PID: 30, PPID: 20, Name: "bash"},
+		{PID: 60, PPID: 99, Name: "orphan"}, // Parent exited between samples
+		{PID: 70, PPID: 70, Name: "self"},
+	}
+}
+
+func treePIDs(rows []ProcessTreeRow) ([]int32, []int) {
+	pids := make([]int32, len(rows))
+	depths := make([]int, len(rows))
+	for i, row := range rows {
+		pids[i] = row.PID
+		depths[i] = row.Depth
+	}
+	return pids, depths
+}
+
+func TestProcessTree(t *testing.T) {
+	pids, depths := treePIDs(ProcessTree(testProcessTree(), ""))
+
+	wantPIDs := []int32{1, 10, 40, 50, 20, 30, 60, 70}
+	wantDepths := []int{0, 1, 2, 2, 1, 2, 0, 0}
+	if len(pids) != len(wantPIDs) {
+		t.Fatalf("Expected %d rows, got %v", len(wantPIDs), pids)
+	}
+	for i := range wantPIDs {
+		if pids[i] != wantPIDs[i] || depths[i] != wantDepths[i] {
+			t.Errorf("Expected PID %d at depth %d in row %d, got PID %d at depth %d",
+				wantPIDs[i], wantDepths[i], i, pids[i], depths[i])
+		}
+	}
+}
+
+func TestProcessTreeFilter(t *testing.T) {
+	// The match keeps its ancestors and its descendants, but not its siblings
+	pids, depths := treePIDs(ProcessTree(testProcessTree(), "make"))
+
+	wantPIDs := []int32{1, 10, 40, 50}
+	wantDepths := []int{0, 1, 2, 2}
+	if len(pids) != len(wantPIDs) {
+		t.Fatalf("Expected %d rows, got %v", len(wantPIDs), pids)
+	}
+	for i := range wantPIDs {
+		if pids[i] != wantPIDs[i] || depths[i] != wantDepths[i] {
+			t.Errorf("Expected PID %d at depth %d in row %d, got PID %d at depth %d",
+				wantPIDs[i], wantDepths[i], i, pids[i], depths[i])
+		}
+	}
+
+	if rows := ProcessTree(testProcessTree(), "nothing"); len(rows) != 0 {
+		t.Errorf("Expected no rows for a filter without matches, got %d", len(rows))
+	}
+}
+
+func TestProcessTreeParentLoop(t *testing.T) {
+	procs := []ProcessInfo{
+		{PID: 2, PPID: 3, Name: "a"},
+		{PID: 3, PPID: 2, Name: "b"},
+	}
+
+	// Processes in a parent loop have no root; they must be shown once without hanging the walk
+	for _, filter := range []string{"", "a"} {
+		if rows := ProcessTree(procs, filter); len(rows) != 2 {
+			t.Errorf("Expected both processes of the loop for filter %q, got %d rows", filter, len(rows))
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package collectors
+
+import "time"
+
+// bytesPerMB converts byte counts to megabytes
+const bytesPerMB = 1024 * 1024
+
+// counterSample is a counter value together with the time it was read
+type counterSample struct {
+	value uint64
+	at    time.Time
+}
+
+// CounterRate turns monotonically increasing counters into per-second rates.
+// It keeps the previous reading of every counter and divides the delta by the
+// real elapsed time, so callers never need to sleep between two reads.
+type CounterRate struct {
+	prev map[string]counterSample
+}
+
+// NewCounterRate creates an empty CounterRate
+func NewCounterRate() *CounterRate {
+	return &CounterRate{
+		prev: make(map[string]counterSample),
+	}
+}
+
+// Rate records the counter value read at the given time and returns the
+// per-second rate since the previous reading. It returns false on the first
+// reading of a counter, when the counter went backwards (wrap or reset) or
+// when no time has elapsed.
+func (r *CounterRate) Rate(key string, value uint64, now time.Time) (float64, bool) {
+	prev, ok := r.prev[key]
+	r.prev[key] = counterSample{value: value, at: now}
+	if !ok || value < prev.value {
+		return 0, false
+	}
+
+	// time.Time keeps a monotonic clock reading, so wall clock jumps do not skew the rate
+	elapsed := now.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	return float64(value-prev.value) / elapsed, true
+}
+
+// Forget drops the stored readings of counters that were not seen in keep
+func (r *CounterRate) Forget(keep map[string]bool) {
+	for key := range r.prev {
+		if !keep[key] {
+			delete(r.prev, key)
+		}
+	}
+}
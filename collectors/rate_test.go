@@ -0,0 +1,68 @@
+package collectors
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCounterRateFirstReading(t *testing.T) {
+	rate := NewCounterRate()
+
+	if _, ok := rate.Rate("bytes", 1000, time.Now()); ok {
+		t.Error("Expected first reading to produce no rate")
+	}
+}
+
+func TestCounterRateUsesElapsedTime(t *testing.T) {
+	rate := NewCounterRate()
+	start := time.Now()
+
+	rate.Rate("bytes", 1000, start)
+
+	value, ok := rate.Rate("bytes", 3000, start.Add(2*time.Second))
+	if !ok {
+		t.Fatal("Expected second reading to produce a rate")
+	}
+	if value != 1000 {
+		t.Errorf("Expected 1000 per second, got %f", value)
+	}
+
+	value, ok = rate.Rate("bytes", 3500, start.Add(2500*time.Millisecond))
+	if !ok {
+		t.Fatal("Expected third reading to produce a rate")
+	}
+	if value != 1000 {
+		t.Errorf("Expected 1000 per second over 500ms, got %f", value)
+	}
+}
+
+func TestCounterRateReset(t *testing.T) {
+	rate := NewCounterRate()
+	start := time.Now()
+
+	rate.Rate("bytes", 5000, start)
+	if _, ok := rate.Rate("bytes", 100, start.Add(time.Second)); ok {
+		t.Error("Expected counter reset to produce no rate")
+	}
+
+	value, ok := rate.Rate("bytes", 600, start.Add(2*time.Second))
+	if !ok || value != 500 {
+		t.Errorf("Expected rate of 500 after reset, got %f (ok=%v)", value, ok)
+	}
+}
+
+func TestCounterRateForget(t *testing.T) {
+	rate := NewCounterRate()
+	start := time.Now()
+
+	rate.Rate("eth0", 100, start)
+	rate.Rate("eth1", 100, start)
+	rate.Forget(map[string]bool{"eth0": true})
+
+	if _, ok := rate.Rate("eth1", 200, start.Add(time.Second)); ok {
+		t.Error("Expected forgotten counter to start over")
+	}
+	if _, ok := rate.Rate("eth0", 200, start.Add(time.Second)); !ok {
+		t.Error("Expected kept counter to produce a rate")
+	}
+}
@@ -0,0 +1,207 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// intervalSlack is the fraction of a collector interval that may be skipped,
+// so that ticker jitter does not make a collector miss every other tick
+const intervalSlack = 10
+
+// Registry holds collectors and decides which of them are due to run.
+// Due collectors run concurrently, each with its own deadline, so that a slow one does not
+// hold up the others. A collector still running when CollectDue stops waiting is not started
+// again until it finishes, and its samples are returned by a later CollectDue.
+type Registry struct {
+	collectors []Collector
+	reported   map[string]map[string]bool // Metric names of the latest collection of each MetricOwner, by prefix
+
+	mu      sync.Mutex // Guards the fields below, collectors finish after CollectDue returned
+	lastRun map[string]time.Time
+	running map[string]bool
+	late    []collection
+	details map[string]any
+}
+
+// collection is the outcome of one run of a collector
+type collection struct {
+	index     int // Of the collector in registration order
+	collector Collector
+	samples   []Sample
+	detail    any
+	err       error
+}
+
+// NewRegistry creates an empty collector registry
+func NewRegistry() *Registry {
+	return &Registry{
+		reported: make(map[string]map[string]bool),
+		lastRun:  make(map[string]time.Time),
+		running:  make(map[string]bool),
+		details:  make(map[string]any),
+	}
+}
+
+// Register adds a collector to the registry, names must be unique
+func (r *Registry) Register(c Collector) error {
+	for _, existing := range r.collectors {
+		if existing.Name() == c.Name() {
+			return fmt.Errorf("collector %q is already registered", c.Name())
+		}
+	}
+
+	r.collectors = append(r.collectors, c)
+	return nil
+}
+
+// Collectors returns the registered collectors in registration order
+func (r *Registry) Collectors() []Collector {
+	return r.collectors
+}
+
+// CollectDue runs every collector whose interval has elapsed and returns their samples, together
+// with those of collectors that finished late since the previous call. It waits until ctx is done
+// at most. A failing collector does not prevent the others from running; all errors are joined.
+func (r *Registry) CollectDue(ctx context.Context, now time.Time) ([]Sample, error) {
+	results := make(chan collection, len(r.collectors))
+	started := 0
+
+	r.mu.Lock()
+	collections := r.late
+	r.late = nil
+	for i, c := range r.collectors {
+		if r.running[c.Name()] || !r.isDue(c, now) {
+			continue
+		}
+		r.lastRun[c.Name()] = now
+		r.running[c.Name()] = true
+		started++
+		go run(ctx, i, c, results)
+	}
+	r.mu.Unlock()
+
+waiting:
+	for ; started > 0; started-- {
+		select {
+		case result := <-results:
+			collections = append(collections, r.finish(result))
+		case <-ctx.Done():
+			go r.collectLate(results, started)
+			break waiting
+		}
+	}
+
+	sort.SliceStable(collections, func(i, j int) bool { return collections[i].index < collections[j].index })
+
+	var samples []Sample
+	var errs []error
+	for _, result := range collections {
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.collector.Name(), result.err))
+		}
+		samples = append(samples, result.samples...)
+
+		if owner, ok := result.collector.(MetricOwner); ok && result.err == nil {
+			names := make(map[string]bool, len(result.samples))
+			for _, sample := range result.samples {
+				names[sample.Name] = true
+			}
+			r.reported[owner.MetricPrefix()] = names
+		}
+	}
+
+	return samples, errors.Join(errs...)
+}
+
+// Expired reports whether the named metric is owned by a MetricOwner whose latest successful
+// collection did not report it, e.g. the metrics of a removed cgroup
+func (r *Registry) Expired(name string) bool {
+	for prefix, names := range r.reported {
+		if strings.HasPrefix(name, prefix) && !names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// run collects from c within its own deadline, one interval, and sends the outcome to results.
+// The deadline does not end with ctx, so a collector outliving CollectDue still finishes its work.
+func run(ctx context.Context, index int, c Collector, results chan<- collection) {
+	timeout := c.Interval()
+	if timeout <= 0 {
+		timeout = DefaultInterval
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	result := collection{index: index, collector: c}
+	result.samples, result.err = c.Collect(ctx)
+	if dc, ok := c.(DetailCollector); ok {
+		result.detail = dc.Details()
+	}
+	results <- result
+}
+
+// finish records that the collector of result is done and keeps its details
+func (r *Registry) finish(result collection) collection {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := result.collector.Name()
+	r.running[name] = false
+	if _, ok := result.collector.(DetailCollector); ok {
+		r.details[name] = result.detail
+	}
+	return result
+}
+
+// collectLate keeps the outcome of the given number of collectors still running for the next CollectDue
+func (r *Registry) collectLate(results <-chan collection, pending int) {
+	for ; pending > 0; pending-- {
+		result := r.finish(<-results)
+
+		r.mu.Lock()
+		r.late = append(r.late, result)
+		r.mu.Unlock()
+	}
+}
+
+// Details returns the latest structured data of every DetailCollector, keyed by collector name
+func (r *Registry) Details() map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	details := make(map[string]any)
+	for _, c := range r.collectors {
+		dc, ok := c.(DetailCollector)
+		if !ok {
+			continue
+		}
+		detail, collected := r.details[c.Name()]
+		// A running collector may be modifying its details, only those of a finished run are safe
+		if !collected && !r.running[c.Name()] {
+			detail = dc.Details()
+		}
+		if detail != nil {
+			details[c.Name()] = detail
+		}
+	}
+	return details
+}
+
+// isDue reports whether the collector should run at the given time
+func (r *Registry) isDue(c Collector, now time.Time) bool {
+	last, ok := r.lastRun[c.Name()]
+	if !ok {
+		return true
+	}
+
+	interval := c.Interval()
+	return now.Sub(last) >= interval-interval/intervalSlack
+}
@@ -0,0 +1,159 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeCollector struct {
+	name     string
+	interval time.Duration
+	calls    int
+	err      error
+}
+
+func (f *fakeCollector) Name() string {
+	return f.name
+}
+
+func (f *fakeCollector) Interval() time.Duration {
+	return f.interval
+}
+
+func (f *fakeCollector) Collect(_ context.Context) ([]Sample, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []Sample{{Name: f.name + ".value", Value: float64(f.calls)}}, nil
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	registry := NewRegistry()
+
+	if err := registry.Register(&fakeCollector{name: "fake"}); err != nil {
+		t.Fatalf("Expected first registration to succeed, got %v", err)
+	}
+	if err := registry.Register(&fakeCollector{name: "fake"}); err == nil {
+		t.Error("Expected duplicate registration to fail")
+	}
+	if len(registry.Collectors()) != 1 {
+		t.Errorf("Expected 1 collector, got %d", len(registry.Collectors()))
+	}
+}
+
+func TestCollectDueRespectsInterval(t *testing.T) {
+	registry := NewRegistry()
+	fast := &fakeCollector{name: "fast", interval: time.Second}
+	slow := &fakeCollector{name: "slow", interval: 5 * time.Second}
+	_ = registry.Register(fast)
+	_ = registry.Register(slow)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		// Simulate a ticker that fires slightly early
+		now := start.Add(time.Duration(i)*time.Second - 10*time.Millisecond*time.Duration(i))
+		if _, err := registry.CollectDue(context.Background(), now); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if fast.calls != 5 {
+		t.Errorf("Expected fast collector to run 5 times, got %d", fast.calls)
+	}
+	if slow.calls != 1 {
+		t.Errorf("Expected slow collector to run once, got %d", slow.calls)
+	}
+}
+
+func TestCollectDueContinuesAfterError(t *testing.T) {
+	registry := NewRegistry()
+	failing := &fakeCollector{name: "failing", err: errors.New("boom")}
+	working := &fakeCollector{name: "working"}
+	_ = registry.Register(failing)
+	_ = registry.Register(working)
+
+	samples, err := registry.CollectDue(context.Background(), time.Now())
+	if err == nil {
+		t.Error("Expected an error from the failing collector")
+	}
+	if len(samples) != 1 || samples[0].Name != "working.value" {
+		t.Errorf("Expected a single sample from the working collector, got %v", samples)
+	}
+}
+
+// blockingCollector collects only once release is closed
+type blockingCollector struct {
+	fakeCollector
+	release chan struct{}
+}
+
+func (b *blockingCollector) Collect(ctx context.Context) ([]Sample, error) {
+	<-b.release
+	return b.fakeCollector.Collect(ctx)
+}
+
+func TestCollectDueDoesNotWaitForSlowCollectors(t *testing.T) {
+	registry := NewRegistry()
+	slow := &blockingCollector{fakeCollector: fakeCollector{name: "slow"}, release: make(chan struct{})}
+	fast := &fakeCollector{name: "fast"}
+	_ = registry.Register(slow)
+	_ = registry.Register(fast)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	samples, _ := registry.CollectDue(ctx, time.Now())
+	cancel()
+	if len(samples) != 1 || samples[0].Name != "fast.value" {
+		t.Fatalf("Expected the fast sample without waiting for the slow collector, got %v", samples)
+	}
+
+	// The slow collector is not started again while it is still running
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	_, _ = registry.CollectDue(ctx, time.Now().Add(time.Second))
+	cancel()
+
+	close(slow.release)
+	time.Sleep(50 * time.Millisecond)
+	samples, _ = registry.CollectDue(context.Background(), time.Now().Add(2*time.Second))
+	if len(samples) != 3 || samples[0].Name != "slow.value" || samples[0].Value != 1 {
+		t.Errorf("Expected the late slow sample once, then the new ones, got %v", samples)
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	registry := NewRegistry()
+	for _, c := range Builtin() {
+		if err := registry.Register(c); err != nil {
+			t.Errorf("Expected built-in collector %q to register, got %v", c.Name(), err)
+		}
+	}
+	if len(registry.Collectors()) != len(Builtin()) {
+		t.Errorf("Expected %d built-in collectors, got %d", len(Builtin()), len(registry.Collectors()))
+	}
+}
+
+type fakeDetailCollector struct {
+	fakeCollector
+	detail any
+}
+
+func (f *fakeDetailCollector) Details() any {
+	return f.detail
+}
+
+func TestDetails(t *testing.T) {
+	registry := NewRegistry()
+	_ = registry.Register(&fakeCollector{name: "plain"})
+	_ = registry.Register(&fakeDetailCollector{fakeCollector: fakeCollector{name: "table"}, detail: []string{"row"}})
+	_ = registry.Register(&fakeDetailCollector{fakeCollector: fakeCollector{name: "empty"}})
+
+	details := registry.Details()
+	if len(details) != 1 {
+		t.Fatalf("Expected details of one collector, got %v", details)
+	}
+	if rows, ok := details["table"].([]string); !ok || len(rows) != 1 {
+		t.Errorf("Expected table details, got %v", details["table"])
+	}
+}
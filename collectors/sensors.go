@@ -0,0 +1,324 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/host"
+)
+
+// Sensor metrics not tied to a single sensor
+const (
+	MetricSensorCPU      = "sensor.cpu"      // °C of the CPU package, or of the hottest sensor when there is none
+	MetricSensorHottest  = "sensor.hottest"  // °C of the hottest temperature sensor
+	MetricSensorOverHigh = "sensor.overhigh" // temperature sensors at or above their high threshold
+	MetricSensorThrottle = "sensor.throttle" // CPU thermal throttling events/s
+)
+
+// Kinds of sensors
+const (
+	SensorTemperature = "temp" // °C
+	SensorFan         = "fan"  // RPM
+)
+
+// SensorMetric returns the name of the metric of one sensor
+func SensorMetric(kind, key string) string {
+	return "sensor." + kind + "." + key
+}
+
+// SensorReading is the latest value of one temperature or fan sensor
+type SensorReading struct {
+	Key      string // Unique name made of the chip and the sensor label
+	Chip     string
+	Label    string
+	Kind     string  // SensorTemperature or SensorFan
+	Value    float64 // °C or RPM
+	High     float64 // Zero when the sensor has no high threshold
+	Critical float64 // Zero when the sensor has no critical threshold
+}
+
+// OverHigh reports whether a temperature has reached its high threshold
+func (r SensorReading) OverHigh() bool {
+	return r.Kind == SensorTemperature && r.High > 0 && r.Value >= r.High
+}
+
+// OverCritical reports whether a temperature has reached its critical threshold
+func (r SensorReading) OverCritical() bool {
+	return r.Kind == SensorTemperature && r.Critical > 0 && r.Value >= r.Critical
+}
+
+// SensorsCollector reads temperature and fan sensors from hwmon and thermal zones in sysfs,
+// or from the platform sensors where there is no sysfs
+type SensorsCollector struct {
+	interval time.Duration
+	sysPath  string
+	rates    *CounterRate
+	readings []SensorReading
+}
+
+// NewSensorsCollector creates a new SensorsCollector reading the sysfs mounted at sysPath
+func NewSensorsCollector(interval time.Duration, sysPath string) *SensorsCollector {
+	return &SensorsCollector{
+		interval: interval,
+		sysPath:  sysPath,
+		rates:    NewCounterRate(),
+	}
+}
+
+// Name returns the collector name
+func (s *SensorsCollector) Name() string {
+	return "sensors"
+}
+
+// Interval returns the collection interval
+func (s *SensorsCollector) Interval() time.Duration {
+	return s.interval
+}
+
+// Collect reads every sensor and reports its value along with the CPU and hottest temperatures
+func (s *SensorsCollector) Collect(ctx context.Context) ([]Sample, error) {
+	var readings []SensorReading
+	if _, err := os.Stat(filepath.Join(s.sysPath, "class")); err == nil {
+		readings = append(readHwmonSensors(filepath.Join(s.sysPath, "class", "hwmon")),
+			readThermalZones(filepath.Join(s.sysPath, "class", "thermal"))...)
+	} else {
+		temps, err := host.SensorsTemperaturesWithContext(ctx)
+		if err != nil && len(temps) == 0 {
+			return nil, err
+		}
+		for _, temp := range temps {
+			readings = append(readings, SensorReading{Label: temp.SensorKey, Kind: SensorTemperature, Value: temp.Temperature})
+		}
+	}
+	readings = uniqueSensorKeys(readings)
+	s.readings = readings
+
+	samples := sensorSamples(readings)
+	if throttles, ok := readThrottleCount(filepath.Join(s.sysPath, "devices", "system", "cpu")); ok {
+		perSecond, _ := s.rates.Rate("throttle", throttles, time.Now())
+		samples = append(samples, Sample{Name: MetricSensorThrottle, Value: perSecond})
+	}
+	return samples, nil
+}
+
+// Details returns the sensor readings of the latest collection in chip order
+func (s *SensorsCollector) Details() any {
+	return s.readings
+}
+
+// sensorSamples computes the per-sensor samples and the summary temperatures
+func sensorSamples(readings []SensorReading) []Sample {
+	samples := make([]Sample, 0, len(readings)+3)
+	var hottest float64
+	var overHigh int
+	for _, reading := range readings {
+		samples = append(samples, Sample{Name: SensorMetric(reading.Kind, reading.Key), Value: reading.Value})
+		if reading.Kind != SensorTemperature {
+			continue
+		}
+		if reading.Value > hottest {
+			hottest = reading.Value
+		}
+		if reading.OverHigh() {
+			overHigh++
+		}
+	}
+
+	cpu := hottest
+	if reading, ok := cpuSensor(readings); ok {
+		cpu = reading.Value
+	}
+	return append(samples,
+		Sample{Name: MetricSensorCPU, Value: cpu},
+		Sample{Name: MetricSensorHottest, Value: hottest},
+		Sample{Name: MetricSensorOverHigh, Value: float64(overHigh)},
+	)
+}
+
+// cpuSensor returns the sensor measuring the CPU package, preferring the CPU drivers over thermal zones
+func cpuSensor(readings []SensorReading) (SensorReading, bool) {
+	preferences := []func(SensorReading) bool{
+		func(r SensorReading) bool { return r.Chip == "coretemp" && strings.HasPrefix(r.Label, "Package id") },
+		func(r SensorReading) bool { return r.Chip == "k10temp" && (r.Label == "Tctl" || r.Label == "Tdie") },
+		func(r SensorReading) bool { return r.Chip == "zenpower" && r.Label == "Tdie" },
+		func(r SensorReading) bool { return r.Chip == "cpu_thermal" || r.Chip == "cpu-thermal" },
+		func(r SensorReading) bool { return r.Chip == "thermal" && r.Label == "x86_pkg_temp" },
+	}
+	for _, preferred := range preferences {
+		for _, reading := range readings {
+			if reading.Kind == SensorTemperature && preferred(reading) {
+				return reading, true
+			}
+		}
+	}
+	return SensorReading{}, false
+}
+
+// readHwmonSensors reads the temperature and fan inputs of every hwmon chip, sorted by chip
+func readHwmonSensors(hwmonPath string) []SensorReading {
+	chips, _ := filepath.Glob(filepath.Join(hwmonPath, "hwmon*"))
+	sort.Slice(chips, func(i, j int) bool { return naturalLess(chips[i], chips[j]) })
+
+	var readings []SensorReading
+	for _, chipPath := range chips {
+		chip := readSysString(filepath.Join(chipPath, "name"))
+		// Older drivers keep their attributes in the device directory
+		dir := chipPath
+		if inputs, _ := filepath.Glob(filepath.Join(dir, "*_input")); len(inputs) == 0 {
+			dir = filepath.Join(chipPath, "device")
+		}
+
+		inputs, _ := filepath.Glob(filepath.Join(dir, "*_input"))
+		sort.Slice(inputs, func(i, j int) bool { return naturalLess(inputs[i], inputs[j]) })
+		for _, input := range inputs {
+			sensor := strings.TrimSuffix(filepath.Base(input), "_input") // e.g. temp1 or fan2
+			reading := SensorReading{Chip: chip, Label: sensor}
+			if label := readSysString(filepath.Join(dir, sensor+"_label")); label != "" {
+				reading.Label = label
+			}
+
+			value, ok := readSysNumber(input)
+			if !ok {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(sensor, "temp"):
+				// Temperatures are in millidegrees Celsius
+				reading.Kind = SensorTemperature
+				reading.Value = value / 1000
+				if high, ok := readSysNumber(filepath.Join(dir, sensor+"_max")); ok {
+					reading.High = high / 1000
+				}
+				if critical, ok := readSysNumber(filepath.Join(dir, sensor+"_crit")); ok {
+					reading.Critical = critical / 1000
+				}
+			case strings.HasPrefix(sensor, "fan"):
+				reading.Kind = SensorFan
+				reading.Value = value
+			default:
+				continue
+			}
+			readings = append(readings, reading)
+		}
+	}
+	return readings
+}
+
+// readThermalZones reads the temperature of every thermal zone with its passive and critical trip points
+func readThermalZones(thermalPath string) []SensorReading {
+	zones, _ := filepath.Glob(filepath.Join(thermalPath, "thermal_zone*"))
+	sort.Slice(zones, func(i, j int) bool { return naturalLess(zones[i], zones[j]) })
+
+	var readings []SensorReading
+	for _, zone := range zones {
+		value, ok := readSysNumber(filepath.Join(zone, "temp"))
+		if !ok {
+			continue
+		}
+		reading := SensorReading{
+			Chip:  "thermal",
+			Label: readSysString(filepath.Join(zone, "type")),
+			Kind:  SensorTemperature,
+			Value: value / 1000,
+		}
+		if reading.Label == "" {
+			reading.Label = filepath.Base(zone)
+		}
+
+		// The lowest passive or hot trip point is where the system starts cooling down by throttling
+		trips, _ := filepath.Glob(filepath.Join(zone, "trip_point_*_type"))
+		for _, trip := range trips {
+			temp, ok := readSysNumber(strings.TrimSuffix(trip, "_type") + "_temp")
+			if !ok || temp <= 0 {
+				continue
+			}
+			switch readSysString(trip) {
+			case "passive", "hot":
+				if reading.High == 0 || temp/1000 < reading.High {
+					reading.High = temp / 1000
+				}
+			case "critical":
+				reading.Critical = temp / 1000
+			}
+		}
+		readings = append(readings, reading)
+	}
+	return readings
+}
+
+// readThrottleCount sums the thermal throttling counters of every CPU, false when the kernel has none
+func readThrottleCount(cpuPath string) (uint64, bool) {
+	counters, _ := filepath.Glob(filepath.Join(cpuPath, "cpu*", "thermal_throttle", "*_throttle_count"))
+	if len(counters) == 0 {
+		return 0, false
+	}
+
+	var total uint64
+	for _, counter := range counters {
+		if value, ok := readSysNumber(counter); ok {
+			total += uint64(value)
+		}
+	}
+	return total, true
+}
+
+// uniqueSensorKeys sets the sensor keys, numbering sensors whose chip and label repeat,
+// as with several NVMe drives
+func uniqueSensorKeys(readings []SensorReading) []SensorReading {
+	seen := make(map[string]int, len(readings))
+	for i := range readings {
+		key := strings.TrimSpace(readings[i].Chip + " " + readings[i].Label)
+		seen[key]++
+		if seen[key] > 1 {
+			key = fmt.Sprintf("%s #%d", key, seen[key])
+		}
+		readings[i].Key = key
+	}
+	return readings
+}
+
+// readSysString reads a sysfs attribute without the trailing newline, empty when it cannot be read
+func readSysString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readSysNumber reads a numeric sysfs attribute
+func readSysNumber(path string) (float64, bool) {
+	value, err := strconv.ParseFloat(readSysString(path), 64)
+	return value, err == nil
+}
+
+// naturalLess orders paths so that hwmon10 follows hwmon9 and temp10 follows temp9
+func naturalLess(a, b string) bool {
+	prefixA, numberA := splitTrailingNumber(a)
+	prefixB, numberB := splitTrailingNumber(b)
+	if prefixA != prefixB {
+		return a < b
+	}
+	return numberA < numberB
+}
+
+// splitTrailingNumber splits the digits of the last path element off, e.g. "temp12_input" into "temp_input" and 12
+func splitTrailingNumber(path string) (string, int) {
+	start := strings.IndexAny(path[strings.LastIndexByte(path, '/')+1:], "0123456789")
+	if start < 0 {
+		return path, 0
+	}
+	start += strings.LastIndexByte(path, '/') + 1
+	end := start
+	for end < len(path) && path[end] >= '0' && path[end] <= '9' {
+		end++
+	}
+	number, _ := strconv.Atoi(path[start:end])
+	return path[:start] + path[end:], number
+}
@@ -0,0 +1,143 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSysfs creates the files of a fake sysfs below root
+func writeSysfs(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSensorsCollector(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "acpitz",
+		"class/hwmon/hwmon0/temp1_input": "27800",
+		"class/hwmon/hwmon1/name":        "coretemp",
+		"class/hwmon/hwmon1/temp1_input": "91000",
+		"class/hwmon/hwmon1/temp1_label": "Package id 0",
+		"class/hwmon/hwmon1/temp1_max":   "90000",
+		"class/hwmon/hwmon1/temp1_crit":  "100000",
+		"class/hwmon/hwmon1/temp2_input": "55000",
+		"class/hwmon/hwmon1/temp2_label": "Core 0",
+		"class/hwmon/hwmon1/temp2_max":   "90000",
+		// Two identical drives, the second one gets a numbered key
+		"class/hwmon/hwmon2/name":         "nvme",
+		"class/hwmon/hwmon2/temp1_input":  "44850",
+		"class/hwmon/hwmon2/temp1_label":  "Composite",
+		"class/hwmon/hwmon10/name":        "nvme",
+		"class/hwmon/hwmon10/temp1_input": "39850",
+		"class/hwmon/hwmon10/temp1_label": "Composite",
+		// Older drivers keep their inputs in the device directory
+		"class/hwmon/hwmon3/name":                                         "thinkpad",
+		"class/hwmon/hwmon3/device/fan1_input":                            "2890",
+		"class/thermal/thermal_zone0/type":                                "x86_pkg_temp",
+		"class/thermal/thermal_zone0/temp":                                "92000",
+		"class/thermal/thermal_zone0/trip_point_0_type":                   "passive",
+		"class/thermal/thermal_zone0/trip_point_0_temp":                   "95000",
+		"class/thermal/thermal_zone0/trip_point_1_type":                   "critical",
+		"class/thermal/thermal_zone0/trip_point_1_temp":                   "105000",
+		"devices/system/cpu/cpu0/thermal_throttle/core_throttle_count":    "3",
+		"devices/system/cpu/cpu1/thermal_throttle/package_throttle_count": "4",
+	})
+
+	collector := NewSensorsCollector(DefaultInterval, root)
+	samples, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+	expected := map[string]float64{
+		SensorMetric(SensorTemperature, "acpitz temp1"):         27.8,
+		SensorMetric(SensorTemperature, "coretemp Core 0"):      55,
+		SensorMetric(SensorTemperature, "nvme Composite"):       44.85,
+		SensorMetric(SensorTemperature, "nvme Composite #2"):    39.85,
+		SensorMetric(SensorFan, "thinkpad fan1"):                2890,
+		SensorMetric(SensorTemperature, "thermal x86_pkg_temp"): 92,
+		// The coretemp package wins over the hotter thermal zone
+		MetricSensorCPU:      91,
+		MetricSensorHottest:  92,
+		MetricSensorOverHigh: 1,
+		// The first collection has no throttling rate yet
+		MetricSensorThrottle: 0,
+	}
+	for name, want := range expected {
+		if got, ok := values[name]; !ok || got != want {
+			t.Errorf("Expected %s to be %f, got %f (present %v)", name, want, got, ok)
+		}
+	}
+
+	readings, ok := collector.Details().([]SensorReading)
+	if !ok || len(readings) != 7 {
+		t.Fatalf("Expected 7 sensor readings, got %v", collector.Details())
+	}
+	// Chips are ordered numerically, so hwmon10 comes last among the hwmon chips
+	if readings[5].Key != "nvme Composite #2" {
+		t.Errorf("Expected the sixth reading to be the second drive, got %q", readings[5].Key)
+	}
+	pkg := readings[1]
+	if pkg.High != 90 || pkg.Critical != 100 || !pkg.OverHigh() || pkg.OverCritical() {
+		t.Errorf("Unexpected package thresholds %+v", pkg)
+	}
+	zone := readings[6]
+	if zone.High != 95 || zone.Critical != 105 || zone.OverHigh() {
+		t.Errorf("Unexpected thermal zone trip points %+v", zone)
+	}
+}
+
+func TestCPUSensor(t *testing.T) {
+	readings := []SensorReading{
+		{Chip: "thermal", Label: "x86_pkg_temp", Kind: SensorTemperature, Value: 60},
+		{Chip: "k10temp", Label: "Tccd1", Kind: SensorTemperature, Value: 70},
+		{Chip: "k10temp", Label: "Tctl", Kind: SensorTemperature, Value: 65},
+	}
+	if reading, ok := cpuSensor(readings); !ok || reading.Label != "Tctl" {
+		t.Errorf("Expected Tctl as the CPU sensor, got %+v", reading)
+	}
+
+	// Without a known CPU sensor the CPU temperature is the hottest one
+	samples := sensorSamples([]SensorReading{
+		{Key: "nvme Composite", Kind: SensorTemperature, Value: 48},
+		{Key: "acpitz temp1", Kind: SensorTemperature, Value: 52},
+		{Key: "fan fan1", Kind: SensorFan, Value: 3000},
+	})
+	for _, sample := range samples {
+		if sample.Name == MetricSensorCPU && sample.Value != 52 {
+			t.Errorf("Expected the CPU temperature to fall back to the hottest sensor, got %f", sample.Value)
+		}
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"/sys/class/hwmon/hwmon9", "/sys/class/hwmon/hwmon10", true},
+		{"/sys/class/hwmon/hwmon10", "/sys/class/hwmon/hwmon9", false},
+		{"hwmon1/temp10_input", "hwmon1/temp2_input", false},
+		{"hwmon1/fan1_input", "hwmon1/temp1_input", true},
+	}
+	for _, c := range cases {
+		if got := naturalLess(c.a, c.b); got != c.want {
+			t.Errorf("Expected naturalLess(%q, %q) to be %v", c.a, c.b, c.want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+// WatchPrefix starts the names of all watched process group metrics
+const WatchPrefix = "watch."
+
+// Kinds of watched process group metrics, each summed over the processes of the group
+const (
+	WatchCount   = "count"   // matching processes
+	WatchCPU     = "cpu"     // percent of one CPU
+	WatchRSS     = "rss"     // resident memory in MB
+	WatchFDs     = "fds"     // open file descriptors
+	WatchThreads = "threads" // threads
+	WatchRead    = "read"    // MB/s read
+	WatchWrite   = "write"   // MB/s written
+)
+
+// watchMetricKinds lists the watched process group metric kinds in reporting order
+var watchMetricKinds = []string{WatchCount, WatchCPU, WatchRSS, WatchFDs, WatchThreads, WatchRead, WatchWrite}
+
+// WatchMetric returns the name of a metric of the given kind for one watched process group
+func WatchMetric(group, kind string) string {
+	return WatchPrefix + group + "." + kind
+}
+
+// WatchKind names how a watched process group picks its processes
+type WatchKind string
+
+// Ways of picking the processes of a watched group
+const (
+	WatchByPID     WatchKind = "pid"   // the process with the given PID
+	WatchByName    WatchKind = "name"  // every process with the given executable name
+	WatchByPattern WatchKind = "regex" // every process whose command line matches the expression
+)
+
+// WatchGroup is a set of processes whose usage is tracked together
+type WatchGroup struct {
+	Name    string // Shown in the UI and part of the metric names
+	Kind    WatchKind
+	PID     int32
+	Exe     string
+	Pattern *regexp.Regexp
+}
+
+// NewWatchGroup creates a watched process group from a command line value
+func NewWatchGroup(kind WatchKind, value string) (WatchGroup, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return WatchGroup{}, fmt.Errorf("empty %s to watch", kind)
+	}
+
+	switch kind {
+	case WatchByPID:
+		pid, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || pid <= 0 {
+			return WatchGroup{}, fmt.Errorf("invalid PID to watch %q", value)
+		}
+		return WatchGroup{Name: "pid " + value, Kind: kind, PID: int32(pid)}, nil
+	case WatchByName:
+		return WatchGroup{Name: value, Kind: kind, Exe: value}, nil
+	case WatchByPattern:
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return WatchGroup{}, fmt.Errorf("invalid pattern to watch %q: %w", value, err)
+		}
+		return WatchGroup{Name: "/" + value + "/", Kind: kind, Pattern: pattern}, nil
+	default:
+		return WatchGroup{}, fmt.Errorf("unknown watch kind %q", kind)
+	}
+}
+
+// Matches reports whether a process with the given name, executable path and arguments belongs to the group
+func (g WatchGroup) Matches(pid int32, name, exe string, cmdline []string) bool {
+	switch g.Kind {
+	case WatchByPID:
+		return pid == g.PID
+	case WatchByName:
+		if name == g.Exe || (exe != "" && filepath.Base(exe) == g.Exe) {
+			return true
+		}
+		return len(cmdline) > 0 && filepath.Base(cmdline[0]) == g.Exe
+	case WatchByPattern:
+		// Kernel threads have no command line, match their name instead
+		if len(cmdline) == 0 {
+			return g.Pattern.MatchString(name)
+		}
+		return g.Pattern.MatchString(strings.Join(cmdline, " "))
+	}
+	return false
+}
+
+// watchUsage is the resource usage of one process counted in the given groups
+type watchUsage struct {
+	groups  []int // Indexes of the groups the process belongs to
+	cpu     float64
+	rss     float64
+	fds     float64
+	threads float64
+	read    float64
+	write   float64
+}
+
+// watchedProcess caches which groups a process belongs to, tied to its start time in case the PID is reused
+// and to its name and executable, which change when a wrapper execs the real binary under the same PID
+type watchedProcess struct {
+	createTime int64
+	name       string
+	exe        string
+	groups     []int
+}
+
+// WatchCollector tracks the summed usage of the processes in each watched group
+type WatchCollector struct {
+	interval  time.Duration
+	groups    []WatchGroup
+	rates     *ProcessRates
+	processes map[int32]watchedProcess
+}
+
+// NewWatchCollector creates a new WatchCollector
+func NewWatchCollector(groups []WatchGroup, interval time.Duration) *WatchCollector {
+	return &WatchCollector{
+		interval:  interval,
+		groups:    groups,
+		rates:     NewProcessRates(),
+		processes: make(map[int32]watchedProcess),
+	}
+}
+
+// Name returns the collector name
+func (w *WatchCollector) Name() string {
+	return "watch"
+}
+
+// Interval returns the collection interval
+func (w *WatchCollector) Interval() time.Duration {
+	return w.interval
+}
+
+// Collect sums the usage of the processes of every watched group
+func (w *WatchCollector) Collect(ctx context.Context) ([]Sample, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	var usages []watchUsage
+	seenPIDs := make(map[int32]bool, len(procs))
+	for _, proc := range procs {
+		// Past the deadline the processes read so far are summed
+		if ctx.Err() != nil {
+			break
+		}
+		createTime, err := proc.CreateTimeWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		// A process that has already exited matches no group
+		name, err := proc.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		exe, _ := proc.ExeWithContext(ctx)
+		seenPIDs[proc.Pid] = true
+
+		watched, ok := w.processes[proc.Pid]
+		if !ok || watched.createTime != createTime || watched.name != name || watched.exe != exe {
+			watched = watchedProcess{
+				createTime: createTime,
+				name:       name,
+				exe:        exe,
+				groups:     w.matchingGroups(ctx, proc, name, exe),
+			}
+			w.processes[proc.Pid] = watched
+		}
+		if len(watched.groups) == 0 {
+			continue
+		}
+
+		rates := w.rates.Read(ctx, proc, createTime, now)
+		usage := watchUsage{
+			groups: watched.groups,
+			cpu:    rates.CPUPercent,
+			read:   rates.ReadRate / bytesPerMB,
+			write:  rates.WriteRate / bytesPerMB,
+		}
+		if mem, err := proc.MemoryInfoWithContext(ctx); err == nil {
+			usage.rss = float64(mem.RSS) / bytesPerMB
+		}
+		// Descriptors of other users' processes are only readable with elevated privileges
+		if fds, err := proc.NumFDsWithContext(ctx); err == nil {
+			usage.fds = float64(fds)
+		}
+		if threads, err := proc.NumThreadsWithContext(ctx); err == nil {
+			usage.threads = float64(threads)
+		}
+		usages = append(usages, usage)
+	}
+
+	// Processes left unread are not gone, keep their counters and groups
+	interrupted := ctx.Err()
+	if interrupted == nil {
+		w.rates.Forget()
+		for pid := range w.processes {
+			if !seenPIDs[pid] {
+				delete(w.processes, pid)
+			}
+		}
+	}
+
+	return watchSamples(w.groups, usages), interrupted
+}
+
+// matchingGroups returns the indexes of the groups the process with the given name and executable belongs to
+func (w *WatchCollector) matchingGroups(ctx context.Context, proc *process.Process, name, exe string) []int {
+	cmdline, _ := proc.CmdlineSliceWithContext(ctx)
+
+	var groups []int
+	for i, group := range w.groups {
+		if group.Matches(proc.Pid, name, exe, cmdline) {
+			groups = append(groups, i)
+		}
+	}
+	return groups
+}
+
+// watchSamples sums the usage of the processes per group; groups without processes report zeros
+func watchSamples(groups []WatchGroup, usages []watchUsage) []Sample {
+	totals := make([]map[string]float64, len(groups))
+	for i := range totals {
+		totals[i] = make(map[string]float64, len(watchMetricKinds))
+	}
+	for _, usage := range usages {
+		for _, i := range usage.groups {
+			totals[i][WatchCount]++
+			totals[i][WatchCPU] += usage.cpu
+			totals[i][WatchRSS] += usage.rss
+			totals[i][WatchFDs] += usage.fds
+			totals[i][WatchThreads] += usage.threads
+			totals[i][WatchRead] += usage.read
+			totals[i][WatchWrite] += usage.write
+		}
+	}
+
+	samples := make([]Sample, 0, len(groups)*len(watchMetricKinds))
+	for i, group := range groups {
+		for _, kind := range watchMetricKinds {
+			samples = append(samples, Sample{Name: WatchMetric(group.Name, kind), Value: totals[i][kind]})
+		}
+	}
+	return samples
+}
@@ -0,0 +1,178 @@
+package collectors
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+func TestNewWatchGroup(t *testing.T) {
+	cases := []struct {
+		kind    WatchKind
+		value   string
+		name    string
+		wantErr bool
+	}{
+		{WatchByPID, "42", "pid 42", false},
+		{WatchByPID, "abc", "", true},
+		{WatchByPID, "-1", "", true},
+		{WatchByName, " gopls ", "gopls", false},
+		{WatchByName, "", "", true},
+		{WatchByPattern, "postgres: .*", "/postgres: .*/", false},
+		{WatchByPattern, "(", "", true},
+		{WatchKind("user"), "dev", "", true},
+	}
+	for _, c := range cases {
+		group, err := NewWatchGroup(c.kind, c.value)
+		if (err != nil) != c.wantErr {
+			t.Errorf("Expected error %v for %s %q, got %v", c.wantErr, c.kind, c.value, err)
+			continue
+		}
+		if err == nil && group.Name != c.name {
+			t.Errorf("Expected group name %q for %s %q, got %q", c.name, c.kind, c.value, group.Name)
+		}
+	}
+}
+
+func TestWatchGroupMatches(t *testing.T) {
+	byPID, _ := NewWatchGroup(WatchByPID, "42")
+	byName, _ := NewWatchGroup(WatchByName, "gopls")
+	byPattern, _ := NewWatchGroup(WatchByPattern, `postgres: \w+ writer`)
+
+	cases := []struct {
+		group   WatchGroup
+		pid     int32
+		name    string
+		exe     string
+		cmdline []string
+		want    bool
+	}{
+		{byPID, 42, "anything", "", nil, true},
+		{byPID, 43, "anything", "", nil, false},
+		{byName, 1, "gopls", "", nil, true},
+		{byName, 1, "go", "/home/dev/go/bin/gopls", nil, true},                 // Renamed process, matched by its executable
+		{byName, 1, "node", "/usr/bin/node", []string{"gopls", "serve"}, true}, // Matched by its first argument
+		{byName, 1, "goplsx", "/usr/bin/goplsx", []string{"goplsx"}, false},
+		{byPattern, 1, "postgres", "", []string{"postgres:", "checkpointer"}, false},
+		{byPattern, 1, "postgres", "", []string{"postgres:", "background", "writer"}, true},
+		{byPattern, 1, "postgres: wal writer", "", nil, true}, // Without a command line the name is matched
+	}
+	for _, c := range cases {
+		if got := c.group.Matches(c.pid, c.name, c.exe, c.cmdline); got != c.want {
+			t.Errorf("Expected %s to match %q %q %v: %v, got %v", c.group.Name, c.name, c.exe, c.cmdline, c.want, got)
+		}
+	}
+}
+
+func TestWatchSamples(t *testing.T) {
+	groups := []WatchGroup{{Name: "gopls"}, {Name: "postgres"}, {Name: "idle"}}
+	usages := []watchUsage{
+		{groups: []int{0}, cpu: 50, rss: 300, fds: 40, threads: 12, read: 1, write: 2},
+		{groups: []int{1}, cpu: 5, rss: 100, fds: 10, threads: 1},
+		{groups: []int{1}, cpu: 10, rss: 200, fds: 20, threads: 1, write: 4},
+		// A process may be in several groups
+		{groups: []int{0, 1}, cpu: 1, rss: 1},
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range watchSamples(groups, usages) {
+		values[sample.Name] = sample.Value
+	}
+	if len(values) != len(groups)*len(watchMetricKinds) {
+		t.Errorf("Expected every kind for every group, got %d samples", len(values))
+	}
+
+	want := map[string]float64{
+		WatchMetric("gopls", WatchCount):      2,
+		WatchMetric("gopls", WatchCPU):        51,
+		WatchMetric("gopls", WatchFDs):        40,
+		WatchMetric("postgres", WatchCount):   3,
+		WatchMetric("postgres", WatchRSS):     301,
+		WatchMetric("postgres", WatchThreads): 2,
+		WatchMetric("postgres", WatchWrite):   4,
+		WatchMetric("idle", WatchCount):       0,
+	}
+	for name, value := range want {
+		if values[name] != value {
+			t.Errorf("Expected %s to be %.1f, got %.1f", name, value, values[name])
+		}
+	}
+}
+
+func TestWatchCollectorCollect(t *testing.T) {
+	self, err := NewWatchGroup(WatchByPID, strconv.Itoa(os.Getpid()))
+	if err != nil {
+		t.Fatalf("Expected a group for the test process, got %v", err)
+	}
+	collector := NewWatchCollector([]WatchGroup{self}, DefaultInterval)
+
+	// The second collection has counters to compute rates from
+	for i := 0; i < 2; i++ {
+		samples, err := collector.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Expected the collection to succeed, got %v", err)
+		}
+
+		values := make(map[string]float64)
+		for _, sample := range samples {
+			values[sample.Name] = sample.Value
+		}
+		if values[WatchMetric(self.Name, WatchCount)] != 1 {
+			t.Errorf("Expected the test process to be watched, got %.0f processes", values[WatchMetric(self.Name, WatchCount)])
+		}
+		if values[WatchMetric(self.Name, WatchRSS)] <= 0 || values[WatchMetric(self.Name, WatchThreads)] <= 0 {
+			t.Errorf("Expected memory and threads of the test process, got %v", values)
+		}
+	}
+}
+
+func TestWatchCollectorRematchesAfterExec(t *testing.T) {
+	self, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := self.Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	createTime, err := self.CreateTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	group, err := NewWatchGroup(WatchByName, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collector := NewWatchCollector([]WatchGroup{group}, DefaultInterval)
+
+	// Seen as the wrapper shell before it exec'd the test binary under the same PID and start time
+	collector.processes[self.Pid] = watchedProcess{createTime: createTime, name: "sh", exe: "/bin/sh"}
+
+	samples, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the collection to succeed, got %v", err)
+	}
+	for _, sample := range samples {
+		if sample.Name == WatchMetric(group.Name, WatchCount) && sample.Value < 1 {
+			t.Errorf("Expected the exec'd test process to be matched again, got %.0f processes", sample.Value)
+		}
+	}
+}
+
+func TestWatchCollectorKeepsStateWhenInterrupted(t *testing.T) {
+	collector := NewWatchCollector(nil, DefaultInterval)
+	collector.processes[-1] = watchedProcess{createTime: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := collector.Collect(ctx); err == nil {
+		t.Error("Expected the interrupted collection to report the deadline")
+	}
+	// The walk did not reach every process, so none is known to be gone
+	if _, ok := collector.processes[-1]; !ok {
+		t.Error("Expected the processes left unread to be kept")
+	}
+}
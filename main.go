@@ -1,26 +1,53 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 
+	"go-dummy-monitor/collectors"
 	"go-dummy-monitor/constants"
+	"go-dummy-monitor/procctl"
 	"go-dummy-monitor/ui"
 	"go-dummy-monitor/utils"
 )
 
 // Application-specific constants
 const (
-	DATA_POINTS = 11 // Number of data points to track
+	APP_ID      = "com.github/source-c/go-dummy-monitor" // Same as FyneApp.toml, required for preferences
+	DATA_POINTS = 11                                     // Number of data points to track
 )
 
 func main() {
-	a := app.New()
+	// "run -- command" records a command instead of opening the monitor
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		os.Exit(runCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	opts, err := parseOptions(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Before anything reads from the host
+	if err := opts.apply(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	collectors.BuiltinFilesystemFilter = opts.filesystems
+
+	a := app.NewWithID(APP_ID)
 	w := a.NewWindow("GO System Monitor")
 	darkMode := false
 
@@ -37,8 +64,39 @@ func main() {
 		DATA_POINTS,              // Number of data points to track
 	)
 
-	// Create widget factory
-	widgetFactory := ui.NewWidgetFactory(monitorSystem)
+	// Track the process groups given on the command line
+	if len(opts.watch) > 0 {
+		_ = monitorSystem.RegisterCollector(collectors.NewWatchCollector(opts.watch, collectors.DefaultInterval))
+	}
+
+	// Remember selector choices, such as the shown network interface, across restarts
+	monitorSystem.PersistSelections(a.Preferences())
+
+	// Record process actions in the user configuration directory, or only in memory when it is unavailable
+	auditLog := procctl.NewAuditLog(nil)
+	if path, err := procctl.DefaultAuditLogPath(); err == nil {
+		if opened, err := procctl.OpenAuditLog(path); err == nil {
+			auditLog = opened
+		}
+	}
+	// PIDs of a host procfs in another PID namespace would signal the wrong processes, so actions are left out
+	var processActions *ui.ProcessActions
+	if utils.SharesHostPIDs() {
+		processActions = ui.NewProcessActions(monitorSystem, auditLog, w)
+	}
+
+	// The battery widget is only worth its space on machines with a battery
+	hasBattery := collectors.HasBattery(utils.HostSys())
+
+	// Create widget factory, again on theme changes to pick up the new colors
+	newWidgetFactory := func() *ui.WidgetFactory {
+		factory := ui.NewWidgetFactory(monitorSystem)
+		factory.Actions = processActions
+		factory.Watched = opts.watch
+		factory.Battery = hasBattery
+		return factory
+	}
+	widgetFactory := newWidgetFactory()
 
 	// Variables to track layout state
 	showDetailColumns := true
@@ -80,11 +138,11 @@ func main() {
 	// Create monitoring panel
 	monitoringPanel := ui.NewMonitoringPanel(monitorSystem, widgetFactory, showDetailColumns)
 
+	// Scroll the panel vertically since it holds more widgets than fit on small screens
+	panelScroll := container.NewVScroll(monitoringPanel.Container)
+
 	// Create a container with padding and proper spacing that will be updated
-	content := container.New(layout.NewVBoxLayout(),
-		container.NewPadded(themeButton),
-		monitoringPanel.Container,
-	)
+	content := container.NewBorder(container.NewPadded(themeButton), nil, nil, nil, panelScroll)
 
 	// Update the theme button callback to use the monitoring panel
 	themeButton.OnTapped = func() {
@@ -98,11 +156,11 @@ func main() {
 		}
 
 		// Recreate the widget factory to get updated colors
-		widgetFactory = ui.NewWidgetFactory(monitorSystem)
+		widgetFactory = newWidgetFactory()
 
 		// Recreate monitoring panel with new theme colors
 		monitoringPanel = ui.NewMonitoringPanel(monitorSystem, widgetFactory, showDetailColumns)
-		content.Objects[1] = monitoringPanel.Container
+		panelScroll.Content = monitoringPanel.Container
 
 		// Force refresh to show theme changes
 		content.Refresh()
@@ -0,0 +1,88 @@
+package procctl
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuditLogEntries is how many of the latest entries are kept in memory
+const AuditLogEntries = 100
+
+// AuditEntry records one action taken on a process
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"` // e.g. "SIGTERM" or "renice +5"
+	PID     int32     `json:"pid"`
+	Process string    `json:"process"`
+	Error   string    `json:"error,omitempty"` // Empty when the action succeeded
+}
+
+// AuditLog keeps the latest actions in memory and appends every action as a JSON line to a writer
+type AuditLog struct {
+	mu      sync.Mutex
+	w       io.Writer
+	entries []AuditEntry
+}
+
+// NewAuditLog creates an AuditLog writing to w; a nil writer keeps the entries in memory only
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// DefaultAuditLogPath returns the audit log location in the user configuration directory
+func DefaultAuditLogPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-dummy-monitor", "audit.log"), nil
+}
+
+// OpenAuditLog creates an AuditLog appending to the file at path, creating it and its directory when missing
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return NewAuditLog(file), nil
+}
+
+// Record adds the entry, failing only when it cannot be written out
+func (a *AuditLog) Record(entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.entries = append(a.entries, entry)
+	if len(a.entries) > AuditLogEntries {
+		a.entries = a.entries[len(a.entries)-AuditLogEntries:]
+	}
+
+	if a.w == nil {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = a.w.Write(append(line, '\n'))
+	return err
+}
+
+// Entries returns the kept entries, newest first
+func (a *AuditLog) Entries() []AuditEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entries := make([]AuditEntry, len(a.entries))
+	for i, entry := range a.entries {
+		entries[len(a.entries)-1-i] = entry
+	}
+	return entries
+}
@@ -0,0 +1,88 @@
+package procctl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAuditLogRecord(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewAuditLog(&buf)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entries := []AuditEntry{
+		{Time: now, Action: string(SIGTERM), PID: 42, Process: "make"},
+		{Time: now.Add(time.Second), Action: "renice -5", PID: 42, Process: "make", Error: "operation not permitted"},
+	}
+	for _, entry := range entries {
+		if err := log.Record(entry); err != nil {
+			t.Fatalf("Expected the entry to be recorded, got %v", err)
+		}
+	}
+
+	kept := log.Entries()
+	if len(kept) != 2 || kept[0].Action != "renice -5" || kept[1].Action != string(SIGTERM) {
+		t.Errorf("Expected the entries newest first, got %+v", kept)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	var lines int
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected a JSON line, got %q: %v", scanner.Text(), err)
+		}
+		if entry != entries[lines] {
+			t.Errorf("Expected line %d to hold %+v, got %+v", lines, entries[lines], entry)
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("Expected 2 lines, got %d", lines)
+	}
+}
+
+func TestAuditLogKeepsLatestEntries(t *testing.T) {
+	log := NewAuditLog(nil)
+	for i := 0; i < AuditLogEntries+10; i++ {
+		if err := log.Record(AuditEntry{PID: int32(i)}); err != nil {
+			t.Fatalf("Expected the entry to be recorded, got %v", err)
+		}
+	}
+
+	entries := log.Entries()
+	if len(entries) != AuditLogEntries {
+		t.Fatalf("Expected %d entries, got %d", AuditLogEntries, len(entries))
+	}
+	if entries[0].PID != AuditLogEntries+9 || entries[len(entries)-1].PID != 10 {
+		t.Errorf("Expected the latest entries, got PIDs %d to %d", entries[0].PID, entries[len(entries)-1].PID)
+	}
+}
+
+func TestOpenAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "audit.log")
+
+	for i := 0; i < 2; i++ {
+		log, err := OpenAuditLog(path)
+		if err != nil {
+			t.Fatalf("Expected the audit log to open, got %v", err)
+		}
+		if err := log.Record(AuditEntry{Action: string(SIGKILL), PID: 7}); err != nil {
+			t.Fatalf("Expected the entry to be recorded, got %v", err)
+		}
+	}
+
+	// Reopening appends instead of truncating
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the audit log file, got %v", err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines != 2 {
+		t.Errorf("Expected 2 lines, got %d", lines)
+	}
+}
@@ -0,0 +1,3 @@
+// Package procctl sends signals to and changes the priority of other processes,
+// and records every action taken in an audit log
+package procctl
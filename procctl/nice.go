@@ -0,0 +1,18 @@
+package procctl
+
+// Bounds of the nice value on unix systems
+const (
+	MinNice = -20
+	MaxNice = 19
+)
+
+// clampNice keeps a nice value within MinNice and MaxNice
+func clampNice(nice int) int {
+	if nice < MinNice {
+		return MinNice
+	}
+	if nice > MaxNice {
+		return MaxNice
+	}
+	return nice
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package procctl
+
+import (
+	"context"
+	"runtime"
+	"syscall"
+)
+
+// Renice changes the nice value of the process by delta and returns the new value.
+// Raising the priority (a negative delta) usually requires elevated privileges.
+func Renice(_ context.Context, pid int32, delta int) (int, error) {
+	current, err := Nice(pid)
+	if err != nil {
+		return 0, err
+	}
+
+	nice := clampNice(current + delta)
+	if err := syscall.Setpriority(syscall.PRIO_PROCESS, int(pid), nice); err != nil {
+		return 0, err
+	}
+	return nice, nil
+}
+
+// Nice returns the nice value of the process
+func Nice(pid int32) (int, error) {
+	priority, err := syscall.Getpriority(syscall.PRIO_PROCESS, int(pid))
+	if err != nil {
+		return 0, err
+	}
+	// The Linux system call returns 20 - nice so that the result is never negative
+	if runtime.GOOS == "linux" {
+		return 20 - priority, nil
+	}
+	return priority, nil
+}
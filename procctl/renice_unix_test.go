@@ -0,0 +1,56 @@
+//go:build !windows
+
+package procctl
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRenice(t *testing.T) {
+	cmd := startSleep(t)
+	pid := int32(cmd.Process.Pid)
+
+	before, err := Nice(pid)
+	if err != nil {
+		t.Fatalf("Expected the nice value of process %d, got %v", pid, err)
+	}
+
+	// Lowering the priority is allowed without privileges
+	nice, err := Renice(context.Background(), pid, 1)
+	if err != nil {
+		t.Fatalf("Expected renice to succeed, got %v", err)
+	}
+	if want := clampNice(before + 1); nice != want {
+		t.Errorf("Expected nice value %d, got %d", want, nice)
+	}
+	if after, _ := Nice(pid); after != nice {
+		t.Errorf("Expected the process to run at nice %d, got %d", nice, after)
+	}
+
+	// Cross-check with the kernel's own report, the nice value is the 19th field of /proc/<pid>/stat
+	if runtime.GOOS == "linux" {
+		data, err := os.ReadFile("/proc/" + strconv.Itoa(int(pid)) + "/stat")
+		if err != nil {
+			t.Fatalf("Expected the stat file of process %d, got %v", pid, err)
+		}
+		// Fields are counted after the command name, which may contain spaces
+		fields := strings.Fields(string(data[strings.LastIndexByte(string(data), ')')+1:]))
+		if fields[16] != strconv.Itoa(nice) {
+			t.Errorf("Expected /proc to report nice %d, got %s", nice, fields[16])
+		}
+	}
+}
+
+func TestClampNice(t *testing.T) {
+	cases := map[int]int{-30: MinNice, -20: -20, 0: 0, 19: 19, 25: MaxNice}
+	for nice, want := range cases {
+		if got := clampNice(nice); got != want {
+			t.Errorf("Expected %d to clamp to %d, got %d", nice, want, got)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package procctl
+
+import (
+	"context"
+	"errors"
+)
+
+// errNiceUnsupported is returned on Windows, which uses priority classes instead of nice values
+var errNiceUnsupported = errors.New("nice values are not supported on windows")
+
+// Renice is not supported on Windows
+func Renice(_ context.Context, _ int32, _ int) (int, error) {
+	return 0, errNiceUnsupported
+}
+
+// Nice is not supported on Windows
+func Nice(_ int32) (int, error) {
+	return 0, errNiceUnsupported
+}
@@ -0,0 +1,60 @@
+package procctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+// Signal names a signal that can be sent to a process
+type Signal string
+
+// Signals offered in the process tree view
+const (
+	SIGTERM Signal = "SIGTERM" // Ask the process to terminate
+	SIGKILL Signal = "SIGKILL" // Kill the process immediately
+	SIGSTOP Signal = "SIGSTOP" // Pause the process
+	SIGCONT Signal = "SIGCONT" // Resume a paused process
+)
+
+// Signals lists the supported signals, gentlest first
+var Signals = []Signal{SIGTERM, SIGKILL, SIGSTOP, SIGCONT}
+
+// Send delivers the signal to the process; on Windows the closest equivalent is used
+func Send(ctx context.Context, pid int32, sig Signal) error {
+	proc, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return err
+	}
+
+	switch sig {
+	case SIGTERM:
+		return proc.TerminateWithContext(ctx)
+	case SIGKILL:
+		return proc.KillWithContext(ctx)
+	case SIGSTOP:
+		return proc.SuspendWithContext(ctx)
+	case SIGCONT:
+		return proc.ResumeWithContext(ctx)
+	default:
+		return fmt.Errorf("unsupported signal %q", sig)
+	}
+}
+
+// Verify checks that pid still names the process started at createTime, in
+// milliseconds since the epoch, so an action never hits a process that reused the PID
+func Verify(ctx context.Context, pid int32, createTime int64) error {
+	proc, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return fmt.Errorf("process %d has exited", pid)
+	}
+	started, err := proc.CreateTimeWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("process %d has exited", pid)
+	}
+	if started != createTime {
+		return fmt.Errorf("process %d has exited and its PID was reused", pid)
+	}
+	return nil
+}
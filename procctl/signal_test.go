@@ -0,0 +1,114 @@
+//go:build !windows
+
+package procctl
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+// startSleep starts a child process that lives long enough to be signalled
+func startSleep(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Cannot start sleep: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd
+}
+
+// processStatus returns the state letter of the process, "T" when stopped and "S" when sleeping
+func processStatus(t *testing.T, pid int32) string {
+	t.Helper()
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		t.Fatalf("Expected process %d to exist: %v", pid, err)
+	}
+	status, err := proc.Status()
+	if err != nil || status == "" {
+		t.Fatalf("Expected the status of process %d: %v", pid, err)
+	}
+	return status[:1]
+}
+
+// waitForStatus polls until the process reaches the given state
+func waitForStatus(t *testing.T, pid int32, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for processStatus(t, pid) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected process %d to reach status %s, got %s", pid, want, processStatus(t, pid))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSendStopAndContinue(t *testing.T) {
+	cmd := startSleep(t)
+	pid := int32(cmd.Process.Pid)
+	ctx := context.Background()
+
+	if err := Send(ctx, pid, SIGSTOP); err != nil {
+		t.Fatalf("Expected SIGSTOP to succeed, got %v", err)
+	}
+	waitForStatus(t, pid, "T")
+
+	if err := Send(ctx, pid, SIGCONT); err != nil {
+		t.Fatalf("Expected SIGCONT to succeed, got %v", err)
+	}
+	waitForStatus(t, pid, "S")
+}
+
+func TestSendTerminate(t *testing.T) {
+	cmd := startSleep(t)
+
+	if err := Send(context.Background(), int32(cmd.Process.Pid), SIGTERM); err != nil {
+		t.Fatalf("Expected SIGTERM to succeed, got %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Errorf("Expected the process to exit after SIGTERM")
+	}
+}
+
+func TestSendUnsupportedSignal(t *testing.T) {
+	cmd := startSleep(t)
+
+	if err := Send(context.Background(), int32(cmd.Process.Pid), Signal("SIGHUP")); err == nil {
+		t.Errorf("Expected an error for an unsupported signal")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	cmd := startSleep(t)
+	pid := int32(cmd.Process.Pid)
+	ctx := context.Background()
+
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		t.Fatalf("Expected process %d to exist: %v", pid, err)
+	}
+	createTime, err := proc.CreateTime()
+	if err != nil {
+		t.Fatalf("Expected the start time of process %d: %v", pid, err)
+	}
+
+	if err := Verify(ctx, pid, createTime); err != nil {
+		t.Errorf("Expected the running process to verify, got %v", err)
+	}
+	if err := Verify(ctx, pid, createTime-1000); err == nil {
+		t.Errorf("Expected a different start time to fail verification")
+	}
+}
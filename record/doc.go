@@ -0,0 +1,3 @@
+// Package record runs a command while sampling its process tree and the whole
+// system, and summarizes the run as JSON and as an HTML report
+package record
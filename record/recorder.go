@@ -0,0 +1,206 @@
+package record
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"time"
+
+	"go-dummy-monitor/collectors"
+)
+
+// DefaultInterval is how often a recorded run is sampled, faster than the monitor to catch short builds
+const DefaultInterval = 250 * time.Millisecond
+
+// Options configures a recorded run
+type Options struct {
+	Command  []string
+	Interval time.Duration
+	Backend  string // Reads the system counters, collectors.DefaultBackend when empty
+	Stdin    io.Reader
+	Stdout   io.Writer
+	Stderr   io.Writer
+}
+
+// systemSeries lists the system-wide series recorded next to the process tree
+var systemSeries = []Series{
+	{Name: collectors.MetricCPUUsage, Label: "System CPU", Unit: "%"},
+	{Name: collectors.MetricRAMUsage, Label: "System RAM", Unit: "%"},
+	{Name: collectors.MetricDiskRead, Label: "Disk read", Unit: "MB/s"},
+	{Name: collectors.MetricDiskWrite, Label: "Disk write", Unit: "MB/s"},
+	{Name: collectors.MetricNetRead, Label: "Net download", Unit: "MB/s"},
+	{Name: collectors.MetricNetWrite, Label: "Net upload", Unit: "MB/s"},
+}
+
+// treeSeries lists the process tree series
+var treeSeries = []Series{
+	{Name: SeriesTreeCPU, Label: "CPU", Unit: "%"},
+	{Name: SeriesTreeRSS, Label: "RSS", Unit: "MB"},
+	{Name: SeriesTreeRead, Label: "Read", Unit: "MB/s"},
+	{Name: SeriesTreeWrite, Label: "Write", Unit: "MB/s"},
+	{Name: SeriesTreeProcesses, Label: "Processes", Unit: ""},
+}
+
+// Run starts the command and samples it until it exits. A command that fails or
+// exits with a non-zero code still yields a summary; only failing to start it is an error.
+func Run(ctx context.Context, opts Options) (*Summary, error) {
+	if len(opts.Command) == 0 {
+		return nil, errors.New("no command to run")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Backend == "" {
+		opts.Backend = collectors.DefaultBackend
+	}
+	backend, err := collectors.NewBackend(opts.Backend)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := backend.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	system := collectors.NewRegistry()
+	for _, c := range []collectors.Collector{
+		collectors.NewCPUCollector(opts.Interval, backend),
+		collectors.NewMemoryCollector(opts.Interval, backend),
+		collectors.NewDiskCollector(opts.Interval, backend),
+		collectors.NewNetworkCollector(opts.Interval, backend),
+	} {
+		_ = system.Register(c)
+	}
+	// Prime the rate based collectors so the first recorded sample is meaningful
+	_, _ = system.CollectDue(ctx, time.Now())
+
+	cmd := exec.CommandContext(ctx, opts.Command[0], opts.Command[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = opts.Stdin, opts.Stdout, opts.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	rec := newRecording(start)
+	tree := newTreeSampler(int32(cmd.Process.Pid))
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	var waitErr error
+sampling:
+	for {
+		select {
+		case waitErr = <-done:
+			break sampling
+		case now := <-ticker.C:
+			// Errors only leave gaps in the system series, the run is still worth summarizing
+			samples, _ := system.CollectDue(ctx, now)
+			usage, _ := tree.Sample(ctx, now)
+			rec.add(now, samples, usage)
+		}
+	}
+	end := time.Now()
+
+	// A non-zero exit is a result, not a recording failure
+	var exitErr *exec.ExitError
+	if waitErr != nil && !errors.As(waitErr, &exitErr) {
+		return nil, waitErr
+	}
+
+	state := cmd.ProcessState
+	totals := tree.Totals()
+	summary := rec.summary(opts.Command, opts.Interval)
+	summary.ExitCode = state.ExitCode()
+	summary.WallSeconds = end.Sub(start).Seconds()
+	summary.UserSeconds = state.UserTime().Seconds()
+	summary.SystemSeconds = state.SystemTime().Seconds()
+	summary.CPUSeconds = summary.UserSeconds + summary.SystemSeconds
+	// Descendants the command did not wait for are only known from sampling
+	if totals.cpuSeconds > summary.CPUSeconds {
+		summary.CPUSeconds = totals.cpuSeconds
+	}
+	summary.MaxRSS = maxRSS(state)
+	summary.ReadBytes = totals.readBytes
+	summary.WriteBytes = totals.writeBytes
+
+	return summary, nil
+}
+
+// recording accumulates the samples of a run
+type recording struct {
+	start   time.Time
+	times   []float64
+	values  map[string][]float64
+	busiest []string
+}
+
+// newRecording creates an empty recording of a run started at start
+func newRecording(start time.Time) *recording {
+	return &recording{
+		start:  start,
+		values: make(map[string][]float64),
+	}
+}
+
+// add appends one sample of the system and the process tree
+func (r *recording) add(now time.Time, samples []collectors.Sample, usage treeUsage) {
+	r.times = append(r.times, now.Sub(r.start).Seconds())
+	r.busiest = append(r.busiest, usage.Busiest)
+
+	// Every series gets a value per sample, a missing system metric repeats the previous one
+	collected := make(map[string]float64, len(samples))
+	for _, sample := range samples {
+		collected[sample.Name] = sample.Value
+	}
+	for _, series := range systemSeries {
+		value, ok := collected[series.Name]
+		if previous := r.values[series.Name]; !ok && len(previous) > 0 {
+			value = previous[len(previous)-1]
+		}
+		r.values[series.Name] = append(r.values[series.Name], value)
+	}
+
+	r.values[SeriesTreeCPU] = append(r.values[SeriesTreeCPU], usage.CPUPercent)
+	r.values[SeriesTreeRSS] = append(r.values[SeriesTreeRSS], float64(usage.RSS)/bytesPerMB)
+	r.values[SeriesTreeRead] = append(r.values[SeriesTreeRead], usage.ReadRate/bytesPerMB)
+	r.values[SeriesTreeWrite] = append(r.values[SeriesTreeWrite], usage.WriteRate/bytesPerMB)
+	r.values[SeriesTreeProcesses] = append(r.values[SeriesTreeProcesses], float64(usage.Processes))
+}
+
+// summary builds the sampled part of the summary: series, peaks and phases
+func (r *recording) summary(command []string, interval time.Duration) *Summary {
+	summary := &Summary{
+		Command:         command,
+		Start:           r.start,
+		IntervalSeconds: interval.Seconds(),
+		Times:           r.times,
+	}
+
+	for _, definitions := range [][]Series{treeSeries, systemSeries} {
+		for _, series := range definitions {
+			series.Values = r.values[series.Name]
+			summary.Series = append(summary.Series, series)
+		}
+	}
+
+	for i := range r.times {
+		if rss := uint64(r.values[SeriesTreeRSS][i] * bytesPerMB); rss > summary.PeakRSS {
+			summary.PeakRSS = rss
+		}
+		if processes := int(r.values[SeriesTreeProcesses][i]); processes > summary.PeakProcesses {
+			summary.PeakProcesses = processes
+		}
+	}
+
+	// Phases last at least a twentieth of the run, so a long run splits into a readable number of them
+	minSamples := len(r.times) / 20
+	if minSamples < 2 {
+		minSamples = 2
+	}
+	summary.Phases = detectPhases(r.times, r.busiest, r.values[SeriesTreeCPU], r.values[SeriesTreeRSS], minSamples)
+
+	return summary
+}
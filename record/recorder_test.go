@@ -0,0 +1,45 @@
+//go:build !windows
+
+package record
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	summary, err := Run(context.Background(), Options{
+		Command:  []string{"sh", "-c", "sleep 0.3; exit 3"},
+		Interval: 50 * time.Millisecond,
+		Stdout:   io.Discard,
+		Stderr:   io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("Expected the run to be recorded, got %v", err)
+	}
+
+	if summary.ExitCode != 3 {
+		t.Errorf("Expected exit code 3, got %d", summary.ExitCode)
+	}
+	if summary.WallSeconds < 0.3 {
+		t.Errorf("Expected a wall time of at least 0.3 s, got %.2f", summary.WallSeconds)
+	}
+	if len(summary.Times) == 0 || summary.PeakProcesses == 0 || summary.PeakRSS == 0 {
+		t.Errorf("Expected samples of the process tree, got %d samples, %d processes and %d bytes",
+			len(summary.Times), summary.PeakProcesses, summary.PeakRSS)
+	}
+	if len(summary.Phases) == 0 {
+		t.Errorf("Expected at least one phase")
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := Run(context.Background(), Options{}); err == nil {
+		t.Error("Expected an error without a command")
+	}
+	if _, err := Run(context.Background(), Options{Command: []string{"/nonexistent/command"}}); err == nil {
+		t.Error("Expected an error for a command that cannot start")
+	}
+}
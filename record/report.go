@@ -0,0 +1,233 @@
+package record
+
+import (
+	"fmt"
+	"html/template"
+	"image/color"
+	"math"
+	"os"
+	"strings"
+
+	"go-dummy-monitor/constants"
+)
+
+// Size of the report graphs in SVG units
+const (
+	reportGraphWidth  = 800.0
+	reportGraphHeight = 160.0
+)
+
+// reportGraph is one SVG graph of the report, its lines sharing an axis
+type reportGraph struct {
+	Title  string
+	Unit   string
+	Max    float64
+	Lines  []reportLine
+	Phases []reportBand
+}
+
+// reportLine is one series of a graph as SVG polyline points
+type reportLine struct {
+	Label  string
+	Color  string
+	Points string
+}
+
+// reportBand marks a phase across the width of a graph
+type reportBand struct {
+	Name  string
+	X     float64
+	Width float64
+	Fill  string
+}
+
+// reportGraphs lists the graphs of the report and the series each one draws
+var reportGraphs = []struct {
+	title  string
+	unit   string
+	series []string
+}{
+	{"Process tree CPU", "% of one CPU", []string{SeriesTreeCPU}},
+	{"Process tree memory", "MB", []string{SeriesTreeRSS}},
+	{"Process tree I/O", "MB/s", []string{SeriesTreeRead, SeriesTreeWrite}},
+	{"Process tree size", "processes", []string{SeriesTreeProcesses}},
+	{"System CPU and RAM", "%", []string{systemSeries[0].Name, systemSeries[1].Name}},
+	{"System disk and network", "MB/s", []string{systemSeries[2].Name, systemSeries[3].Name, systemSeries[4].Name, systemSeries[5].Name}},
+}
+
+// reportColors are the line colors in order of the series in a graph, matching the widget colors
+var reportColors = []color.Color{
+	constants.LightColors.CPU,
+	constants.LightColors.RAM,
+	constants.LightColors.DISK,
+	constants.LightColors.NET,
+}
+
+// WriteHTML writes a self-contained HTML report of the summary to the file at path
+func (s *Summary) WriteHTML(path string) error {
+	var report strings.Builder
+	if err := reportTemplate.Execute(&report, s.reportData()); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(report.String()), 0o644)
+}
+
+// reportData prepares the summary for the report template
+func (s *Summary) reportData() map[string]any {
+	graphs := make([]reportGraph, 0, len(reportGraphs))
+	for _, definition := range reportGraphs {
+		graph := reportGraph{Title: definition.title, Unit: definition.unit}
+
+		var histories [][]float64
+		for _, name := range definition.series {
+			histories = append(histories, s.Values(name))
+		}
+		graph.Max = axisMax(histories...)
+
+		for i, name := range definition.series {
+			graph.Lines = append(graph.Lines, reportLine{
+				Label:  s.seriesLabel(name),
+				Color:  hexColor(reportColors[i%len(reportColors)]),
+				Points: polylinePoints(s.Times, histories[i], s.WallSeconds, graph.Max),
+			})
+		}
+		graph.Phases = s.phaseBands()
+		graphs = append(graphs, graph)
+	}
+
+	return map[string]any{
+		"Summary": s,
+		"Command": strings.Join(s.Command, " "),
+		"Graphs":  graphs,
+		"Width":   reportGraphWidth,
+		"Height":  reportGraphHeight,
+		"Bytes":   formatBytes,
+	}
+}
+
+// seriesLabel returns the label of the series with the given name
+func (s *Summary) seriesLabel(name string) string {
+	for _, series := range s.Series {
+		if series.Name == name {
+			return series.Label
+		}
+	}
+	return name
+}
+
+// phaseBands returns a shaded band per phase, alternating shades so neighbouring phases stand apart
+func (s *Summary) phaseBands() []reportBand {
+	if s.WallSeconds <= 0 {
+		return nil
+	}
+	shades := []string{"#f4f4f4", "#e4e4e4"}
+
+	bands := make([]reportBand, len(s.Phases))
+	for i, phase := range s.Phases {
+		x := phase.Start / s.WallSeconds * reportGraphWidth
+		bands[i] = reportBand{
+			Name:  phase.Name,
+			X:     x,
+			Width: math.Max(phase.End/s.WallSeconds*reportGraphWidth-x, 0),
+			Fill:  shades[i%len(shades)],
+		}
+	}
+	return bands
+}
+
+// axisMax returns the highest value across the histories, at least 1 so empty graphs still have an axis
+func axisMax(histories ...[]float64) float64 {
+	peak := 1.0
+	for _, history := range histories {
+		for _, value := range history {
+			peak = math.Max(peak, value)
+		}
+	}
+	return peak
+}
+
+// polylinePoints converts a history into SVG points, time running left to right over the whole run
+func polylinePoints(times, values []float64, wallSeconds, maxValue float64) string {
+	if wallSeconds <= 0 || maxValue <= 0 {
+		return ""
+	}
+
+	points := make([]string, 0, len(values))
+	for i, value := range values {
+		if i >= len(times) {
+			break
+		}
+		x := times[i] / wallSeconds * reportGraphWidth
+		y := reportGraphHeight - math.Min(value/maxValue, 1)*reportGraphHeight
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	return strings.Join(points, " ")
+}
+
+// hexColor formats a color as a CSS hex color
+func hexColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// formatBytes formats a byte count with a binary unit suffix
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// reportTemplate renders the report, graphs are inline SVG so the file needs nothing else
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Command}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #000; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+td, th { padding: 0.2em 1em 0.2em 0; text-align: left; }
+svg { background: #f0f0f0; display: block; }
+.legend span { margin-right: 1.5em; }
+.phase { font-size: 10px; fill: #666; }
+</style>
+</head>
+<body>
+<h1><code>{{.Command}}</code></h1>
+{{with .Summary}}
+<table>
+<tr><th>Exit code</th><td>{{.ExitCode}}</td></tr>
+<tr><th>Started</th><td>{{.Start.Format "2006-01-02 15:04:05"}}</td></tr>
+<tr><th>Wall time</th><td>{{printf "%.2f s" .WallSeconds}}</td></tr>
+<tr><th>CPU time</th><td>{{printf "%.2f s (user %.2f s, system %.2f s)" .CPUSeconds .UserSeconds .SystemSeconds}}</td></tr>
+<tr><th>Peak RSS of the tree</th><td>{{call $.Bytes .PeakRSS}}</td></tr>
+<tr><th>Largest process RSS</th><td>{{if .MaxRSS}}{{call $.Bytes .MaxRSS}}{{else}}unknown{{end}}</td></tr>
+<tr><th>I/O</th><td>read {{call $.Bytes .ReadBytes}}, written {{call $.Bytes .WriteBytes}}</td></tr>
+<tr><th>Peak processes</th><td>{{.PeakProcesses}}</td></tr>
+<tr><th>Sampled every</th><td>{{printf "%.3f s" .IntervalSeconds}}</td></tr>
+</table>
+<h2>Phases</h2>
+<table>
+<tr><th>Busiest process</th><th>From</th><th>To</th><th>CPU time</th><th>Peak RSS</th></tr>
+{{range .Phases}}<tr><td>{{.Name}}</td><td>{{printf "%.1f s" .Start}}</td><td>{{printf "%.1f s" .End}}</td><td>{{printf "%.2f s" .CPUSeconds}}</td><td>{{call $.Bytes .PeakRSS}}</td></tr>
+{{end}}</table>
+{{end}}
+{{range .Graphs}}
+<h2>{{.Title}}</h2>
+<svg width="{{$.Width}}" height="{{$.Height}}" viewBox="0 0 {{$.Width}} {{$.Height}}">
+{{range .Phases}}<rect x="{{printf "%.1f" .X}}" y="0" width="{{printf "%.1f" .Width}}" height="{{$.Height}}" fill="{{.Fill}}"><title>{{.Name}}</title></rect>
+<text class="phase" x="{{printf "%.1f" .X}}" y="10">{{.Name}}</text>
+{{end}}{{range .Lines}}<polyline fill="none" stroke="{{.Color}}" stroke-width="1.5" points="{{.Points}}"/>
+{{end}}</svg>
+<div class="legend">max {{printf "%.1f" .Max}} {{.Unit}}: {{range .Lines}}<span style="color: {{.Color}}">&#9632; {{.Label}}</span>{{end}}</div>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,64 @@
+package record
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolylinePoints(t *testing.T) {
+	points := polylinePoints([]float64{0, 5, 10}, []float64{0, 50, 200}, 10, 100)
+
+	// Values above the axis maximum are clipped to the top
+	want := "0.0,160.0 400.0,80.0 800.0,0.0"
+	if points != want {
+		t.Errorf("Expected %q, got %q", want, points)
+	}
+	if points := polylinePoints([]float64{0}, []float64{1}, 0, 1); points != "" {
+		t.Errorf("Expected no points for a run without duration, got %q", points)
+	}
+}
+
+func TestHexColor(t *testing.T) {
+	if got := hexColor(color.RGBA{40, 180, 255, 255}); got != "#28b4ff" {
+		t.Errorf("Expected #28b4ff, got %s", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[uint64]string{512: "512 B", 2048: "2.0 KB", 3 * 1024 * 1024: "3.0 MB"}
+	for bytes, want := range cases {
+		if got := formatBytes(bytes); got != want {
+			t.Errorf("Expected %d bytes to format as %q, got %q", bytes, want, got)
+		}
+	}
+}
+
+func TestSummaryWriteHTML(t *testing.T) {
+	rec := newRecording(testStart)
+	rec.add(testStart.Add(time51), nil, treeUsage{Processes: 1, CPUPercent: 90, RSS: bytesPerMB, Busiest: "cc1"})
+	rec.add(testStart.Add(2*time51), nil, treeUsage{Processes: 1, CPUPercent: 95, RSS: bytesPerMB, Busiest: "cc1"})
+	summary := rec.summary([]string{"make", "<all>"}, DefaultInterval)
+	summary.WallSeconds = 1.1
+
+	path := filepath.Join(t.TempDir(), "run.html")
+	if err := summary.WriteHTML(path); err != nil {
+		t.Fatalf("Expected the report to be written, got %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the report file, got %v", err)
+	}
+	report := string(data)
+
+	for _, want := range []string{"make &lt;all&gt;", "<svg", "<polyline", "Process tree CPU", "<td>cc1</td>"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected the report to contain %q", want)
+		}
+	}
+	if strings.Count(report, "<svg") != len(reportGraphs) {
+		t.Errorf("Expected %d graphs, got %d", len(reportGraphs), strings.Count(report, "<svg"))
+	}
+}
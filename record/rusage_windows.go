@@ -0,0 +1,10 @@
+//go:build windows
+
+package record
+
+import "os"
+
+// maxRSS is unknown on Windows, which reports no resource usage for exited processes
+func maxRSS(_ *os.ProcessState) uint64 {
+	return 0
+}
@@ -0,0 +1,155 @@
+package record
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Series recorded for the process tree of the command, system-wide series use the collector metric names
+const (
+	SeriesTreeCPU       = "tree.cpu"       // percent of one CPU
+	SeriesTreeRSS       = "tree.rss"       // MB
+	SeriesTreeRead      = "tree.read"      // MB/s
+	SeriesTreeWrite     = "tree.write"     // MB/s
+	SeriesTreeProcesses = "tree.processes" // processes in the tree
+)
+
+// Summary is the outcome of a recorded command run
+type Summary struct {
+	Command         []string  `json:"command"`
+	ExitCode        int       `json:"exit_code"`
+	Start           time.Time `json:"start"`
+	WallSeconds     float64   `json:"wall_seconds"`
+	UserSeconds     float64   `json:"user_seconds"`
+	SystemSeconds   float64   `json:"system_seconds"`
+	CPUSeconds      float64   `json:"cpu_seconds"`
+	PeakRSS         uint64    `json:"peak_rss_bytes"` // Summed over the process tree, as sampled
+	MaxRSS          uint64    `json:"max_rss_bytes"`  // Largest single process as reported by the OS, zero when unknown
+	ReadBytes       uint64    `json:"read_bytes"`
+	WriteBytes      uint64    `json:"write_bytes"`
+	PeakProcesses   int       `json:"peak_processes"`
+	IntervalSeconds float64   `json:"interval_seconds"`
+	Phases          []Phase   `json:"phases"`
+	Times           []float64 `json:"times"` // Seconds since the start of every sample
+	Series          []Series  `json:"series"`
+}
+
+// Series is the history of one metric over the run, one value per sample time
+type Series struct {
+	Name   string    `json:"name"`
+	Label  string    `json:"label"`
+	Unit   string    `json:"unit"`
+	Values []float64 `json:"values"`
+}
+
+// Phase is a stretch of the run during which the same process was the busiest
+type Phase struct {
+	Name       string  `json:"name"`
+	Start      float64 `json:"start"` // Seconds since the start of the run
+	End        float64 `json:"end"`
+	CPUSeconds float64 `json:"cpu_seconds"`
+	PeakRSS    uint64  `json:"peak_rss_bytes"`
+}
+
+// IdlePhase names the stretches in which no process of the tree used the CPU
+const IdlePhase = "idle"
+
+// Values returns the values of the series with the given name, or nil
+func (s *Summary) Values(name string) []float64 {
+	for _, series := range s.Series {
+		if series.Name == name {
+			return series.Values
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the summary as indented JSON to the file at path
+func (s *Summary) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// detectPhases splits the run at the samples where the busiest process changes.
+// Stretches shorter than minSamples are merged into the phase before them, or
+// after them at the start, so short-lived helper processes do not fragment the run.
+func detectPhases(times []float64, busiest []string, cpuPercent []float64, rssMB []float64, minSamples int) []Phase {
+	if len(times) == 0 {
+		return nil
+	}
+
+	type stretch struct {
+		name       string
+		first, end int // Sample indexes, end exclusive
+	}
+	var stretches []stretch
+	for i := range times {
+		name := busiest[i]
+		if name == "" {
+			name = IdlePhase
+		}
+		if len(stretches) > 0 && stretches[len(stretches)-1].name == name {
+			stretches[len(stretches)-1].end = i + 1
+			continue
+		}
+		stretches = append(stretches, stretch{name: name, first: i, end: i + 1})
+	}
+
+	var merged []stretch
+	lead := -1 // First sample of the short stretches at the start, waiting for a long one to join
+	for _, s := range stretches {
+		short := s.end-s.first < minSamples
+		last := len(merged) - 1
+		switch {
+		case last < 0 && short:
+			if lead < 0 {
+				lead = s.first
+			}
+		case last < 0:
+			if lead >= 0 {
+				s.first = lead
+			}
+			merged = append(merged, s)
+		case short || merged[last].name == s.name:
+			merged[last].end = s.end
+		default:
+			merged = append(merged, s)
+		}
+	}
+	// A run made only of short stretches is one phase named after the longest of them
+	if len(merged) == 0 {
+		longest := stretches[0]
+		for _, s := range stretches {
+			if s.end-s.first > longest.end-longest.first {
+				longest = s
+			}
+		}
+		merged = []stretch{{name: longest.name, first: 0, end: len(times)}}
+	}
+
+	phases := make([]Phase, len(merged))
+	for i, s := range merged {
+		phase := Phase{Name: s.name, Start: times[s.first], End: times[s.end-1]}
+		for j := s.first; j < s.end; j++ {
+			if j > 0 {
+				phase.CPUSeconds += cpuPercent[j] / 100 * (times[j] - times[j-1])
+			}
+			if rss := uint64(rssMB[j] * bytesPerMB); rss > phase.PeakRSS {
+				phase.PeakRSS = rss
+			}
+		}
+		// Phases meet at the first sample of the next one
+		if s.end < len(times) {
+			phase.End = times[s.end]
+		}
+		phases[i] = phase
+	}
+	return phases
+}
+
+// bytesPerMB converts between the MB based series and byte counts
+const bytesPerMB = 1024 * 1024
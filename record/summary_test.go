@@ -0,0 +1,85 @@
+package record
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectPhases(t *testing.T) {
+	times := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	busiest := []string{"", "make", "cc1", "cc1", "cc1", "as", "cc1", "ld", "ld", "ld"}
+	cpu := []float64{0, 100, 100, 100, 100, 100, 100, 50, 50, 50}
+	rss := []float64{1, 2, 10, 30, 20, 5, 40, 8, 8, 8}
+
+	phases := detectPhases(times, busiest, cpu, rss, 2)
+
+	// The idle and make samples at the start join cc1, the short as stretch is absorbed by it
+	want := []Phase{
+		{Name: "cc1", Start: 0, End: 7, CPUSeconds: 6, PeakRSS: 40 * bytesPerMB},
+		{Name: "ld", Start: 7, End: 9, CPUSeconds: 1.5, PeakRSS: 8 * bytesPerMB},
+	}
+	if len(phases) != len(want) {
+		t.Fatalf("Expected %d phases, got %+v", len(want), phases)
+	}
+	for i := range want {
+		if phases[i] != want[i] {
+			t.Errorf("Expected phase %d to be %+v, got %+v", i, want[i], phases[i])
+		}
+	}
+
+	if phases := detectPhases(nil, nil, nil, nil, 2); phases != nil {
+		t.Errorf("Expected no phases without samples, got %+v", phases)
+	}
+}
+
+func TestDetectPhasesIdle(t *testing.T) {
+	phases := detectPhases([]float64{0, 1, 2}, []string{"", "", ""}, []float64{0, 0, 0}, []float64{1, 1, 1}, 2)
+	if len(phases) != 1 || phases[0].Name != IdlePhase {
+		t.Errorf("Expected a single idle phase, got %+v", phases)
+	}
+}
+
+func TestRecordingSummary(t *testing.T) {
+	rec := newRecording(testStart)
+	rec.add(testStart.Add(time51), nil, treeUsage{Processes: 1, CPUPercent: 50, RSS: 10 * bytesPerMB, Busiest: "make"})
+	rec.add(testStart.Add(2*time51), nil, treeUsage{Processes: 3, CPUPercent: 150, RSS: 30 * bytesPerMB, Busiest: "make"})
+	rec.add(testStart.Add(3*time51), nil, treeUsage{Processes: 2, CPUPercent: 80, RSS: 20 * bytesPerMB, Busiest: "make"})
+
+	summary := rec.summary([]string{"make"}, DefaultInterval)
+	if summary.PeakRSS != 30*bytesPerMB || summary.PeakProcesses != 3 {
+		t.Errorf("Expected peaks of 30 MB and 3 processes, got %d bytes and %d", summary.PeakRSS, summary.PeakProcesses)
+	}
+	if len(summary.Series) != len(treeSeries)+len(systemSeries) {
+		t.Errorf("Expected every tree and system series, got %d", len(summary.Series))
+	}
+	for _, series := range summary.Series {
+		if len(series.Values) != len(summary.Times) {
+			t.Errorf("Expected one value of %s per sample, got %d", series.Name, len(series.Values))
+		}
+	}
+	if cpu := summary.Values(SeriesTreeCPU); cpu[1] != 150 {
+		t.Errorf("Expected the tree CPU history, got %v", cpu)
+	}
+}
+
+func TestSummaryWriteJSON(t *testing.T) {
+	summary := &Summary{Command: []string{"make", "build"}, ExitCode: 2, Times: []float64{0.25}}
+	path := filepath.Join(t.TempDir(), "run.json")
+	if err := summary.WriteJSON(path); err != nil {
+		t.Fatalf("Expected the summary to be written, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the summary file, got %v", err)
+	}
+	var decoded Summary
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if decoded.ExitCode != 2 || len(decoded.Command) != 2 || decoded.Times[0] != 0.25 {
+		t.Errorf("Expected the summary to round trip, got %+v", decoded)
+	}
+}
@@ -0,0 +1,143 @@
+package record
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/process"
+
+	"go-dummy-monitor/collectors"
+)
+
+// treeUsage is the summed resource usage of a process tree at one sample
+type treeUsage struct {
+	Processes  int
+	CPUPercent float64 // Percent of one CPU
+	RSS        uint64  // Bytes
+	ReadRate   float64 // Bytes/s
+	WriteRate  float64 // Bytes/s
+	Busiest    string  // Name of the process using the most CPU, empty when all are idle
+}
+
+// processCounters are the cumulative counters of one process when it was last seen
+type processCounters struct {
+	cpuSeconds float64
+	readBytes  uint64
+	writeBytes uint64
+}
+
+// treeSampler samples a process and all its descendants, remembering the counters of
+// processes that exited so the totals cover the whole run
+type treeSampler struct {
+	root     int32
+	rates    *collectors.ProcessRates
+	names    map[string]string
+	counters map[string]processCounters
+}
+
+// newTreeSampler creates a treeSampler for the tree below root
+func newTreeSampler(root int32) *treeSampler {
+	return &treeSampler{
+		root:     root,
+		rates:    collectors.NewProcessRates(),
+		names:    make(map[string]string),
+		counters: make(map[string]processCounters),
+	}
+}
+
+// Sample reads the usage of the processes currently in the tree
+func (t *treeSampler) Sample(ctx context.Context, now time.Time) (treeUsage, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return treeUsage{}, err
+	}
+
+	byPID := make(map[int32]*process.Process, len(procs))
+	parents := make(map[int32]int32, len(procs))
+	for _, proc := range procs {
+		if ppid, err := proc.PpidWithContext(ctx); err == nil {
+			byPID[proc.Pid] = proc
+			parents[proc.Pid] = ppid
+		}
+	}
+
+	var usage treeUsage
+	var busiestCPU float64
+	for _, pid := range descendants(t.root, parents) {
+		proc := byPID[pid]
+		createTime, err := proc.CreateTimeWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		key := collectors.ProcessKey(pid, createTime)
+
+		name, ok := t.names[key]
+		if !ok {
+			name, _ = proc.NameWithContext(ctx)
+			t.names[key] = name
+		}
+
+		// Counters that cannot be read keep their last known value for the totals
+		counters := t.counters[key]
+		rates := t.rates.Read(ctx, proc, createTime, now)
+		if rates.HasTimes {
+			counters.cpuSeconds = rates.CPUSeconds
+		}
+		if rates.HasIO {
+			counters.readBytes, counters.writeBytes = rates.ReadBytes, rates.WriteBytes
+		}
+		t.counters[key] = counters
+		if mem, err := proc.MemoryInfoWithContext(ctx); err == nil {
+			usage.RSS += mem.RSS
+		}
+
+		usage.Processes++
+		usage.CPUPercent += rates.CPUPercent
+		usage.ReadRate += rates.ReadRate
+		usage.WriteRate += rates.WriteRate
+		if rates.CPUPercent > busiestCPU {
+			busiestCPU, usage.Busiest = rates.CPUPercent, name
+		}
+	}
+	t.rates.Forget()
+	return usage, nil
+}
+
+// Totals returns the CPU seconds and bytes read and written by every process seen in the tree
+func (t *treeSampler) Totals() processCounters {
+	var totals processCounters
+	for _, counters := range t.counters {
+		totals.cpuSeconds += counters.cpuSeconds
+		totals.readBytes += counters.readBytes
+		totals.writeBytes += counters.writeBytes
+	}
+	return totals
+}
+
+// descendants returns root and every process below it, given each process's parent, sorted by PID
+func descendants(root int32, parents map[int32]int32) []int32 {
+	if _, ok := parents[root]; !ok {
+		return nil
+	}
+
+	children := make(map[int32][]int32, len(parents))
+	for pid, ppid := range parents {
+		if pid != ppid {
+			children[ppid] = append(children[ppid], pid)
+		}
+	}
+
+	tree := []int32{root}
+	inTree := map[int32]bool{root: true}
+	for i := 0; i < len(tree); i++ {
+		for _, child := range children[tree[i]] {
+			if !inTree[child] {
+				inTree[child] = true
+				tree = append(tree, child)
+			}
+		}
+	}
+	sort.Slice(tree, func(i, j int) bool { return tree[i] < tree[j] })
+	return tree
+}
@@ -0,0 +1,43 @@
+package record
+
+import (
+	"testing"
+	"time"
+)
+
+// testStart is the start of the runs recorded in tests
+var testStart = time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+// time51 is a sampling interval that is not a round number of seconds
+const time51 = 510 * time.Millisecond
+
+func TestDescendants(t *testing.T) {
+	parents := map[int32]int32{
+		1:  0,
+		10: 1, // The recorded command
+		11: 10,
+		12: 11,
+		13: 10,
+		20: 1,
+		30: 30, // Parent of itself
+	}
+
+	got := descendants(10, parents)
+	want := []int32{10, 11, 12, 13}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+
+	if got := descendants(30, parents); len(got) != 1 {
+		t.Errorf("Expected a process that is its own parent to be alone, got %v", got)
+	}
+	if got := descendants(99, parents); got != nil {
+		t.Errorf("Expected no tree for an exited command, got %v", got)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"go-dummy-monitor/record"
+)
+
+// runCommand records a run of the command given in args, writes its summary and
+// report, and returns the exit code of the command
+func runCommand(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseRunOptions(args, stderr, time.Now())
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	// Before the recorder builds its collectors
+	if err := opts.apply(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	// An interrupt reaches the command as well; keep going to report on the run once it exits
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	summary, err := record.Run(context.Background(), record.Options{
+		Command:  opts.command,
+		Interval: opts.interval,
+		Backend:  opts.backend,
+		Stdin:    os.Stdin,
+		Stdout:   stdout,
+		Stderr:   stderr,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	jsonPath, htmlPath := opts.output+".json", opts.output+".html"
+	if err := summary.WriteJSON(jsonPath); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := summary.WriteHTML(htmlPath); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	printSummary(stderr, summary, jsonPath, htmlPath)
+
+	// A command killed by a signal has no exit code of its own
+	if summary.ExitCode < 0 {
+		return 1
+	}
+	return summary.ExitCode
+}
+
+// printSummary writes the headline numbers of the run in the manner of time -v
+func printSummary(w io.Writer, summary *record.Summary, jsonPath, htmlPath string) {
+	fmt.Fprintf(w, "\n\tCommand: %s\n", strings.Join(summary.Command, " "))
+	fmt.Fprintf(w, "\tExit code: %d\n", summary.ExitCode)
+	fmt.Fprintf(w, "\tWall time: %.2f s\n", summary.WallSeconds)
+	fmt.Fprintf(w, "\tCPU time: %.2f s (user %.2f s, system %.2f s)\n",
+		summary.CPUSeconds, summary.UserSeconds, summary.SystemSeconds)
+	fmt.Fprintf(w, "\tPeak RSS of the process tree: %.1f MB\n", float64(summary.PeakRSS)/(1024*1024))
+	if summary.MaxRSS > 0 {
+		fmt.Fprintf(w, "\tLargest process RSS: %.1f MB\n", float64(summary.MaxRSS)/(1024*1024))
+	}
+	fmt.Fprintf(w, "\tRead / written: %.1f / %.1f MB\n",
+		float64(summary.ReadBytes)/(1024*1024), float64(summary.WriteBytes)/(1024*1024))
+	fmt.Fprintf(w, "\tPeak processes: %d\n", summary.PeakProcesses)
+	for _, phase := range summary.Phases {
+		fmt.Fprintf(w, "\tPhase %s: %.1f - %.1f s\n", phase.Name, phase.Start, phase.End)
+	}
+	fmt.Fprintf(w, "\tSummary: %s\n\tReport: %s\n", jsonPath, htmlPath)
+}
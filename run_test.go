@@ -0,0 +1,43 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCommand(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "run")
+	var stdout, stderr bytes.Buffer
+
+	code := runCommand([]string{"--interval", "50ms", "--out", output, "--", "sh", "-c", "echo built; exit 4"}, &stdout, &stderr)
+
+	if code != 4 {
+		t.Errorf("Expected the exit code of the command, got %d", code)
+	}
+	if stdout.String() != "built\n" {
+		t.Errorf("Expected the command output to pass through, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "Exit code: 4") {
+		t.Errorf("Expected a printed summary, got %q", stderr.String())
+	}
+	for _, ext := range []string{".json", ".html"} {
+		if _, err := os.Stat(output + ext); err != nil {
+			t.Errorf("Expected the %s file to be written, got %v", ext, err)
+		}
+	}
+}
+
+func TestRunCommandUsage(t *testing.T) {
+	if code := runCommand(nil, io.Discard, io.Discard); code != 2 {
+		t.Errorf("Expected exit code 2 without a command, got %d", code)
+	}
+	if code := runCommand([]string{"-h"}, io.Discard, io.Discard); code != 0 {
+		t.Errorf("Expected exit code 0 for help, got %d", code)
+	}
+}
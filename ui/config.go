@@ -4,14 +4,21 @@ import (
 	"image/color"
 
 	"github.com/shirou/gopsutil/disk"
-	"github.com/shirou/gopsutil/mem"
 
+	"go-dummy-monitor/collectors"
 	"go-dummy-monitor/constants"
 )
 
 // Constants for measurements
 const (
 	MaxNetworkSpeedDefault = 125 // Max network speed in MB/s (125 MB/s is the 1000Mbit/s or 1Gbps)
+	ProcessTableRows       = 25  // Processes shown in the process table after filtering and sorting
+	ProcessTreeRows        = 50  // Processes shown in the process tree after filtering
+	AuditLogRows           = 10  // Latest process actions shown in the audit log table
+	TopProcessCount        = 5   // Processes listed in the top consumer rows of the CPU, RAM and Disk widgets
+	BatteryLowPercent      = 20  // Battery charge below which the Battery widget warns while on battery power
+	BatteryCriticalPercent = 10  // Battery charge below which the Battery widget shows an error while on battery power
+	PressureHighPercent    = 10  // Share of time stalled on a resource from which the Pressure widget is highlighted
 )
 
 // Constants for UI sizing
@@ -34,6 +41,21 @@ const (
 	TransparentAlpha     = constants.TRANSPARENT_ALPHA
 )
 
+// Selection keys and shared selector options
+const (
+	SelectionDiskDevice    = "disk.device"
+	SelectionDiskMount     = "disk.mount"
+	SelectionNetInterface  = "net.interface"
+	SelectionProcessSort   = "process.sort"
+	SelectionProcessFilter = "process.filter"
+	SelectionTreeFilter    = "process.tree.filter"
+	SelectionScope         = "scope"
+	AllDevicesOption       = "All devices"
+	ActiveInterfaceOption  = "Active interface"
+	AllPhysicalOption      = "All physical"
+	HostScopeOption        = "Host"
+)
+
 // ColorScheme is imported from constants package
 type ColorScheme = constants.ColorScheme
 
@@ -42,13 +64,50 @@ type ComponentType int
 
 const (
 	CPUComponent ComponentType = iota
+	CPUCoresComponent
+	LoadComponent
 	RAMComponent
 	DiskComponent
+	FilesystemComponent
 	NetworkComponent
+	ConnectionsComponent
+	ListeningComponent
+	ProcessesComponent
+	ProcessTreeComponent
+	AuditLogComponent
+	WatchComponent
+	SensorsComponent
+	BatteryComponent
+	PressureComponent
+	SystemComponent
 )
 
+// PanelComponents lists the components in the order they appear in the monitoring panel
+var PanelComponents = []ComponentType{
+	WatchComponent,
+	CPUComponent,
+	CPUCoresComponent,
+	LoadComponent,
+	PressureComponent,
+	SensorsComponent,
+	BatteryComponent,
+	RAMComponent,
+	ProcessTreeComponent,
+	DiskComponent,
+	FilesystemComponent,
+	NetworkComponent,
+	ConnectionsComponent,
+	ListeningComponent,
+	ProcessesComponent,
+	AuditLogComponent,
+	SystemComponent,
+}
+
 // SystemDataProvider is an interface for components that provide system data
 type SystemDataProvider interface {
+	GetSnapshot() *Snapshot
+	GetMetricData(name string) []float64
+	GetMetricValue(name string) float64
 	GetCPUData() []float64
 	GetCPUUsage() float64
 	GetRAMData() []float64
@@ -60,10 +119,8 @@ type SystemDataProvider interface {
 	GetNetworkWriteData() []float64
 	GetActiveNetInterfaceName() string
 	GetPartitionInfo() []disk.PartitionStat
-	GetVirtualMemory() *mem.VirtualMemoryStat
-	GetPhysicalCPUCount() int
-	GetLogicalCPUCount() int
-	GetCPUModelName() string
+	GetHostInfo() collectors.HostInfo
+	RefreshHostInfo() collectors.HostInfo
 	GetMaxNetworkSpeed() float64
 }
 
@@ -74,8 +131,15 @@ type Theme interface {
 	GetEmptyRectangle() color.Color
 }
 
+// SelectionStore remembers the choices made in selector controls
+type SelectionStore interface {
+	GetSelection(key string) string
+	SetSelection(key, value string)
+}
+
 // MonitoringSystem defines the interface for the entire monitoring system
 type MonitoringSystem interface {
 	SystemDataProvider
 	Theme
+	SelectionStore
 }
@@ -74,21 +74,18 @@ func NewMonitoringPanel(system MonitoringSystem, factory *WidgetFactory, showDet
 		Controllers:   make(map[ComponentType]*WidgetController),
 	}
 
-	// Create widget controllers
+	// Create widget controllers and stack them in panel order
 	allWidgets := factory.CreateAllWidgets()
+	panel.Container = container.New(layout.NewVBoxLayout())
 
-	panel.Controllers[CPUComponent] = NewWidgetController(system, allWidgets[CPUComponent], showDetail)
-	panel.Controllers[RAMComponent] = NewWidgetController(system, allWidgets[RAMComponent], showDetail)
-	panel.Controllers[DiskComponent] = NewWidgetController(system, allWidgets[DiskComponent], showDetail)
-	panel.Controllers[NetworkComponent] = NewWidgetController(system, allWidgets[NetworkComponent], showDetail)
-
-	// Create panel container
-	panel.Container = container.New(layout.NewVBoxLayout(),
-		panel.Controllers[CPUComponent].Container,
-		panel.Controllers[RAMComponent].Container,
-		panel.Controllers[DiskComponent].Container,
-		panel.Controllers[NetworkComponent].Container,
-	)
+	for _, component := range PanelComponents {
+		monitorWidget, ok := allWidgets[component]
+		if !ok {
+			continue
+		}
+		panel.Controllers[component] = NewWidgetController(system, monitorWidget, showDetail)
+		panel.Container.Add(panel.Controllers[component].Container)
+	}
 
 	return panel
 }
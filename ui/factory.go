@@ -5,12 +5,16 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
 
+	"go-dummy-monitor/collectors"
 	"go-dummy-monitor/ui/widgets"
 )
 
 // WidgetFactory creates monitoring widgets
 type WidgetFactory struct {
-	System MonitoringSystem
+	System  MonitoringSystem
+	Actions *ProcessActions         // Offers process actions and the audit log widget when set
+	Watched []collectors.WatchGroup // Process groups given their own widgets
+	Battery bool                    // Shows the battery widget, for machines with a battery
 }
 
 // NewWidgetFactory creates a new widget factory
@@ -45,16 +49,78 @@ func (f *WidgetFactory) CreateCPUWidget() widgets.MonitorWidget {
 	provider := &CPUDataProvider{System: f.System}
 	infoRows := GetCPUInfoProvider(f.System)
 
+	cpuWidget := widgets.NewSingleValueWidget(*baseGraph, provider, infoRows)
+	cpuWidget.Selectors = GetScopeSelectors(f.System)
+	cpuWidget.Stacked = &StackedMetricsDataProvider{
+		System:   f.System,
+		Title:    "CPU TIME",
+		MaxValue: 100.0,
+		Metrics:  cpuTimeMetrics(f.System.GetColorScheme()),
+	}
+
+	return cpuWidget
+}
+
+// CreateCPUCoresWidget creates a per-core CPU heatmap widget
+func (f *WidgetFactory) CreateCPUCoresWidget() widgets.MonitorWidget {
+	baseGraph := f.createBaseGraph()
+	provider := &CPUCoresDataProvider{System: f.System}
+	infoRows := GetCPUCoresInfoProvider(f.System)
+
+	return widgets.NewHeatmapWidget(*baseGraph, provider, infoRows)
+}
+
+// CreateLoadWidget creates a load average monitoring widget
+func (f *WidgetFactory) CreateLoadWidget() widgets.MonitorWidget {
+	baseGraph := f.createBaseGraph()
+	provider := &LoadDataProvider{System: f.System}
+	infoRows := GetLoadInfoProvider(f.System)
+
 	return widgets.NewSingleValueWidget(*baseGraph, provider, infoRows)
 }
 
+// CreatePressureWidget creates a stall information widget overlaying CPU, memory and I/O pressure
+func (f *WidgetFactory) CreatePressureWidget() widgets.MonitorWidget {
+	baseGraph := f.createBaseGraph()
+	colorScheme := f.System.GetColorScheme()
+	provider := &PressureDataProvider{System: f.System}
+	infoRows := GetPressureInfoProvider(f.System)
+
+	pressureWidget := widgets.NewSingleValueWidget(*baseGraph, provider, infoRows)
+	pressureWidget.Extra = []widgets.SeriesDataProvider{
+		&StackedMetricsDataProvider{
+			System:   f.System,
+			Title:    "SOME STALLED %",
+			MaxValue: 10.0,
+			Metrics:  pressureMetrics(colorScheme, collectors.PressureSome),
+		},
+		&StackedMetricsDataProvider{
+			System:   f.System,
+			Title:    "FULL STALLED %",
+			MaxValue: 10.0,
+			Metrics:  pressureMetrics(colorScheme, collectors.PressureFull),
+		},
+	}
+
+	return pressureWidget
+}
+
 // CreateRAMWidget creates a RAM monitoring widget
 func (f *WidgetFactory) CreateRAMWidget() widgets.MonitorWidget {
 	baseGraph := f.createBaseGraph()
 	provider := &RAMDataProvider{System: f.System}
 	infoRows := GetRAMInfoProvider(f.System)
 
-	return widgets.NewSingleValueWidget(*baseGraph, provider, infoRows)
+	ramWidget := widgets.NewSingleValueWidget(*baseGraph, provider, infoRows)
+	ramWidget.Selectors = GetScopeSelectors(f.System)
+	ramWidget.Stacked = &StackedMetricsDataProvider{
+		System:   f.System,
+		Title:    "RAM COMPOSITION",
+		MaxValue: 100.0,
+		Metrics:  memoryCompositionMetrics(f.System.GetColorScheme()),
+	}
+
+	return ramWidget
 }
 
 // CreateDiskWidget creates a Disk monitoring widget
@@ -63,7 +129,7 @@ func (f *WidgetFactory) CreateDiskWidget() widgets.MonitorWidget {
 	provider := &DiskDataProvider{System: f.System}
 	infoRows := GetDiskInfoProvider(f.System)
 
-	return widgets.NewDualValueWidget(
+	diskWidget := widgets.NewDualValueWidget(
 		*baseGraph,
 		provider,
 		infoRows,
@@ -72,6 +138,68 @@ func (f *WidgetFactory) CreateDiskWidget() widgets.MonitorWidget {
 		"",
 		"R:%.1f W:%.1f MB/s",
 	)
+	diskWidget.Selectors = GetDiskSelectors(f.System)
+	diskWidget.Extra = diskSeriesProviders(f.System)
+
+	return diskWidget
+}
+
+// CreateSensorsWidget creates a temperature widget with the per-sensor and fan trends and the sensor table
+func (f *WidgetFactory) CreateSensorsWidget() widgets.MonitorWidget {
+	baseGraph := f.createBaseGraph()
+	provider := &SensorsDataProvider{System: f.System}
+	infoRows := GetSensorsInfoProvider(f.System)
+
+	temperatureWidget := widgets.NewSingleValueWidget(*baseGraph, provider, infoRows)
+	temperatureWidget.Extra = []widgets.SeriesDataProvider{
+		&SensorSeriesDataProvider{
+			System:   f.System,
+			Kind:     collectors.SensorTemperature,
+			Title:    "TEMPERATURES °C",
+			MaxValue: 100.0,
+		},
+		&SensorSeriesDataProvider{
+			System:   f.System,
+			Kind:     collectors.SensorFan,
+			Title:    "FANS RPM",
+			MaxValue: 1000.0,
+		},
+	}
+
+	// The compact view of the table keeps the sensor and its value
+	tableWidget := widgets.NewTableWidget(&SensorsTableDataProvider{System: f.System}, []int{0, 1})
+
+	return widgets.NewWidgetGroup(temperatureWidget, tableWidget)
+}
+
+// CreateBatteryWidget creates a battery charge widget with the charge or discharge power trend
+func (f *WidgetFactory) CreateBatteryWidget() widgets.MonitorWidget {
+	baseGraph := f.createBaseGraph()
+	provider := &BatteryDataProvider{System: f.System}
+	infoRows := GetBatteryInfoProvider(f.System)
+
+	batteryWidget := widgets.NewSingleValueWidget(*baseGraph, provider, infoRows)
+	batteryWidget.Extra = []widgets.SeriesDataProvider{
+		&StackedMetricsDataProvider{
+			System:   f.System,
+			Title:    "POWER W",
+			MaxValue: 50.0,
+			Metrics: []StackedMetric{
+				{Label: "charge", Metric: collectors.MetricBatteryCharge, Color: f.System.GetColorScheme().Success},
+				{Label: "discharge", Metric: collectors.MetricBatteryDischarge, Color: f.System.GetColorScheme().Warning},
+			},
+		},
+	}
+
+	return batteryWidget
+}
+
+// CreateFilesystemWidget creates a mounted filesystem table widget
+func (f *WidgetFactory) CreateFilesystemWidget() widgets.MonitorWidget {
+	provider := &FilesystemDataProvider{System: f.System}
+
+	// The compact view keeps the mount point and its used percentage
+	return widgets.NewTableWidget(provider, []int{0, 6})
 }
 
 // CreateNetworkWidget creates a Network monitoring widget
@@ -80,7 +208,7 @@ func (f *WidgetFactory) CreateNetworkWidget() widgets.MonitorWidget {
 	provider := &NetworkDataProvider{System: f.System}
 	infoRows := GetNetworkInfoProvider(f.System)
 
-	return widgets.NewDualValueWidget(
+	networkWidget := widgets.NewDualValueWidget(
 		*baseGraph,
 		provider,
 		infoRows,
@@ -88,33 +216,213 @@ func (f *WidgetFactory) CreateNetworkWidget() widgets.MonitorWidget {
 		"",
 		"D:%.1f U:%.1f MB/s",
 	)
+	networkWidget.Selectors = GetNetworkSelectors(f.System)
+
+	return networkWidget
+}
+
+// CreateConnectionsWidget creates a TCP connection count widget with the per-state trend
+func (f *WidgetFactory) CreateConnectionsWidget() widgets.MonitorWidget {
+	baseGraph := f.createBaseGraph()
+	colorScheme := f.System.GetColorScheme()
+	provider := &MetricDataProvider{
+		System:   f.System,
+		Metric:   collectors.MetricConnTCP,
+		Title:    "Conn",
+		MaxValue: 10,
+		Color:    colorScheme.NET,
+	}
+	infoRows := GetConnectionsInfoProvider(f.System)
+
+	connectionsWidget := widgets.NewSingleValueWidget(*baseGraph, provider, infoRows)
+	connectionsWidget.Extra = []widgets.SeriesDataProvider{
+		&StackedMetricsDataProvider{
+			System:   f.System,
+			Title:    "TCP STATES",
+			MaxValue: 10,
+			Metrics:  connectionStateMetrics(colorScheme),
+		},
+	}
+
+	return connectionsWidget
+}
+
+// CreateListeningWidget creates a listening socket table widget
+func (f *WidgetFactory) CreateListeningWidget() widgets.MonitorWidget {
+	provider := &ListeningDataProvider{System: f.System}
+
+	// The compact view keeps the port and the owning process
+	return widgets.NewTableWidget(provider, []int{2, 4})
+}
+
+// CreateProcessesWidget creates a sortable and filterable process table widget
+func (f *WidgetFactory) CreateProcessesWidget() widgets.MonitorWidget {
+	provider := &ProcessesDataProvider{System: f.System}
+
+	// The compact view keeps the name and CPU usage
+	processesWidget := widgets.NewTableWidget(provider, []int{1, 3})
+	processesWidget.Selectors = GetProcessSelectors(f.System)
+	processesWidget.Inputs = GetProcessInputs(f.System)
+	if f.Actions != nil {
+		processesWidget.RowActions = f.Actions.RowActions()
+	}
+
+	return processesWidget
+}
+
+// CreateProcessTreeWidget creates a filterable process hierarchy widget
+func (f *WidgetFactory) CreateProcessTreeWidget() widgets.MonitorWidget {
+	provider := &ProcessTreeDataProvider{System: f.System}
+
+	// The compact view keeps the indented name and CPU usage
+	treeWidget := widgets.NewTableWidget(provider, []int{1, 3})
+	treeWidget.Inputs = GetProcessTreeInputs(f.System)
+	if f.Actions != nil {
+		treeWidget.RowActions = f.Actions.RowActions()
+	}
+
+	return treeWidget
+}
+
+// CreateAuditLogWidget creates a table of the latest process actions
+func (f *WidgetFactory) CreateAuditLogWidget() widgets.MonitorWidget {
+	provider := &AuditLogDataProvider{Log: f.Actions.Audit}
+
+	// The compact view keeps the action, the process and the result
+	return widgets.NewTableWidget(provider, []int{1, 3, 4})
+}
+
+// CreateSystemWidget creates a table of the host information with a button collecting it again
+func (f *WidgetFactory) CreateSystemWidget() widgets.MonitorWidget {
+	provider := &HostInfoDataProvider{System: f.System}
+
+	// Both columns fit the compact view
+	systemWidget := widgets.NewTableWidget(provider, []int{0, 1})
+	systemWidget.Buttons = []widgets.Button{
+		{
+			Label: "Refresh",
+			OnTapped: func() {
+				f.System.RefreshHostInfo()
+			},
+		},
+	}
+
+	return systemWidget
+}
+
+// CreateWatchWidget creates the CPU, memory and I/O widgets of a watched process group
+func (f *WidgetFactory) CreateWatchWidget(group collectors.WatchGroup) widgets.MonitorWidget {
+	colorScheme := f.System.GetColorScheme()
+	metric := func(kind string) string {
+		return collectors.WatchMetric(group.Name, kind)
+	}
+
+	cpuProvider := &MetricDataProvider{
+		System:    f.System,
+		Metric:    metric(collectors.WatchCPU),
+		Title:     group.Name + " CPU",
+		MaxValue:  100.0,
+		AutoScale: true, // A multi-threaded group may use several CPUs
+		Color:     colorScheme.CPU,
+	}
+	cpuWidget := widgets.NewSingleValueWidget(*f.createBaseGraph(), cpuProvider, GetWatchInfoProvider(f.System, group.Name))
+	cpuWidget.Extra = []widgets.SeriesDataProvider{
+		&StackedMetricsDataProvider{
+			System:   f.System,
+			Title:    "FDS / THREADS",
+			MaxValue: 10,
+			Metrics:  watchCountMetrics(colorScheme, group.Name),
+		},
+	}
+
+	rssProvider := &MetricDataProvider{
+		System:    f.System,
+		Metric:    metric(collectors.WatchRSS),
+		Title:     group.Name + " RSS MB",
+		MaxValue:  100.0,
+		AutoScale: true,
+		Color:     colorScheme.RAM,
+	}
+	ioProvider := &DualMetricDataProvider{
+		System:      f.System,
+		ReadMetric:  metric(collectors.WatchRead),
+		WriteMetric: metric(collectors.WatchWrite),
+		Title:       group.Name + " I/O",
+		MaxValue:    1.0,
+		AutoScale:   true,
+		Color:       colorScheme.DISK,
+	}
+
+	return widgets.NewWidgetGroup(
+		cpuWidget,
+		f.CreateMetricWidget(rssProvider, nil),
+		f.CreateDualMetricWidget(ioProvider, nil, "R:%.1f W:%.1f MB/s"),
+	)
+}
+
+// CreateMetricWidget creates a single value widget for any collector metric
+func (f *WidgetFactory) CreateMetricWidget(provider *MetricDataProvider, infoRows []widgets.InfoRow) widgets.MonitorWidget {
+	baseGraph := f.createBaseGraph()
+
+	return widgets.NewSingleValueWidget(*baseGraph, provider, infoRows)
+}
+
+// CreateDualMetricWidget creates a dual value widget for a pair of collector metrics
+func (f *WidgetFactory) CreateDualMetricWidget(
+	provider *DualMetricDataProvider,
+	infoRows []widgets.InfoRow,
+	compactValueFmt string,
+) widgets.MonitorWidget {
+	baseGraph := f.createBaseGraph()
+
+	return widgets.NewDualValueWidget(*baseGraph, provider, infoRows, "", "", compactValueFmt)
 }
 
 // CreateAllWidgets creates all system monitoring widgets
 func (f *WidgetFactory) CreateAllWidgets() map[ComponentType]widgets.MonitorWidget {
-	return map[ComponentType]widgets.MonitorWidget{
-		CPUComponent:     f.CreateCPUWidget(),
-		RAMComponent:     f.CreateRAMWidget(),
-		DiskComponent:    f.CreateDiskWidget(),
-		NetworkComponent: f.CreateNetworkWidget(),
+	allWidgets := map[ComponentType]widgets.MonitorWidget{
+		CPUComponent:         f.CreateCPUWidget(),
+		CPUCoresComponent:    f.CreateCPUCoresWidget(),
+		LoadComponent:        f.CreateLoadWidget(),
+		RAMComponent:         f.CreateRAMWidget(),
+		DiskComponent:        f.CreateDiskWidget(),
+		FilesystemComponent:  f.CreateFilesystemWidget(),
+		NetworkComponent:     f.CreateNetworkWidget(),
+		ConnectionsComponent: f.CreateConnectionsWidget(),
+		ListeningComponent:   f.CreateListeningWidget(),
+		ProcessesComponent:   f.CreateProcessesWidget(),
+		ProcessTreeComponent: f.CreateProcessTreeWidget(),
+		SensorsComponent:     f.CreateSensorsWidget(),
+		PressureComponent:    f.CreatePressureWidget(),
+		SystemComponent:      f.CreateSystemWidget(),
+	}
+	if f.Actions != nil {
+		allWidgets[AuditLogComponent] = f.CreateAuditLogWidget()
+	}
+	if f.Battery {
+		allWidgets[BatteryComponent] = f.CreateBatteryWidget()
 	}
+	if len(f.Watched) > 0 {
+		watchWidgets := make([]widgets.MonitorWidget, len(f.Watched))
+		for i, group := range f.Watched {
+			watchWidgets[i] = f.CreateWatchWidget(group)
+		}
+		allWidgets[WatchComponent] = widgets.NewWidgetGroup(watchWidgets...)
+	}
+
+	return allWidgets
 }
 
 // CreateMonitoringPanel creates a monitoring panel with all widgets
 func (f *WidgetFactory) CreateMonitoringPanel(showDetails bool) *fyne.Container {
 	allWidgets := f.CreateAllWidgets()
 
-	cpuWidget := container.NewPadded(allWidgets[CPUComponent].CreateViewWithOptions(showDetails))
-	ramWidget := container.NewPadded(allWidgets[RAMComponent].CreateViewWithOptions(showDetails))
-	diskWidget := container.NewPadded(allWidgets[DiskComponent].CreateViewWithOptions(showDetails))
-	netWidget := container.NewPadded(allWidgets[NetworkComponent].CreateViewWithOptions(showDetails))
-
-	panel := container.New(layout.NewVBoxLayout(),
-		cpuWidget,
-		ramWidget,
-		diskWidget,
-		netWidget,
-	)
+	panel := container.New(layout.NewVBoxLayout())
+	for _, component := range PanelComponents {
+		if monitorWidget, ok := allWidgets[component]; ok {
+			panel.Add(container.NewPadded(monitorWidget.CreateViewWithOptions(showDetails)))
+		}
+	}
 
 	return panel
 }
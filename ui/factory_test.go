@@ -3,7 +3,10 @@ package ui
 import (
 	"testing"
 
+	"go-dummy-monitor/collectors"
 	"go-dummy-monitor/constants"
+	"go-dummy-monitor/procctl"
+	"go-dummy-monitor/ui/widgets"
 )
 
 func TestCreateBaseGraph(t *testing.T) {
@@ -157,6 +160,12 @@ func TestCreateAllWidgets(t *testing.T) {
 	if widgets[CPUComponent] == nil {
 		t.Error("Expected CPU widget to not be nil")
 	}
+	if widgets[CPUCoresComponent] == nil {
+		t.Error("Expected CPU cores widget to not be nil")
+	}
+	if widgets[LoadComponent] == nil {
+		t.Error("Expected load widget to not be nil")
+	}
 	if widgets[RAMComponent] == nil {
 		t.Error("Expected RAM widget to not be nil")
 	}
@@ -166,4 +175,191 @@ func TestCreateAllWidgets(t *testing.T) {
 	if widgets[NetworkComponent] == nil {
 		t.Error("Expected Network widget to not be nil")
 	}
+	if widgets[ConnectionsComponent] == nil {
+		t.Error("Expected connections widget to not be nil")
+	}
+	if widgets[ListeningComponent] == nil {
+		t.Error("Expected listening widget to not be nil")
+	}
+	if widgets[ProcessTreeComponent] == nil {
+		t.Error("Expected process tree widget to not be nil")
+	}
+	if widgets[SensorsComponent] == nil {
+		t.Error("Expected sensors widget to not be nil")
+	}
+	if widgets[PressureComponent] == nil {
+		t.Error("Expected pressure widget to not be nil")
+	}
+	if widgets[SystemComponent] == nil {
+		t.Error("Expected system widget to not be nil")
+	}
+
+	// The audit log widget is only shown when process actions are available
+	if widgets[AuditLogComponent] != nil {
+		t.Error("Expected no audit log widget without process actions")
+	}
+	factory.Actions = &ProcessActions{System: system, Audit: procctl.NewAuditLog(nil)}
+	if factory.CreateAllWidgets()[AuditLogComponent] == nil {
+		t.Error("Expected audit log widget to not be nil")
+	}
+}
+
+func TestCreateCPUCoresWidget(t *testing.T) {
+	// Create a test system
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	// Create a widget factory
+	factory := NewWidgetFactory(system)
+
+	// Create a per-core CPU widget
+	widget := factory.CreateCPUCoresWidget()
+
+	// Test basic properties
+	if widget == nil {
+		t.Error("Expected widget to not be nil")
+	}
+}
+
+func TestCreateLoadWidget(t *testing.T) {
+	// Create a test system
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	// Create a widget factory
+	factory := NewWidgetFactory(system)
+
+	// Create a load widget
+	widget := factory.CreateLoadWidget()
+
+	// Test basic properties
+	if widget == nil {
+		t.Error("Expected widget to not be nil")
+	}
+}
+
+func TestCreateFilesystemWidget(t *testing.T) {
+	// Create a test system
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	// Create a widget factory
+	factory := NewWidgetFactory(system)
+
+	// Create a filesystem widget
+	widget := factory.CreateFilesystemWidget()
+
+	// Test basic properties
+	if widget == nil {
+		t.Error("Expected widget to not be nil")
+	}
+}
+
+func TestCreateConnectionsWidget(t *testing.T) {
+	// Create a test system
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	// Create a widget factory
+	factory := NewWidgetFactory(system)
+
+	// Create a connections widget
+	widget := factory.CreateConnectionsWidget()
+
+	// Test basic properties
+	if widget == nil {
+		t.Fatal("Expected widget to not be nil")
+	}
+	if widget.CreateDetailedView() == nil {
+		t.Error("Expected detailed view to not be nil")
+	}
+}
+
+func TestCreateWatchWidget(t *testing.T) {
+	// Create a test system
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	// Create a widget factory watching one process group
+	group, err := collectors.NewWatchGroup(collectors.WatchByName, "gopls")
+	if err != nil {
+		t.Fatalf("Expected a watch group, got %v", err)
+	}
+	factory := NewWidgetFactory(system)
+
+	// Without watched groups there is no watch widget
+	if factory.CreateAllWidgets()[WatchComponent] != nil {
+		t.Error("Expected no watch widget without watched groups")
+	}
+
+	factory.Watched = []collectors.WatchGroup{group}
+	widget, ok := factory.CreateAllWidgets()[WatchComponent].(*widgets.WidgetGroup)
+	if !ok {
+		t.Fatal("Expected the watch component to be a widget group")
+	}
+
+	// One CPU, RSS and I/O widget per group
+	if len(widget.Widgets) != 1 || len(widget.Widgets[0].(*widgets.WidgetGroup).Widgets) != 3 {
+		t.Errorf("Expected three widgets for the watched group, got %+v", widget.Widgets)
+	}
+	if widget.CreateDetailedView() == nil {
+		t.Error("Expected detailed view to not be nil")
+	}
+}
+
+func TestCreateBatteryWidget(t *testing.T) {
+	// Create a test system
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+	factory := NewWidgetFactory(system)
+
+	// The battery widget only appears on machines with a battery
+	if factory.CreateAllWidgets()[BatteryComponent] != nil {
+		t.Error("Expected no battery widget without a battery")
+	}
+
+	factory.Battery = true
+	widget := factory.CreateAllWidgets()[BatteryComponent]
+	if widget == nil {
+		t.Fatal("Expected battery widget to not be nil")
+	}
+	if widget.CreateDetailedView() == nil || widget.CreateCompactView() == nil {
+		t.Error("Expected battery views to not be nil")
+	}
 }
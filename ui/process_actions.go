@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	"go-dummy-monitor/collectors"
+	"go-dummy-monitor/procctl"
+	"go-dummy-monitor/ui/widgets"
+)
+
+// ProcessActionTimeout bounds how long a single process action may take
+const ProcessActionTimeout = 5 * time.Second
+
+// ReniceStep is how much the renice actions change the nice value by
+const ReniceStep = 5
+
+// processAction is a signal or renice action offered in the process tables
+type processAction struct {
+	Label    string // Shown in the menu and recorded in the audit log
+	Question string // Confirmation question, formatted with the process name and PID
+	Run      func(ctx context.Context, pid int32) error
+}
+
+// processActions returns the actions offered for every process
+func processActions() []processAction {
+	var actions []processAction
+	for _, sig := range procctl.Signals {
+		actions = append(actions, processAction{
+			Label:    string(sig),
+			Question: "Send " + string(sig) + " to %s (PID %d)?",
+			Run: func(ctx context.Context, pid int32) error {
+				return procctl.Send(ctx, pid, sig)
+			},
+		})
+	}
+	for _, delta := range []int{ReniceStep, -ReniceStep} {
+		actions = append(actions, processAction{
+			Label:    fmt.Sprintf("renice %+d", delta),
+			Question: fmt.Sprintf("Change the nice value of %%s (PID %%d) by %+d?", delta),
+			Run: func(ctx context.Context, pid int32) error {
+				_, err := procctl.Renice(ctx, pid, delta)
+				return err
+			},
+		})
+	}
+	return actions
+}
+
+// ProcessActions performs the signal and renice actions chosen in the process tables.
+// Every action is confirmed first and recorded in the audit log whether it succeeds or not.
+type ProcessActions struct {
+	System    MonitoringSystem
+	Audit     *procctl.AuditLog
+	Confirm   func(title, message string, onConfirmed func())
+	ShowError func(err error)
+}
+
+// NewProcessActions creates ProcessActions that confirm and report errors in dialogs on the window
+func NewProcessActions(system MonitoringSystem, audit *procctl.AuditLog, window fyne.Window) *ProcessActions {
+	return &ProcessActions{
+		System: system,
+		Audit:  audit,
+		Confirm: func(title, message string, onConfirmed func()) {
+			dialog.ShowConfirm(title, message, func(confirmed bool) {
+				if confirmed {
+					onConfirmed()
+				}
+			}, window)
+		},
+		ShowError: func(err error) {
+			dialog.ShowError(err, window)
+		},
+	}
+}
+
+// RowActions returns the actions for tables whose first column is the PID
+func (a *ProcessActions) RowActions() []widgets.RowAction {
+	actions := processActions()
+	rowActions := make([]widgets.RowAction, len(actions))
+	for i, action := range actions {
+		rowActions[i] = widgets.RowAction{
+			Label: action.Label,
+			OnSelected: func(row []string) {
+				a.request(action, row)
+			},
+		}
+	}
+	return rowActions
+}
+
+// request asks to confirm the action on the process of the row
+func (a *ProcessActions) request(action processAction, row []string) {
+	proc, err := a.rowProcess(row)
+	if err != nil {
+		a.ShowError(err)
+		return
+	}
+
+	a.Confirm(action.Label, fmt.Sprintf(action.Question, proc.Name, proc.PID), func() {
+		a.perform(action, proc)
+	})
+}
+
+// rowProcess finds the sampled process of a table row
+func (a *ProcessActions) rowProcess(row []string) (collectors.ProcessInfo, error) {
+	if len(row) == 0 {
+		return collectors.ProcessInfo{}, errors.New("no process selected")
+	}
+	pid, err := strconv.ParseInt(row[0], 10, 32)
+	if err != nil {
+		return collectors.ProcessInfo{}, fmt.Errorf("invalid PID %q", row[0])
+	}
+	for _, proc := range sampledProcesses(a.System.GetSnapshot()) {
+		if proc.PID == int32(pid) {
+			return proc, nil
+		}
+	}
+	return collectors.ProcessInfo{}, fmt.Errorf("process %d is no longer running", pid)
+}
+
+// perform runs the confirmed action unless the PID now names another process, and records the outcome
+func (a *ProcessActions) perform(action processAction, proc collectors.ProcessInfo) {
+	ctx, cancel := context.WithTimeout(context.Background(), ProcessActionTimeout)
+	defer cancel()
+
+	err := procctl.Verify(ctx, proc.PID, proc.CreateTime)
+	if err == nil {
+		err = action.Run(ctx, proc.PID)
+	}
+
+	entry := procctl.AuditEntry{
+		Time:    time.Now(),
+		Action:  action.Label,
+		PID:     proc.PID,
+		Process: proc.Name,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if auditErr := a.Audit.Record(entry); auditErr != nil && err == nil {
+		err = fmt.Errorf("%s was done but could not be written to the audit log: %w", action.Label, auditErr)
+	}
+
+	if err != nil {
+		a.ShowError(err)
+	}
+}
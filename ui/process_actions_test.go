@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/shirou/gopsutil/process"
+
+	"go-dummy-monitor/collectors"
+	"go-dummy-monitor/constants"
+	"go-dummy-monitor/procctl"
+	"go-dummy-monitor/ui/widgets"
+)
+
+// testProcessActions creates ProcessActions over a fixed process table that
+// confirm as told and collect the reported errors instead of showing dialogs
+func testProcessActions(t *testing.T, procs []collectors.ProcessInfo, confirm bool) (*ProcessActions, *[]string, *[]error) {
+	t.Helper()
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(&processTableCollector{processes: procs})
+	system.UpdateSystemStats()
+
+	var questions []string
+	var errs []error
+	actions := &ProcessActions{
+		System: system,
+		Audit:  procctl.NewAuditLog(nil),
+		Confirm: func(_, message string, onConfirmed func()) {
+			questions = append(questions, message)
+			if confirm {
+				onConfirmed()
+			}
+		},
+		ShowError: func(err error) {
+			errs = append(errs, err)
+		},
+	}
+	return actions, &questions, &errs
+}
+
+// rowAction returns the row action with the given label
+func rowAction(t *testing.T, actions *ProcessActions, label string) widgets.RowAction {
+	t.Helper()
+	for _, action := range actions.RowActions() {
+		if action.Label == label {
+			return action
+		}
+	}
+	t.Fatalf("Expected a %s row action", label)
+	return widgets.RowAction{}
+}
+
+func TestProcessActionsOffered(t *testing.T) {
+	actions, _, _ := testProcessActions(t, nil, false)
+
+	var labels []string
+	for _, action := range actions.RowActions() {
+		labels = append(labels, action.Label)
+	}
+	want := "SIGTERM SIGKILL SIGSTOP SIGCONT renice +5 renice -5"
+	if strings.Join(labels, " ") != want {
+		t.Errorf("Expected actions %q, got %q", want, strings.Join(labels, " "))
+	}
+}
+
+func TestProcessActionsConfirmAndAudit(t *testing.T) {
+	// Continuing the test process itself is harmless
+	pid := int32(os.Getpid())
+	self, err := process.NewProcess(pid)
+	if err != nil {
+		t.Fatalf("Expected the test process, got %v", err)
+	}
+	createTime, err := self.CreateTime()
+	if err != nil {
+		t.Fatalf("Expected the start time of the test process, got %v", err)
+	}
+	procs := []collectors.ProcessInfo{{PID: pid, Name: "ui.test", CreateTime: createTime}}
+	row := []string{strconv.Itoa(int(pid)), "ui.test"}
+
+	// Declining the confirmation does nothing
+	declined, questions, _ := testProcessActions(t, procs, false)
+	rowAction(t, declined, "SIGCONT").OnSelected(row)
+	if len(*questions) != 1 || (*questions)[0] != "Send SIGCONT to ui.test (PID "+row[0]+")?" {
+		t.Errorf("Expected one confirmation question, got %q", *questions)
+	}
+	if entries := declined.Audit.Entries(); len(entries) != 0 {
+		t.Errorf("Expected no audit entries after declining, got %+v", entries)
+	}
+
+	confirmed, _, errs := testProcessActions(t, procs, true)
+	rowAction(t, confirmed, "SIGCONT").OnSelected(row)
+	if len(*errs) != 0 {
+		t.Errorf("Expected no errors, got %v", *errs)
+	}
+	entries := confirmed.Audit.Entries()
+	if len(entries) != 1 || entries[0].Action != "SIGCONT" || entries[0].PID != pid || entries[0].Error != "" {
+		t.Errorf("Expected a successful SIGCONT in the audit log, got %+v", entries)
+	}
+}
+
+func TestProcessActionsReusedPID(t *testing.T) {
+	// The sampled start time does not match the running process, so the PID was reused
+	pid := int32(os.Getpid())
+	procs := []collectors.ProcessInfo{{PID: pid, Name: "old", CreateTime: 1}}
+
+	actions, _, errs := testProcessActions(t, procs, true)
+	rowAction(t, actions, "SIGKILL").OnSelected([]string{strconv.Itoa(int(pid))})
+
+	if len(*errs) != 1 {
+		t.Fatalf("Expected one error, got %v", *errs)
+	}
+	entries := actions.Audit.Entries()
+	if len(entries) != 1 || entries[0].Error == "" {
+		t.Errorf("Expected the refused action in the audit log, got %+v", entries)
+	}
+}
+
+func TestProcessActionsUnknownProcess(t *testing.T) {
+	actions, questions, errs := testProcessActions(t, nil, true)
+	rowAction(t, actions, "SIGTERM").OnSelected([]string{"12345"})
+
+	if len(*questions) != 0 || len(*errs) != 1 {
+		t.Errorf("Expected an error without confirmation, got questions %q and errors %v", *questions, *errs)
+	}
+}
@@ -3,7 +3,12 @@ package ui
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"strings"
+	"time"
 
+	"go-dummy-monitor/collectors"
+	"go-dummy-monitor/procctl"
 	"go-dummy-monitor/ui/widgets"
 )
 
@@ -13,6 +18,9 @@ type CPUDataProvider struct {
 }
 
 func (c *CPUDataProvider) GetData() []float64 {
+	if scope := selectedScope(c.System); scope != "" {
+		return c.System.GetMetricData(collectors.CgroupMetric(scope, collectors.CgroupCPU))
+	}
 	return c.System.GetCPUData()
 }
 
@@ -21,10 +29,16 @@ func (c *CPUDataProvider) GetMaxValue() float64 {
 }
 
 func (c *CPUDataProvider) GetCurrentValue() float64 {
+	if scope := selectedScope(c.System); scope != "" {
+		return c.System.GetMetricValue(collectors.CgroupMetric(scope, collectors.CgroupCPU))
+	}
 	return c.System.GetCPUUsage()
 }
 
 func (c *CPUDataProvider) GetTitle() string {
+	if scope := selectedScope(c.System); scope != "" {
+		return "CPU " + scope
+	}
 	return "CPU"
 }
 
@@ -32,12 +46,83 @@ func (c *CPUDataProvider) GetColor() color.Color {
 	return c.System.GetColorScheme().CPU
 }
 
+// CPUCoresDataProvider provides per-core CPU data for the heatmap
+type CPUCoresDataProvider struct {
+	System MonitoringSystem
+}
+
+func (c *CPUCoresDataProvider) GetRows() [][]float64 {
+	return cpuCoreRows(c.System.GetSnapshot())
+}
+
+func (c *CPUCoresDataProvider) GetMaxValue() float64 {
+	return 100.0 // CPU percentage is always 0-100
+}
+
+func (c *CPUCoresDataProvider) GetTitle() string {
+	return "Cores"
+}
+
+func (c *CPUCoresDataProvider) GetColor() color.Color {
+	return c.System.GetColorScheme().CPU
+}
+
+// cpuCoreRows returns the history of every logical CPU found in the snapshot
+func cpuCoreRows(snapshot *Snapshot) [][]float64 {
+	var rows [][]float64
+	for core := 0; snapshot.Has(collectors.CoreMetric(core)); core++ {
+		rows = append(rows, snapshot.History(collectors.CoreMetric(core)))
+	}
+	return rows
+}
+
+// LoadDataProvider provides the 1-minute load average normalized by the logical CPU count
+type LoadDataProvider struct {
+	System MonitoringSystem
+}
+
+func (l *LoadDataProvider) GetData() []float64 {
+	cpus := logicalCPUs(l.System)
+	data := l.System.GetMetricData(collectors.MetricLoad1)
+	for i := range data {
+		data[i] /= cpus
+	}
+	return data
+}
+
+func (l *LoadDataProvider) GetMaxValue() float64 {
+	return 1.0 // One runnable task per logical CPU
+}
+
+func (l *LoadDataProvider) GetCurrentValue() float64 {
+	return l.System.GetMetricValue(collectors.MetricLoad1) / logicalCPUs(l.System)
+}
+
+func (l *LoadDataProvider) GetTitle() string {
+	return "Load"
+}
+
+func (l *LoadDataProvider) GetColor() color.Color {
+	return l.System.GetColorScheme().CPU
+}
+
+// logicalCPUs returns the logical CPU count as a divisor that is never zero
+func logicalCPUs(system MonitoringSystem) float64 {
+	if count := system.GetHostInfo().LogicalCores; count > 0 {
+		return float64(count)
+	}
+	return 1
+}
+
 // RAMDataProvider provides RAM-specific data for graphing
 type RAMDataProvider struct {
 	System MonitoringSystem
 }
 
 func (r *RAMDataProvider) GetData() []float64 {
+	if scope := selectedScope(r.System); scope != "" {
+		return r.System.GetMetricData(collectors.CgroupMetric(scope, collectors.CgroupMemory))
+	}
 	return r.System.GetRAMData()
 }
 
@@ -46,10 +131,16 @@ func (r *RAMDataProvider) GetMaxValue() float64 {
 }
 
 func (r *RAMDataProvider) GetCurrentValue() float64 {
+	if scope := selectedScope(r.System); scope != "" {
+		return r.System.GetMetricValue(collectors.CgroupMetric(scope, collectors.CgroupMemory))
+	}
 	return r.System.GetRAMUsage()
 }
 
 func (r *RAMDataProvider) GetTitle() string {
+	if scope := selectedScope(r.System); scope != "" {
+		return "RAM " + scope
+	}
 	return "RAM"
 }
 
@@ -57,17 +148,75 @@ func (r *RAMDataProvider) GetColor() color.Color {
 	return r.System.GetColorScheme().RAM
 }
 
-// DiskDataProvider provides Disk-specific data for graphing
+// sampledCgroups returns the cgroups published by the cgroup collector
+func sampledCgroups(snapshot *Snapshot) []collectors.CgroupUsage {
+	cgroups, _ := snapshot.Detail("cgroups").([]collectors.CgroupUsage)
+	return cgroups
+}
+
+// selectedScope returns the cgroup chosen for the CPU and RAM widgets, or an empty string for the host.
+// A cgroup that is not reported (yet) shows the host but stays selected.
+func selectedScope(system MonitoringSystem) string {
+	scope := system.GetSelection(SelectionScope)
+	if scope == "" || scope == HostScopeOption || !system.GetSnapshot().Has(collectors.CgroupMetric(scope, collectors.CgroupCPU)) {
+		return ""
+	}
+	return scope
+}
+
+// selectedCgroup returns the usage of the chosen cgroup, false for the host
+func selectedCgroup(system MonitoringSystem) (collectors.CgroupUsage, bool) {
+	scope := selectedScope(system)
+	if scope == "" {
+		return collectors.CgroupUsage{}, false
+	}
+	for _, cgroup := range sampledCgroups(system.GetSnapshot()) {
+		if cgroup.Path == scope {
+			return cgroup, true
+		}
+	}
+	return collectors.CgroupUsage{}, false
+}
+
+// GetScopeSelectors returns the selector switching the CPU and RAM widgets between the host and a cgroup
+func GetScopeSelectors(system MonitoringSystem) []widgets.Selector {
+	return []widgets.Selector{
+		{
+			Label: "Scope",
+			GetOptions: func() []string {
+				options := []string{HostScopeOption}
+				for _, cgroup := range sampledCgroups(system.GetSnapshot()) {
+					options = append(options, cgroup.Path)
+				}
+				return options
+			},
+			GetSelected: func() string {
+				if scope := selectedScope(system); scope != "" {
+					return scope
+				}
+				return HostScopeOption
+			},
+			OnChanged: func(value string) {
+				system.SetSelection(SelectionScope, value)
+			},
+		},
+	}
+}
+
+// DiskDataProvider provides Disk-specific data for graphing, either
+// summed over all physical disks or for the device chosen in the selector
 type DiskDataProvider struct {
 	System MonitoringSystem
 }
 
 func (d *DiskDataProvider) GetReadData() []float64 {
-	return d.System.GetDiskReadData()
+	readMetric, _ := selectedDiskMetrics(d.System)
+	return d.System.GetMetricData(readMetric)
 }
 
 func (d *DiskDataProvider) GetWriteData() []float64 {
-	return d.System.GetDiskWriteData()
+	_, writeMetric := selectedDiskMetrics(d.System)
+	return d.System.GetMetricData(writeMetric)
 }
 
 func (d *DiskDataProvider) GetMaxValue() float64 {
@@ -75,22 +224,19 @@ func (d *DiskDataProvider) GetMaxValue() float64 {
 }
 
 func (d *DiskDataProvider) GetCurrentReadValue() float64 {
-	data := d.System.GetDiskReadData()
-	if len(data) > 0 {
-		return data[len(data)-1]
-	}
-	return 0
+	readMetric, _ := selectedDiskMetrics(d.System)
+	return d.System.GetMetricValue(readMetric)
 }
 
 func (d *DiskDataProvider) GetCurrentWriteValue() float64 {
-	data := d.System.GetDiskWriteData()
-	if len(data) > 0 {
-		return data[len(data)-1]
-	}
-	return 0
+	_, writeMetric := selectedDiskMetrics(d.System)
+	return d.System.GetMetricValue(writeMetric)
 }
 
 func (d *DiskDataProvider) GetTitle() string {
+	if device := selectedDiskDevice(d.System); device != "" {
+		return "Disk " + device
+	}
 	return "Disk"
 }
 
@@ -98,17 +244,228 @@ func (d *DiskDataProvider) GetColor() color.Color {
 	return d.System.GetColorScheme().DISK
 }
 
-// NetworkDataProvider provides Network-specific data for graphing
+// selectedDiskDevice returns the chosen disk device, or an empty string for all devices
+func selectedDiskDevice(system MonitoringSystem) string {
+	device := system.GetSelection(SelectionDiskDevice)
+	if device == AllDevicesOption || !system.GetSnapshot().Has(collectors.DiskDeviceReadMetric(device)) {
+		return ""
+	}
+	return device
+}
+
+// selectedDiskMetrics returns the read and write metric names for the chosen disk device
+func selectedDiskMetrics(system MonitoringSystem) (string, string) {
+	if device := selectedDiskDevice(system); device != "" {
+		return collectors.DiskDeviceReadMetric(device), collectors.DiskDeviceWriteMetric(device)
+	}
+	return collectors.MetricDiskRead, collectors.MetricDiskWrite
+}
+
+// selectedDiskMetric returns the metric name of one disk measurement kind for the chosen device
+func selectedDiskMetric(system MonitoringSystem, kind string) string {
+	if device := selectedDiskDevice(system); device != "" {
+		return collectors.DiskDeviceMetric(device, kind)
+	}
+	return "disk." + kind
+}
+
+// DiskSeries describes one line of a DiskSeriesDataProvider
+type DiskSeries struct {
+	Label string
+	Kind  string // One of the collectors.Disk* measurement kinds
+	Color color.Color
+}
+
+// DiskSeriesDataProvider provides several disk measurements of the chosen device as graph series
+type DiskSeriesDataProvider struct {
+	System    MonitoringSystem
+	Title     string
+	MaxValue  float64
+	AutoScale bool // Grow the axis beyond MaxValue to fit the history
+	Series    []DiskSeries
+}
+
+func (d *DiskSeriesDataProvider) GetSeries() []widgets.Series {
+	snapshot := d.System.GetSnapshot()
+
+	series := make([]widgets.Series, 0, len(d.Series))
+	for _, s := range d.Series {
+		series = append(series, widgets.Series{
+			Label: s.Label,
+			Data:  snapshot.History(selectedDiskMetric(d.System, s.Kind)),
+			Color: s.Color,
+		})
+	}
+	return series
+}
+
+func (d *DiskSeriesDataProvider) GetMaxValue() float64 {
+	if !d.AutoScale {
+		return d.MaxValue
+	}
+	var histories [][]float64
+	for _, series := range d.GetSeries() {
+		histories = append(histories, series.Data)
+	}
+	return scaledMaxValue(d.MaxValue, histories...)
+}
+
+func (d *DiskSeriesDataProvider) GetTitle() string {
+	return d.Title
+}
+
+// diskSeriesProviders returns the IOPS, utilization and await graphs drawn below the Disk throughput graph.
+// Utilization is a percentage on a fixed axis, IOPS and await are unbounded and scale to their history.
+func diskSeriesProviders(system MonitoringSystem) []widgets.SeriesDataProvider {
+	colorScheme := system.GetColorScheme()
+	return []widgets.SeriesDataProvider{
+		&DiskSeriesDataProvider{
+			System:    system,
+			Title:     "IOPS",
+			MaxValue:  100,
+			AutoScale: true,
+			Series: []DiskSeries{
+				{Label: "read", Kind: collectors.DiskReadIOPS, Color: colorScheme.DISK},
+				{Label: "write", Kind: collectors.DiskWriteIOPS, Color: colorScheme.Warning},
+			},
+		},
+		&DiskSeriesDataProvider{
+			System:   system,
+			Title:    "Utilization",
+			MaxValue: 100,
+			Series: []DiskSeries{
+				{Label: "util %", Kind: collectors.DiskUtil, Color: colorScheme.Error},
+			},
+		},
+		&DiskSeriesDataProvider{
+			System:    system,
+			Title:     "Await",
+			MaxValue:  10,
+			AutoScale: true,
+			Series: []DiskSeries{
+				{Label: "await ms", Kind: collectors.DiskAwait, Color: colorScheme.NET},
+			},
+		},
+	}
+}
+
+// GetDiskSelectors returns the disk device selector
+func GetDiskSelectors(system MonitoringSystem) []widgets.Selector {
+	return []widgets.Selector{
+		{
+			Label: "Device",
+			GetOptions: func() []string {
+				return append([]string{AllDevicesOption}, collectors.DiskDevices(system.GetSnapshot().Names())...)
+			},
+			GetSelected: func() string {
+				if device := selectedDiskDevice(system); device != "" {
+					return device
+				}
+				return AllDevicesOption
+			},
+			OnChanged: func(value string) {
+				system.SetSelection(SelectionDiskDevice, value)
+			},
+		},
+		{
+			Label: "Mount",
+			GetOptions: func() []string {
+				var mounts []string
+				for _, fs := range mountedFilesystems(system.GetSnapshot()) {
+					mounts = append(mounts, fs.Mountpoint)
+				}
+				return mounts
+			},
+			GetSelected: func() string {
+				mount, _ := selectedMountUsage(system)
+				return mount
+			},
+			OnChanged: func(value string) {
+				system.SetSelection(SelectionDiskMount, value)
+			},
+		},
+	}
+}
+
+// selectedMountUsage returns the mount point chosen for the usage row and its used percentage,
+// falling back to the root filesystem
+func selectedMountUsage(system MonitoringSystem) (string, float64) {
+	snapshot := system.GetSnapshot()
+	mount := system.GetSelection(SelectionDiskMount)
+	if mount != "" && snapshot.Has(collectors.FilesystemUsageMetric(mount)) {
+		return mount, snapshot.Value(collectors.FilesystemUsageMetric(mount))
+	}
+	return "/", snapshot.Value(collectors.MetricDiskUsage)
+}
+
+// mountedFilesystems returns the filesystem table published in the snapshot
+func mountedFilesystems(snapshot *Snapshot) []collectors.FilesystemUsage {
+	filesystems, _ := snapshot.Detail("filesystem").([]collectors.FilesystemUsage)
+	return filesystems
+}
+
+// FilesystemDataProvider provides the mounted filesystem table
+type FilesystemDataProvider struct {
+	System MonitoringSystem
+}
+
+func (f *FilesystemDataProvider) GetHeaders() []string {
+	return []string{"Mount", "Device", "Type", "Size", "Used", "Free", "Use%", "Inodes%"}
+}
+
+func (f *FilesystemDataProvider) GetRows() [][]string {
+	filesystems := mountedFilesystems(f.System.GetSnapshot())
+
+	rows := make([][]string, 0, len(filesystems))
+	for _, fs := range filesystems {
+		inodes := "-"
+		if fs.InodesTotal > 0 {
+			inodes = fmt.Sprintf("%.1f%%", fs.InodesUsedPercent)
+		}
+		rows = append(rows, []string{
+			fs.Mountpoint,
+			fs.Device,
+			fs.Fstype,
+			formatBytes(fs.Total),
+			formatBytes(fs.Used),
+			formatBytes(fs.Free),
+			fmt.Sprintf("%.1f%%", fs.UsedPercent),
+			inodes,
+		})
+	}
+	return rows
+}
+
+func (f *FilesystemDataProvider) GetTitle() string {
+	return "Filesystems"
+}
+
+// formatBytes formats a byte count with a binary unit suffix
+func formatBytes(bytes uint64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	value := float64(bytes)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
+
+// NetworkDataProvider provides Network-specific data for graphing, either
+// for the active interface, all physical interfaces or the interface chosen in its selector
 type NetworkDataProvider struct {
 	System MonitoringSystem
 }
 
 func (n *NetworkDataProvider) GetReadData() []float64 {
-	return n.System.GetNetworkReadData()
+	readMetric, _ := selectedNetMetrics(n.System)
+	return n.System.GetMetricData(readMetric)
 }
 
 func (n *NetworkDataProvider) GetWriteData() []float64 {
-	return n.System.GetNetworkWriteData()
+	_, writeMetric := selectedNetMetrics(n.System)
+	return n.System.GetMetricData(writeMetric)
 }
 
 func (n *NetworkDataProvider) GetMaxValue() float64 {
@@ -116,141 +473,1363 @@ func (n *NetworkDataProvider) GetMaxValue() float64 {
 }
 
 func (n *NetworkDataProvider) GetCurrentReadValue() float64 {
-	data := n.System.GetNetworkReadData()
-	if len(data) > 0 {
-		return data[len(data)-1]
-	}
-	return 0
+	readMetric, _ := selectedNetMetrics(n.System)
+	return n.System.GetMetricValue(readMetric)
 }
 
 func (n *NetworkDataProvider) GetCurrentWriteValue() float64 {
-	data := n.System.GetNetworkWriteData()
-	if len(data) > 0 {
-		return data[len(data)-1]
-	}
-	return 0
+	_, writeMetric := selectedNetMetrics(n.System)
+	return n.System.GetMetricValue(writeMetric)
 }
 
 func (n *NetworkDataProvider) GetTitle() string {
-	return "Net"
+	switch iface := selectedNetInterface(n.System); iface {
+	case ActiveInterfaceOption:
+		return "Net"
+	case AllPhysicalOption:
+		return "Net all"
+	default:
+		return "Net " + iface
+	}
 }
 
 func (n *NetworkDataProvider) GetColor() color.Color {
 	return n.System.GetColorScheme().NET
 }
 
-// GetDiskInfoProvider returns disk info functions
-func GetDiskInfoProvider(system MonitoringSystem) []widgets.InfoRow {
-	return []widgets.InfoRow{
+// GetHighlight flags the widget in the error color on interface errors and in the warning color on drops
+func (n *NetworkDataProvider) GetHighlight() color.Color {
+	snapshot := n.System.GetSnapshot()
+	rate := func(kind string) float64 {
+		return snapshot.Value(selectedNetMetric(n.System, kind))
+	}
+
+	switch {
+	case rate(collectors.NetErrorsIn)+rate(collectors.NetErrorsOut) > 0:
+		return n.System.GetColorScheme().Error
+	case rate(collectors.NetDropsIn)+rate(collectors.NetDropsOut) > 0:
+		return n.System.GetColorScheme().Warning
+	default:
+		return nil
+	}
+}
+
+// selectedNetInterface returns the chosen interface name, ActiveInterfaceOption or AllPhysicalOption.
+// An interface that is not reported (yet) shows the active interface but stays selected.
+func selectedNetInterface(system MonitoringSystem) string {
+	iface := system.GetSelection(SelectionNetInterface)
+	if iface == AllPhysicalOption {
+		return iface
+	}
+	if iface == "" || !system.GetSnapshot().Has(collectors.NetInterfaceMetric(iface, collectors.NetRead)) {
+		return ActiveInterfaceOption
+	}
+	return iface
+}
+
+// selectedNetMetric returns the metric name of one network measurement kind for the chosen interface
+func selectedNetMetric(system MonitoringSystem, kind string) string {
+	switch iface := selectedNetInterface(system); iface {
+	case ActiveInterfaceOption:
+		return "net." + kind
+	case AllPhysicalOption:
+		return collectors.NetAllPrefix + kind
+	default:
+		return collectors.NetInterfaceMetric(iface, kind)
+	}
+}
+
+// selectedNetMetrics returns the download and upload metric names for the chosen interface
+func selectedNetMetrics(system MonitoringSystem) (string, string) {
+	return selectedNetMetric(system, collectors.NetRead), selectedNetMetric(system, collectors.NetWrite)
+}
+
+// formatNetRates formats an incoming and outgoing per-second rate pair of the chosen interface
+func formatNetRates(system MonitoringSystem, format, inKind, outKind string) string {
+	snapshot := system.GetSnapshot()
+	return fmt.Sprintf(format,
+		snapshot.Value(selectedNetMetric(system, inKind)),
+		snapshot.Value(selectedNetMetric(system, outKind)))
+}
+
+// GetNetworkSelectors returns the network interface selector
+func GetNetworkSelectors(system MonitoringSystem) []widgets.Selector {
+	return []widgets.Selector{
 		{
-			Label: "Read",
-			GetValue: func() string {
-				data := system.GetDiskReadData()
-				if len(data) > 0 {
-					return fmt.Sprintf("%.2f MB/s", data[len(data)-1])
-				}
-				return "0.00 MB/s"
+			Label: "Interface",
+			GetOptions: func() []string {
+				options := []string{ActiveInterfaceOption, AllPhysicalOption}
+				return append(options, collectors.NetInterfaces(system.GetSnapshot().Names())...)
 			},
-		},
-		{
-			Label: "Write",
-			GetValue: func() string {
-				data := system.GetDiskWriteData()
-				if len(data) > 0 {
-					return fmt.Sprintf("%.2f MB/s", data[len(data)-1])
-				}
-				return "0.00 MB/s"
+			GetSelected: func() string {
+				return selectedNetInterface(system)
 			},
-		},
-		{
-			Label: "Usage",
-			GetValue: func() string {
-				return fmt.Sprintf("%.1f%%", system.GetDiskUsage())
+			OnChanged: func(value string) {
+				system.SetSelection(SelectionNetInterface, value)
 			},
 		},
 	}
 }
 
-// GetRAMInfoProvider returns RAM info functions
-func GetRAMInfoProvider(system MonitoringSystem) []widgets.InfoRow {
-	return []widgets.InfoRow{
+// connectionStateMetrics returns the TCP states drawn as lines next to the connection count
+func connectionStateMetrics(colorScheme ColorScheme) []StackedMetric {
+	return []StackedMetric{
+		{Label: "established", Metric: collectors.TCPStateMetric("ESTABLISHED"), Color: colorScheme.NET},
+		{Label: "time_wait", Metric: collectors.TCPStateMetric("TIME_WAIT"), Color: colorScheme.Warning},
+		{Label: "close_wait", Metric: collectors.TCPStateMetric("CLOSE_WAIT"), Color: colorScheme.Error},
+		{Label: "listen", Metric: collectors.TCPStateMetric("LISTEN"), Color: colorScheme.DISK},
+	}
+}
+
+// listeningSockets returns the listening sockets published by the connections collector
+func listeningSockets(snapshot *Snapshot) []collectors.ListeningSocket {
+	listeners, _ := snapshot.Detail("connections").([]collectors.ListeningSocket)
+	return listeners
+}
+
+// ListeningDataProvider provides the listening socket table
+type ListeningDataProvider struct {
+	System MonitoringSystem
+}
+
+func (l *ListeningDataProvider) GetHeaders() []string {
+	return []string{"Proto", "Address", "Port", "PID", "Process"}
+}
+
+func (l *ListeningDataProvider) GetRows() [][]string {
+	listeners := listeningSockets(l.System.GetSnapshot())
+
+	rows := make([][]string, 0, len(listeners))
+	for _, listener := range listeners {
+		pid, name := "-", "-"
+		if listener.PID > 0 {
+			pid = fmt.Sprintf("%d", listener.PID)
+		}
+		if listener.Process != "" {
+			name = listener.Process
+		}
+		rows = append(rows, []string{
+			listener.Protocol,
+			listener.Address,
+			fmt.Sprintf("%d", listener.Port),
+			pid,
+			name,
+		})
+	}
+	return rows
+}
+
+func (l *ListeningDataProvider) GetTitle() string {
+	return "Listening"
+}
+
+// sampledProcesses returns the processes published by the process collector
+func sampledProcesses(snapshot *Snapshot) []collectors.ProcessInfo {
+	procs, _ := snapshot.Detail("processes").([]collectors.ProcessInfo)
+	return procs
+}
+
+// selectedProcessSort returns the chosen process table ordering, CPU by default
+func selectedProcessSort(system MonitoringSystem) collectors.ProcessSortKey {
+	selected := collectors.ProcessSortKey(system.GetSelection(SelectionProcessSort))
+	for _, key := range collectors.ProcessSortKeys {
+		if key == selected {
+			return key
+		}
+	}
+	return collectors.SortByCPU
+}
+
+// ProcessesDataProvider provides the process table, filtered and sorted as chosen in its controls
+type ProcessesDataProvider struct {
+	System MonitoringSystem
+}
+
+func (p *ProcessesDataProvider) GetHeaders() []string {
+	return []string{"PID", "Name", "User", "CPU%", "RSS", "Threads", "Read/s", "Write/s"}
+}
+
+// shownProcesses returns the processes matching the filter in table order and how many matched
+func (p *ProcessesDataProvider) shownProcesses() ([]collectors.ProcessInfo, int) {
+	procs := collectors.FilterProcesses(sampledProcesses(p.System.GetSnapshot()), p.System.GetSelection(SelectionProcessFilter))
+	return collectors.TopProcesses(procs, selectedProcessSort(p.System), ProcessTableRows), len(procs)
+}
+
+func (p *ProcessesDataProvider) GetRows() [][]string {
+	procs, _ := p.shownProcesses()
+
+	rows := make([][]string, 0, len(procs))
+	for _, proc := range procs {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", proc.PID),
+			proc.Name,
+			proc.User,
+			fmt.Sprintf("%.1f", proc.CPUPercent),
+			formatBytes(proc.RSS),
+			fmt.Sprintf("%d", proc.Threads),
+			formatByteRate(proc.ReadRate),
+			formatByteRate(proc.WriteRate),
+		})
+	}
+	return rows
+}
+
+func (p *ProcessesDataProvider) GetTitle() string {
+	shown, matched := p.shownProcesses()
+	return fmt.Sprintf("Processes %d of %d", len(shown), matched)
+}
+
+// GetProcessSelectors returns the process table ordering selector
+func GetProcessSelectors(system MonitoringSystem) []widgets.Selector {
+	return []widgets.Selector{
 		{
-			Label: "Total",
-			GetValue: func() string {
-				v := system.GetVirtualMemory()
-				return fmt.Sprintf("%.1f GB", float64(v.Total)/(1024*1024*1024))
+			Label: "Sort by",
+			GetOptions: func() []string {
+				options := make([]string, len(collectors.ProcessSortKeys))
+				for i, key := range collectors.ProcessSortKeys {
+					options[i] = string(key)
+				}
+				return options
 			},
-		},
-		{
-			Label: "Used",
-			GetValue: func() string {
-				v := system.GetVirtualMemory()
-				return fmt.Sprintf("%.1f GB (%.1f%%)",
-					float64(v.Used)/(1024*1024*1024), system.GetRAMUsage())
+			GetSelected: func() string {
+				return string(selectedProcessSort(system))
 			},
-		},
-		{
-			Label: "Free",
-			GetValue: func() string {
-				v := system.GetVirtualMemory()
-				return fmt.Sprintf("%.1f GB", float64(v.Free)/(1024*1024*1024))
+			OnChanged: func(value string) {
+				system.SetSelection(SelectionProcessSort, value)
 			},
 		},
 	}
 }
 
-// GetCPUInfoProvider returns CPU info functions
-func GetCPUInfoProvider(system MonitoringSystem) []widgets.InfoRow {
-	return []widgets.InfoRow{
+// GetProcessInputs returns the process table filter input
+func GetProcessInputs(system MonitoringSystem) []widgets.TextInput {
+	return []widgets.TextInput{
 		{
-			Label: "Model",
-			GetValue: func() string {
-				return system.GetCPUModelName()
+			Label:       "Filter",
+			Placeholder: "name, user or PID",
+			GetText: func() string {
+				return system.GetSelection(SelectionProcessFilter)
 			},
-		},
-		{
-			Label: "Usage",
-			GetValue: func() string {
-				return fmt.Sprintf("%.2f%%", system.GetCPUUsage())
+			OnChanged: func(value string) {
+				system.SetSelection(SelectionProcessFilter, value)
 			},
 		},
-		{
-			Label: "Cores",
+	}
+}
+
+// topProcessRows returns one info row per top consumer in the given order, formatted by value
+func topProcessRows(system MonitoringSystem, key collectors.ProcessSortKey, value func(collectors.ProcessInfo) string) []widgets.InfoRow {
+	rows := make([]widgets.InfoRow, TopProcessCount)
+	for i := range rows {
+		rank := i
+		rows[i] = widgets.InfoRow{
+			Label: fmt.Sprintf("Top %d", rank+1),
 			GetValue: func() string {
-				return fmt.Sprintf("%d physical / %d logical",
-					system.GetPhysicalCPUCount(), system.GetLogicalCPUCount())
+				top := collectors.TopProcesses(sampledProcesses(system.GetSnapshot()), key, TopProcessCount)
+				if rank >= len(top) {
+					return "-"
+				}
+				return fmt.Sprintf("%s (%d) %s", top[rank].Name, top[rank].PID, value(top[rank]))
 			},
-		},
+		}
 	}
+	return rows
 }
 
-// GetNetworkInfoProvider returns network info functions
-func GetNetworkInfoProvider(system MonitoringSystem) []widgets.InfoRow {
-	return []widgets.InfoRow{
+// formatByteRate formats a rate in bytes per second with a binary unit suffix
+func formatByteRate(bytesPerSecond float64) string {
+	return formatBytes(uint64(bytesPerSecond)) + "/s"
+}
+
+// ProcessTreeDataProvider provides the parent/child hierarchy of the sampled processes
+type ProcessTreeDataProvider struct {
+	System MonitoringSystem
+}
+
+func (p *ProcessTreeDataProvider) GetHeaders() []string {
+	return []string{"PID", "Process", "User", "CPU%", "RSS"}
+}
+
+// shownRows returns the tree rows matching the filter and how many matched
+func (p *ProcessTreeDataProvider) shownRows() ([]collectors.ProcessTreeRow, int) {
+	rows := collectors.ProcessTree(sampledProcesses(p.System.GetSnapshot()), p.System.GetSelection(SelectionTreeFilter))
+	if len(rows) > ProcessTreeRows {
+		return rows[:ProcessTreeRows], len(rows)
+	}
+	return rows, len(rows)
+}
+
+func (p *ProcessTreeDataProvider) GetRows() [][]string {
+	tree, _ := p.shownRows()
+
+	rows := make([][]string, 0, len(tree))
+	for _, row := range tree {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", row.PID),
+			treeIndent(row.Depth) + row.Name,
+			row.User,
+			fmt.Sprintf("%.1f", row.CPUPercent),
+			formatBytes(row.RSS),
+		})
+	}
+	return rows
+}
+
+func (p *ProcessTreeDataProvider) GetTitle() string {
+	shown, total := p.shownRows()
+	return fmt.Sprintf("Process tree %d of %d", len(shown), total)
+}
+
+// treeIndent returns the prefix drawing a process at the given depth below its parent
+func treeIndent(depth int) string {
+	if depth == 0 {
+		return ""
+	}
+	return strings.Repeat("  ", depth-1) + "└─ "
+}
+
+// GetProcessTreeInputs returns the process tree filter input
+func GetProcessTreeInputs(system MonitoringSystem) []widgets.TextInput {
+	return []widgets.TextInput{
 		{
-			Label: "Interface",
-			GetValue: func() string {
-				return system.GetActiveNetInterfaceName()
+			Label:       "Filter",
+			Placeholder: "name, user or PID",
+			GetText: func() string {
+				return system.GetSelection(SelectionTreeFilter)
+			},
+			OnChanged: func(value string) {
+				system.SetSelection(SelectionTreeFilter, value)
 			},
 		},
-		{
-			Label: "Download",
-			GetValue: func() string {
-				data := system.GetNetworkReadData()
-				if len(data) > 0 {
-					return fmt.Sprintf("%.2f MB/s", data[len(data)-1])
+	}
+}
+
+// AuditLogDataProvider provides the latest actions taken on processes
+type AuditLogDataProvider struct {
+	Log *procctl.AuditLog
+}
+
+func (a *AuditLogDataProvider) GetHeaders() []string {
+	return []string{"Time", "Action", "PID", "Process", "Result"}
+}
+
+func (a *AuditLogDataProvider) GetRows() [][]string {
+	entries := a.Log.Entries()
+	if len(entries) > AuditLogRows {
+		entries = entries[:AuditLogRows]
+	}
+
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		result := "OK"
+		if entry.Error != "" {
+			result = entry.Error
+		}
+		rows = append(rows, []string{
+			entry.Time.Format("15:04:05"),
+			entry.Action,
+			fmt.Sprintf("%d", entry.PID),
+			entry.Process,
+			result,
+		})
+	}
+	return rows
+}
+
+func (a *AuditLogDataProvider) GetTitle() string {
+	return "Process actions"
+}
+
+// sensorReadings returns the sensor readings published by the sensors collector
+func sensorReadings(snapshot *Snapshot) []collectors.SensorReading {
+	readings, _ := snapshot.Detail("sensors").([]collectors.SensorReading)
+	return readings
+}
+
+// SensorsDataProvider provides the CPU temperature, flagged when any sensor crosses a threshold
+type SensorsDataProvider struct {
+	System MonitoringSystem
+}
+
+func (s *SensorsDataProvider) GetData() []float64 {
+	return s.System.GetMetricData(collectors.MetricSensorCPU)
+}
+
+func (s *SensorsDataProvider) GetMaxValue() float64 {
+	return scaledMaxValue(100.0, s.GetData())
+}
+
+func (s *SensorsDataProvider) GetCurrentValue() float64 {
+	return s.System.GetMetricValue(collectors.MetricSensorCPU)
+}
+
+func (s *SensorsDataProvider) GetTitle() string {
+	return "Temp"
+}
+
+func (s *SensorsDataProvider) GetColor() color.Color {
+	return s.System.GetColorScheme().CPU
+}
+
+// GetHighlight flags the widget in the error color when a sensor is critical and in the warning color when one runs high
+func (s *SensorsDataProvider) GetHighlight() color.Color {
+	var high bool
+	for _, reading := range sensorReadings(s.System.GetSnapshot()) {
+		if reading.OverCritical() {
+			return s.System.GetColorScheme().Error
+		}
+		high = high || reading.OverHigh()
+	}
+	if high {
+		return s.System.GetColorScheme().Warning
+	}
+	return nil
+}
+
+// SensorSeriesDataProvider provides the history of every sensor of one kind, one line per sensor
+type SensorSeriesDataProvider struct {
+	System   MonitoringSystem
+	Kind     string // collectors.SensorTemperature or collectors.SensorFan
+	Title    string
+	MaxValue float64 // Lowest axis maximum, the axis grows to fit the history
+}
+
+func (s *SensorSeriesDataProvider) GetSeries() []widgets.Series {
+	snapshot := s.System.GetSnapshot()
+	colorScheme := s.System.GetColorScheme()
+	colors := []color.Color{colorScheme.CPU, colorScheme.RAM, colorScheme.DISK, colorScheme.NET, colorScheme.Success, colorScheme.Warning}
+
+	var series []widgets.Series
+	for _, reading := range sensorReadings(snapshot) {
+		if reading.Kind != s.Kind {
+			continue
+		}
+		series = append(series, widgets.Series{
+			Label: reading.Key,
+			Data:  snapshot.History(collectors.SensorMetric(reading.Kind, reading.Key)),
+			Color: colors[len(series)%len(colors)],
+		})
+	}
+	return series
+}
+
+func (s *SensorSeriesDataProvider) GetMaxValue() float64 {
+	var histories [][]float64
+	for _, series := range s.GetSeries() {
+		histories = append(histories, series.Data)
+	}
+	return scaledMaxValue(s.MaxValue, histories...)
+}
+
+func (s *SensorSeriesDataProvider) GetTitle() string {
+	return s.Title
+}
+
+// SensorsTableDataProvider provides the table of every sensor with its thresholds
+type SensorsTableDataProvider struct {
+	System MonitoringSystem
+}
+
+func (s *SensorsTableDataProvider) GetHeaders() []string {
+	return []string{"Sensor", "Value", "High", "Crit"}
+}
+
+func (s *SensorsTableDataProvider) GetRows() [][]string {
+	readings := sensorReadings(s.System.GetSnapshot())
+
+	rows := make([][]string, 0, len(readings))
+	for _, reading := range readings {
+		rows = append(rows, []string{
+			reading.Key,
+			formatSensorValue(reading.Kind, reading.Value),
+			formatSensorValue(reading.Kind, reading.High),
+			formatSensorValue(reading.Kind, reading.Critical),
+		})
+	}
+	return rows
+}
+
+func (s *SensorsTableDataProvider) GetTitle() string {
+	return "Sensors"
+}
+
+// formatSensorValue formats a sensor value or threshold in its unit, "-" for a missing threshold
+func formatSensorValue(kind string, value float64) string {
+	switch {
+	case value == 0:
+		return "-"
+	case kind == collectors.SensorFan:
+		return fmt.Sprintf("%.0f RPM", value)
+	default:
+		return fmt.Sprintf("%.1f °C", value)
+	}
+}
+
+// batteries returns the batteries published by the battery collector
+func batteries(snapshot *Snapshot) []collectors.BatteryInfo {
+	batteries, _ := snapshot.Detail("battery").([]collectors.BatteryInfo)
+	return batteries
+}
+
+// BatteryDataProvider provides the remaining battery charge, flagged when running low on battery power
+type BatteryDataProvider struct {
+	System MonitoringSystem
+}
+
+func (b *BatteryDataProvider) GetData() []float64 {
+	return b.System.GetMetricData(collectors.MetricBatteryPercent)
+}
+
+func (b *BatteryDataProvider) GetMaxValue() float64 {
+	return 100.0
+}
+
+func (b *BatteryDataProvider) GetCurrentValue() float64 {
+	return b.System.GetMetricValue(collectors.MetricBatteryPercent)
+}
+
+func (b *BatteryDataProvider) GetTitle() string {
+	return "Battery"
+}
+
+func (b *BatteryDataProvider) GetColor() color.Color {
+	return b.System.GetColorScheme().Success
+}
+
+// GetHighlight flags the widget in the warning color below BatteryLowPercent and in the error color
+// below BatteryCriticalPercent, only while on battery power
+func (b *BatteryDataProvider) GetHighlight() color.Color {
+	snapshot := b.System.GetSnapshot()
+	if snapshot.Value(collectors.MetricBatteryAC) > 0 {
+		return nil
+	}
+
+	switch percent := snapshot.Value(collectors.MetricBatteryPercent); {
+	case percent < BatteryCriticalPercent:
+		return b.System.GetColorScheme().Error
+	case percent < BatteryLowPercent:
+		return b.System.GetColorScheme().Warning
+	default:
+		return nil
+	}
+}
+
+// formatBatteryPower formats the power flowing into and out of the batteries, showing only the directions in use
+func formatBatteryPower(charge, discharge float64) string {
+	switch {
+	case charge > 0 && discharge > 0:
+		return fmt.Sprintf("%.1f W in, %.1f W out", charge, discharge)
+	case charge > 0:
+		return fmt.Sprintf("%.1f W in", charge)
+	case discharge > 0:
+		return fmt.Sprintf("%.1f W out", discharge)
+	}
+	return "0.0 W"
+}
+
+// formatMinutes formats a duration in minutes as hours and minutes, "-" when unknown
+func formatMinutes(minutes float64) string {
+	if minutes <= 0 {
+		return "-"
+	}
+	total := int(math.Round(minutes))
+	return fmt.Sprintf("%dh %02dm", total/60, total%60)
+}
+
+// PressureDataProvider provides the overall contention, the highest share of time some tasks stalled on a resource
+type PressureDataProvider struct {
+	System MonitoringSystem
+}
+
+func (p *PressureDataProvider) GetData() []float64 {
+	return p.System.GetMetricData(collectors.MetricPressure)
+}
+
+func (p *PressureDataProvider) GetMaxValue() float64 {
+	return scaledMaxValue(10.0, p.GetData())
+}
+
+func (p *PressureDataProvider) GetCurrentValue() float64 {
+	return p.System.GetMetricValue(collectors.MetricPressure)
+}
+
+func (p *PressureDataProvider) GetTitle() string {
+	return "Pressure"
+}
+
+func (p *PressureDataProvider) GetColor() color.Color {
+	return p.System.GetColorScheme().Warning
+}
+
+// GetHighlight flags the widget in the error color when all tasks stall on a resource for PressureHighPercent
+// of the time and in the warning color when some tasks do
+func (p *PressureDataProvider) GetHighlight() color.Color {
+	snapshot := p.System.GetSnapshot()
+	for _, resource := range collectors.PressureResources {
+		if snapshot.Value(collectors.PressureMetric(resource, collectors.PressureFull, collectors.PressureAvg10)) >= PressureHighPercent {
+			return p.System.GetColorScheme().Error
+		}
+	}
+	if snapshot.Value(collectors.MetricPressure) >= PressureHighPercent {
+		return p.System.GetColorScheme().Warning
+	}
+	return nil
+}
+
+// pressureMetrics returns the 10 second averages of one pressure line overlaid per resource
+func pressureMetrics(colorScheme ColorScheme, line string) []StackedMetric {
+	return []StackedMetric{
+		{Label: "cpu", Metric: collectors.PressureMetric("cpu", line, collectors.PressureAvg10), Color: colorScheme.CPU},
+		{Label: "memory", Metric: collectors.PressureMetric("memory", line, collectors.PressureAvg10), Color: colorScheme.RAM},
+		{Label: "io", Metric: collectors.PressureMetric("io", line, collectors.PressureAvg10), Color: colorScheme.DISK},
+	}
+}
+
+// MetricDataProvider provides data of any collector metric for graphing
+type MetricDataProvider struct {
+	System    MonitoringSystem
+	Metric    string
+	Title     string
+	MaxValue  float64
+	AutoScale bool // Grow the axis beyond MaxValue to fit the history
+	Color     color.Color
+}
+
+func (m *MetricDataProvider) GetData() []float64 {
+	return m.System.GetMetricData(m.Metric)
+}
+
+func (m *MetricDataProvider) GetMaxValue() float64 {
+	if m.AutoScale {
+		return scaledMaxValue(m.MaxValue, m.GetData())
+	}
+	return m.MaxValue
+}
+
+func (m *MetricDataProvider) GetCurrentValue() float64 {
+	return m.System.GetMetricValue(m.Metric)
+}
+
+func (m *MetricDataProvider) GetTitle() string {
+	return m.Title
+}
+
+func (m *MetricDataProvider) GetColor() color.Color {
+	return m.Color
+}
+
+// DualMetricDataProvider provides data of two collector metrics for graphing
+type DualMetricDataProvider struct {
+	System      MonitoringSystem
+	ReadMetric  string
+	WriteMetric string
+	Title       string
+	MaxValue    float64
+	AutoScale   bool // Grow the axis beyond MaxValue to fit the history
+	Color       color.Color
+}
+
+func (m *DualMetricDataProvider) GetReadData() []float64 {
+	return m.System.GetMetricData(m.ReadMetric)
+}
+
+func (m *DualMetricDataProvider) GetWriteData() []float64 {
+	return m.System.GetMetricData(m.WriteMetric)
+}
+
+func (m *DualMetricDataProvider) GetMaxValue() float64 {
+	if m.AutoScale {
+		return scaledMaxValue(m.MaxValue, m.GetReadData(), m.GetWriteData())
+	}
+	return m.MaxValue
+}
+
+func (m *DualMetricDataProvider) GetCurrentReadValue() float64 {
+	return m.System.GetMetricValue(m.ReadMetric)
+}
+
+func (m *DualMetricDataProvider) GetCurrentWriteValue() float64 {
+	return m.System.GetMetricValue(m.WriteMetric)
+}
+
+func (m *DualMetricDataProvider) GetTitle() string {
+	return m.Title
+}
+
+func (m *DualMetricDataProvider) GetColor() color.Color {
+	return m.Color
+}
+
+// scaledMaxValue returns minimum, or the highest value in the histories rounded up
+// to 1, 2 or 5 times a power of ten when that is larger, so the axis stays readable
+func scaledMaxValue(minimum float64, histories ...[]float64) float64 {
+	peak := minimum
+	for _, history := range histories {
+		for _, value := range history {
+			peak = math.Max(peak, value)
+		}
+	}
+	if peak <= minimum {
+		return minimum
+	}
+
+	magnitude := math.Pow(10, math.Floor(math.Log10(peak)))
+	for _, step := range []float64{1, 2, 5} {
+		if peak <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// StackedMetric describes one layer of a StackedMetricsDataProvider
+type StackedMetric struct {
+	Label  string
+	Metric string
+	Color  color.Color
+}
+
+// StackedMetricsDataProvider provides several collector metrics for a stacked or line graph
+type StackedMetricsDataProvider struct {
+	System   MonitoringSystem
+	Title    string
+	MaxValue float64
+	Metrics  []StackedMetric
+}
+
+func (m *StackedMetricsDataProvider) GetSeries() []widgets.Series {
+	// Read every layer from the same snapshot so the stack stays consistent
+	snapshot := m.System.GetSnapshot()
+
+	series := make([]widgets.Series, 0, len(m.Metrics))
+	for _, metric := range m.Metrics {
+		series = append(series, widgets.Series{
+			Label: metric.Label,
+			Data:  snapshot.History(metric.Metric),
+			Color: metric.Color,
+		})
+	}
+	return series
+}
+
+func (m *StackedMetricsDataProvider) GetMaxValue() float64 {
+	return m.MaxValue
+}
+
+func (m *StackedMetricsDataProvider) GetTitle() string {
+	return m.Title
+}
+
+// cpuTimeMetrics returns the CPU time breakdown layers, busiest categories first
+func cpuTimeMetrics(colorScheme ColorScheme) []StackedMetric {
+	return []StackedMetric{
+		{Label: "user", Metric: collectors.MetricCPUUser, Color: colorScheme.CPU},
+		{Label: "sys", Metric: collectors.MetricCPUSystem, Color: colorScheme.RAM},
+		{Label: "nice", Metric: collectors.MetricCPUNice, Color: colorScheme.Success},
+		{Label: "iowait", Metric: collectors.MetricCPUIowait, Color: colorScheme.Warning},
+		{Label: "irq", Metric: collectors.MetricCPUIrq, Color: colorScheme.DISK},
+		{Label: "softirq", Metric: collectors.MetricCPUSoftirq, Color: colorScheme.NET},
+		{Label: "steal", Metric: collectors.MetricCPUSteal, Color: colorScheme.Error},
+	}
+}
+
+// memoryCompositionMetrics returns the memory composition layers, used memory at the bottom
+func memoryCompositionMetrics(colorScheme ColorScheme) []StackedMetric {
+	return []StackedMetric{
+		{Label: "used", Metric: collectors.MetricRAMUsed, Color: colorScheme.RAM},
+		{Label: "buffers", Metric: collectors.MetricRAMBuffers, Color: colorScheme.DISK},
+		{Label: "cached", Metric: collectors.MetricRAMCached, Color: colorScheme.NET},
+	}
+}
+
+// formatBytesOfTotal formats a percentage-of-total metric as an absolute size in GB
+func formatBytesOfTotal(snapshot *Snapshot, metric string) string {
+	bytes := snapshot.Value(metric) / 100 * snapshot.Value(collectors.MetricRAMTotal)
+	return fmt.Sprintf("%.2f GB", bytes/(1024*1024*1024))
+}
+
+// formatPercentPair formats two metrics of the same snapshot as "a% / b%"
+func formatPercentPair(system MonitoringSystem, first, second string) string {
+	snapshot := system.GetSnapshot()
+	return fmt.Sprintf("%.1f%% / %.1f%%", snapshot.Value(first), snapshot.Value(second))
+}
+
+// GetDiskInfoProvider returns disk info functions
+func GetDiskInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	rows := []widgets.InfoRow{
+		{
+			Label: "Read",
+			GetValue: func() string {
+				readMetric, _ := selectedDiskMetrics(system)
+				return fmt.Sprintf("%.2f MB/s", system.GetMetricValue(readMetric))
+			},
+		},
+		{
+			Label: "Write",
+			GetValue: func() string {
+				_, writeMetric := selectedDiskMetrics(system)
+				return fmt.Sprintf("%.2f MB/s", system.GetMetricValue(writeMetric))
+			},
+		},
+		{
+			Label: "IOPS",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				return fmt.Sprintf("R %.0f / W %.0f",
+					snapshot.Value(selectedDiskMetric(system, collectors.DiskReadIOPS)),
+					snapshot.Value(selectedDiskMetric(system, collectors.DiskWriteIOPS)))
+			},
+		},
+		{
+			Label: "Await",
+			GetValue: func() string {
+				return fmt.Sprintf("%.1f ms", system.GetMetricValue(selectedDiskMetric(system, collectors.DiskAwait)))
+			},
+		},
+		{
+			Label: "Queue",
+			GetValue: func() string {
+				return fmt.Sprintf("%.2f", system.GetMetricValue(selectedDiskMetric(system, collectors.DiskQueue)))
+			},
+		},
+		{
+			Label: "Util",
+			GetValue: func() string {
+				return fmt.Sprintf("%.1f%%", system.GetMetricValue(selectedDiskMetric(system, collectors.DiskUtil)))
+			},
+		},
+		{
+			Label: "Usage",
+			GetValue: func() string {
+				mount, usage := selectedMountUsage(system)
+				return fmt.Sprintf("%.1f%% of %s", usage, mount)
+			},
+		},
+		{
+			Label: "Cgroup Read / Write",
+			GetValue: func() string {
+				cgroup, ok := selectedCgroup(system)
+				if !ok {
+					return "host"
 				}
-				return "0.00 MB/s"
+				return formatByteRate(cgroup.ReadRate) + " / " + formatByteRate(cgroup.WriteRate)
 			},
 		},
+	}
+
+	// The processes reading and writing the most
+	return append(rows, topProcessRows(system, collectors.SortByIO, func(proc collectors.ProcessInfo) string {
+		return formatByteRate(proc.IORate())
+	})...)
+}
+
+// GetRAMInfoProvider returns RAM info functions
+func GetRAMInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	rows := []widgets.InfoRow{
 		{
-			Label: "Upload",
+			Label: "Total",
+			GetValue: func() string {
+				return fmt.Sprintf("%.1f GB", float64(system.GetHostInfo().TotalRAM)/(1024*1024*1024))
+			},
+		},
+		{
+			Label: "Used",
+			GetValue: func() string {
+				return fmt.Sprintf("%s (%.1f%%)",
+					formatBytesOfTotal(system.GetSnapshot(), collectors.MetricRAMUsed), system.GetRAMUsage())
+			},
+		},
+		{
+			Label: "Free",
+			GetValue: func() string {
+				return formatBytesOfTotal(system.GetSnapshot(), collectors.MetricRAMFree)
+			},
+		},
+		{
+			Label: "Available",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				return fmt.Sprintf("%s (%.1f%%)",
+					formatBytesOfTotal(snapshot, collectors.MetricRAMAvailable),
+					snapshot.Value(collectors.MetricRAMAvailable))
+			},
+		},
+		{
+			Label: "Cached / Buffers",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				return fmt.Sprintf("%s / %s",
+					formatBytesOfTotal(snapshot, collectors.MetricRAMCached),
+					formatBytesOfTotal(snapshot, collectors.MetricRAMBuffers))
+			},
+		},
+		{
+			Label: "Shared / Dirty",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				return fmt.Sprintf("%s / %s",
+					formatBytesOfTotal(snapshot, collectors.MetricRAMShared),
+					formatBytesOfTotal(snapshot, collectors.MetricRAMDirty))
+			},
+		},
+		{
+			Label: "Slab",
+			GetValue: func() string {
+				return formatBytesOfTotal(system.GetSnapshot(), collectors.MetricRAMSlab)
+			},
+		},
+		{
+			Label: "Swap",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				if snapshot.Value(collectors.MetricSwapTotal) == 0 {
+					return "none"
+				}
+				return fmt.Sprintf("%.2f / %.2f GB (%.1f%%)",
+					snapshot.Value(collectors.MetricSwapUsed)/(1024*1024*1024),
+					snapshot.Value(collectors.MetricSwapTotal)/(1024*1024*1024),
+					snapshot.Value(collectors.MetricSwapUsage))
+			},
+		},
+		{
+			Label: "Swap In / Out",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				return fmt.Sprintf("%.2f / %.2f MB/s",
+					snapshot.Value(collectors.MetricSwapIn),
+					snapshot.Value(collectors.MetricSwapOut))
+			},
+		},
+		{
+			Label: "Cgroup",
+			GetValue: func() string {
+				cgroup, ok := selectedCgroup(system)
+				if !ok {
+					return "host"
+				}
+				if cgroup.MemoryMax == 0 {
+					return formatBytes(cgroup.MemoryBytes) + ", no limit"
+				}
+				return fmt.Sprintf("%s of %s", formatBytes(cgroup.MemoryBytes), formatBytes(cgroup.MemoryMax))
+			},
+		},
+	}
+
+	// The processes with the largest resident memory
+	return append(rows, topProcessRows(system, collectors.SortByMemory, func(proc collectors.ProcessInfo) string {
+		return formatBytes(proc.RSS)
+	})...)
+}
+
+// GetCPUInfoProvider returns CPU info functions
+func GetCPUInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	rows := []widgets.InfoRow{
+		{
+			Label: "Model",
+			GetValue: func() string {
+				return system.GetHostInfo().CPUModel
+			},
+		},
+		{
+			Label: "Usage",
+			GetValue: func() string {
+				return fmt.Sprintf("%.2f%%", system.GetCPUUsage())
+			},
+		},
+		{
+			Label: "Cores",
+			GetValue: func() string {
+				info := system.GetHostInfo()
+				return fmt.Sprintf("%d physical / %d logical", info.PhysicalCores, info.LogicalCores)
+			},
+		},
+		{
+			Label: "User / Sys",
+			GetValue: func() string {
+				return formatPercentPair(system, collectors.MetricCPUUser, collectors.MetricCPUSystem)
+			},
+		},
+		{
+			Label: "IOWait / Steal",
 			GetValue: func() string {
-				data := system.GetNetworkWriteData()
-				if len(data) > 0 {
-					return fmt.Sprintf("%.2f MB/s", data[len(data)-1])
+				return formatPercentPair(system, collectors.MetricCPUIowait, collectors.MetricCPUSteal)
+			},
+		},
+		{
+			Label: "IRQ / SoftIRQ",
+			GetValue: func() string {
+				return formatPercentPair(system, collectors.MetricCPUIrq, collectors.MetricCPUSoftirq)
+			},
+		},
+		{
+			Label: "Nice / Guest",
+			GetValue: func() string {
+				return formatPercentPair(system, collectors.MetricCPUNice, collectors.MetricCPUGuest)
+			},
+		},
+		{
+			Label: "Cgroup",
+			GetValue: func() string {
+				cgroup, ok := selectedCgroup(system)
+				if !ok {
+					return "host"
 				}
-				return "0.00 MB/s"
+				if cgroup.CPULimit <= 0 {
+					return fmt.Sprintf("%.2f CPUs, no limit", cgroup.CPUCores)
+				}
+				return fmt.Sprintf("%.2f of %.2f CPUs", cgroup.CPUCores, cgroup.CPULimit)
+			},
+		},
+		{
+			Label: "Cgroup Tasks",
+			GetValue: func() string {
+				cgroup, ok := selectedCgroup(system)
+				if !ok {
+					return "host"
+				}
+				if cgroup.PidsMax == 0 {
+					return fmt.Sprintf("%d, no limit", cgroup.Pids)
+				}
+				return fmt.Sprintf("%d of %d", cgroup.Pids, cgroup.PidsMax)
+			},
+		},
+	}
+
+	// The processes using the most CPU time
+	return append(rows, topProcessRows(system, collectors.SortByCPU, func(proc collectors.ProcessInfo) string {
+		return fmt.Sprintf("%.1f%%", proc.CPUPercent)
+	})...)
+}
+
+// GetCPUCoresInfoProvider returns per-core CPU info functions
+func GetCPUCoresInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	// extremeCore finds the core with the highest (or lowest) current usage
+	extremeCore := func(highest bool) string {
+		snapshot := system.GetSnapshot()
+		found := -1
+		var value float64
+		for core := 0; snapshot.Has(collectors.CoreMetric(core)); core++ {
+			v := snapshot.Value(collectors.CoreMetric(core))
+			if found < 0 || (highest && v > value) || (!highest && v < value) {
+				found, value = core, v
+			}
+		}
+		if found < 0 {
+			return "n/a"
+		}
+		return fmt.Sprintf("core %d (%.1f%%)", found, value)
+	}
+
+	return []widgets.InfoRow{
+		{
+			Label: "Busiest",
+			GetValue: func() string {
+				return extremeCore(true)
+			},
+		},
+		{
+			Label: "Idlest",
+			GetValue: func() string {
+				return extremeCore(false)
+			},
+		},
+		{
+			Label: "Tracked",
+			GetValue: func() string {
+				return fmt.Sprintf("%d logical", len(cpuCoreRows(system.GetSnapshot())))
+			},
+		},
+	}
+}
+
+// GetLoadInfoProvider returns load average info functions
+func GetLoadInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	return []widgets.InfoRow{
+		{
+			Label: "Load 1/5/15",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				return fmt.Sprintf("%.2f / %.2f / %.2f",
+					snapshot.Value(collectors.MetricLoad1),
+					snapshot.Value(collectors.MetricLoad5),
+					snapshot.Value(collectors.MetricLoad15))
+			},
+		},
+		{
+			Label: "Per CPU",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				cpus := logicalCPUs(system)
+				return fmt.Sprintf("%.2f / %.2f / %.2f",
+					snapshot.Value(collectors.MetricLoad1)/cpus,
+					snapshot.Value(collectors.MetricLoad5)/cpus,
+					snapshot.Value(collectors.MetricLoad15)/cpus)
+			},
+		},
+		{
+			Label: "Running / Blocked",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				return fmt.Sprintf("%.0f / %.0f",
+					snapshot.Value(collectors.MetricProcsRunning),
+					snapshot.Value(collectors.MetricProcsBlocked))
+			},
+		},
+	}
+}
+
+// GetConnectionsInfoProvider returns socket state info functions
+func GetConnectionsInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	stateRow := func(label, state string) widgets.InfoRow {
+		return widgets.InfoRow{
+			Label: label,
+			GetValue: func() string {
+				return fmt.Sprintf("%.0f", system.GetMetricValue(collectors.TCPStateMetric(state)))
+			},
+		}
+	}
+
+	return []widgets.InfoRow{
+		stateRow("Established", "ESTABLISHED"),
+		stateRow("Time Wait", "TIME_WAIT"),
+		stateRow("Close Wait", "CLOSE_WAIT"),
+		{
+			Label: "Listening",
+			GetValue: func() string {
+				return fmt.Sprintf("%.0f", system.GetMetricValue(collectors.MetricConnListening))
+			},
+		},
+		{
+			Label: "TCP / UDP",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				return fmt.Sprintf("%.0f / %.0f",
+					snapshot.Value(collectors.MetricConnTCP),
+					snapshot.Value(collectors.MetricConnUDP))
+			},
+		},
+	}
+}
+
+// GetWatchInfoProvider returns the summed usage rows of a watched process group
+func GetWatchInfoProvider(system MonitoringSystem, group string) []widgets.InfoRow {
+	metricRow := func(label, kind, format string) widgets.InfoRow {
+		return widgets.InfoRow{
+			Label: label,
+			GetValue: func() string {
+				return fmt.Sprintf(format, system.GetMetricValue(collectors.WatchMetric(group, kind)))
+			},
+		}
+	}
+
+	return []widgets.InfoRow{
+		metricRow("Processes", collectors.WatchCount, "%.0f"),
+		metricRow("CPU", collectors.WatchCPU, "%.1f%%"),
+		metricRow("RSS", collectors.WatchRSS, "%.1f MB"),
+		metricRow("Open Files", collectors.WatchFDs, "%.0f"),
+		metricRow("Threads", collectors.WatchThreads, "%.0f"),
+		{
+			Label: "I/O",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				return fmt.Sprintf("R:%.2f W:%.2f MB/s",
+					snapshot.Value(collectors.WatchMetric(group, collectors.WatchRead)),
+					snapshot.Value(collectors.WatchMetric(group, collectors.WatchWrite)))
+			},
+		},
+	}
+}
+
+// watchCountMetrics returns the descriptor and thread count lines of a watched process group
+func watchCountMetrics(colorScheme ColorScheme, group string) []StackedMetric {
+	return []StackedMetric{
+		{Label: "fds", Metric: collectors.WatchMetric(group, collectors.WatchFDs), Color: colorScheme.NET},
+		{Label: "threads", Metric: collectors.WatchMetric(group, collectors.WatchThreads), Color: colorScheme.RAM},
+	}
+}
+
+// GetSensorsInfoProvider returns temperature summary info functions
+func GetSensorsInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	return []widgets.InfoRow{
+		{
+			Label: "CPU",
+			GetValue: func() string {
+				return fmt.Sprintf("%.1f °C", system.GetMetricValue(collectors.MetricSensorCPU))
+			},
+		},
+		{
+			Label: "Hottest",
+			GetValue: func() string {
+				var hottest collectors.SensorReading
+				for _, reading := range sensorReadings(system.GetSnapshot()) {
+					if reading.Kind == collectors.SensorTemperature && reading.Value > hottest.Value {
+						hottest = reading
+					}
+				}
+				if hottest.Key == "" {
+					return "no sensors"
+				}
+				return fmt.Sprintf("%.1f °C (%s)", hottest.Value, hottest.Key)
+			},
+		},
+		{
+			Label: "Over High",
+			GetValue: func() string {
+				return fmt.Sprintf("%.0f", system.GetMetricValue(collectors.MetricSensorOverHigh))
+			},
+		},
+		{
+			Label: "Throttling",
+			GetValue: func() string {
+				return fmt.Sprintf("%.1f /s", system.GetMetricValue(collectors.MetricSensorThrottle))
+			},
+		},
+	}
+}
+
+// GetBatteryInfoProvider returns battery info functions
+func GetBatteryInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	return []widgets.InfoRow{
+		{
+			Label: "Status",
+			GetValue: func() string {
+				statuses := make([]string, 0, 2)
+				for _, battery := range batteries(system.GetSnapshot()) {
+					statuses = append(statuses, fmt.Sprintf("%s %s", battery.Name, battery.Status))
+				}
+				return strings.Join(statuses, ", ")
+			},
+		},
+		{
+			Label: "AC",
+			GetValue: func() string {
+				if system.GetMetricValue(collectors.MetricBatteryAC) > 0 {
+					return "online"
+				}
+				return "offline"
+			},
+		},
+		{
+			Label: "Power",
+			GetValue: func() string {
+				return formatBatteryPower(
+					system.GetMetricValue(collectors.MetricBatteryCharge),
+					system.GetMetricValue(collectors.MetricBatteryDischarge),
+				)
+			},
+		},
+		{
+			Label: "Time Left",
+			GetValue: func() string {
+				return formatMinutes(system.GetMetricValue(collectors.MetricBatteryEmpty))
+			},
+		},
+		{
+			Label: "Cycles",
+			GetValue: func() string {
+				return fmt.Sprintf("%.0f", system.GetMetricValue(collectors.MetricBatteryCycles))
+			},
+		},
+		{
+			Label: "Health",
+			GetValue: func() string {
+				healths := make([]string, 0, 2)
+				for _, battery := range batteries(system.GetSnapshot()) {
+					if health := battery.Health(); health > 0 {
+						healths = append(healths, fmt.Sprintf("%s %.0f%%", battery.Name, health))
+					}
+				}
+				if len(healths) == 0 {
+					return "unknown"
+				}
+				return strings.Join(healths, ", ")
+			},
+		},
+	}
+}
+
+// GetPressureInfoProvider returns stall averages per resource and the stall time since the previous tick
+func GetPressureInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	averages := func(snapshot *Snapshot, resource, line string) string {
+		return fmt.Sprintf("%.1f / %.1f / %.1f%%",
+			snapshot.Value(collectors.PressureMetric(resource, line, collectors.PressureAvg10)),
+			snapshot.Value(collectors.PressureMetric(resource, line, collectors.PressureAvg60)),
+			snapshot.Value(collectors.PressureMetric(resource, line, collectors.PressureAvg300)))
+	}
+	resourceRow := func(label, resource string) widgets.InfoRow {
+		return widgets.InfoRow{
+			Label: label,
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				value := "some " + averages(snapshot, resource, collectors.PressureSome)
+				// Older kernels report no full line for the CPU
+				if snapshot.Has(collectors.PressureMetric(resource, collectors.PressureFull, collectors.PressureAvg10)) {
+					value += ", full " + averages(snapshot, resource, collectors.PressureFull)
+				}
+				return value
+			},
+		}
+	}
+
+	return []widgets.InfoRow{
+		resourceRow("CPU 10s/60s/300s", "cpu"),
+		resourceRow("Memory 10s/60s/300s", "memory"),
+		resourceRow("I/O 10s/60s/300s", "io"),
+		{
+			Label: "Stalled Now",
+			GetValue: func() string {
+				snapshot := system.GetSnapshot()
+				stall := func(resource string) float64 {
+					return snapshot.Value(collectors.PressureMetric(resource, collectors.PressureSome, collectors.PressureStall))
+				}
+				return fmt.Sprintf("CPU %.1f%% / Mem %.1f%% / I/O %.1f%%", stall("cpu"), stall("memory"), stall("io"))
+			},
+		},
+	}
+}
+
+// GetNetworkInfoProvider returns network info functions
+func GetNetworkInfoProvider(system MonitoringSystem) []widgets.InfoRow {
+	return []widgets.InfoRow{
+		{
+			Label: "Interface",
+			GetValue: func() string {
+				if iface := selectedNetInterface(system); iface != ActiveInterfaceOption {
+					return iface
+				}
+				return system.GetActiveNetInterfaceName()
+			},
+		},
+		{
+			Label: "Download",
+			GetValue: func() string {
+				readMetric, _ := selectedNetMetrics(system)
+				return fmt.Sprintf("%.2f MB/s", system.GetMetricValue(readMetric))
+			},
+		},
+		{
+			Label: "Upload",
+			GetValue: func() string {
+				_, writeMetric := selectedNetMetrics(system)
+				return fmt.Sprintf("%.2f MB/s", system.GetMetricValue(writeMetric))
+			},
+		},
+		{
+			Label: "Packets",
+			GetValue: func() string {
+				return formatNetRates(system, "In %.0f / Out %.0f /s", collectors.NetPacketsIn, collectors.NetPacketsOut)
+			},
+		},
+		{
+			Label: "Errors",
+			GetValue: func() string {
+				return formatNetRates(system, "In %.1f / Out %.1f /s", collectors.NetErrorsIn, collectors.NetErrorsOut)
+			},
+		},
+		{
+			Label: "Drops",
+			GetValue: func() string {
+				return formatNetRates(system, "In %.1f / Out %.1f /s", collectors.NetDropsIn, collectors.NetDropsOut)
 			},
 		},
 		{
@@ -261,3 +1840,76 @@ func GetNetworkInfoProvider(system MonitoringSystem) []widgets.InfoRow {
 		},
 	}
 }
+
+// HostInfoDataProvider provides the System table, the cached host information
+type HostInfoDataProvider struct {
+	System MonitoringSystem
+}
+
+func (h *HostInfoDataProvider) GetHeaders() []string {
+	return []string{"Property", "Value"}
+}
+
+func (h *HostInfoDataProvider) GetRows() [][]string {
+	info := h.System.GetHostInfo()
+
+	platform := strings.TrimSpace(info.Platform + " " + info.PlatformVersion)
+	if platform == "" {
+		platform = info.OS
+	}
+	topology := countOf(info.PhysicalCores, "core") + ", " + countOf(info.LogicalCores, "thread")
+	if info.Sockets > 0 {
+		topology = countOf(info.Sockets, "socket") + ", " + topology
+	}
+	virtualization := info.Virtualization
+	if virtualization == "" {
+		virtualization = "none"
+	}
+	bootTime, uptime := "-", "-"
+	if !info.BootTime.IsZero() {
+		bootTime = info.BootTime.Format("2006-01-02 15:04")
+		uptime = formatUptime(info.Uptime(time.Now()))
+	}
+
+	return [][]string{
+		{"Hostname", orDash(info.Hostname)},
+		{"OS", orDash(platform)},
+		{"Kernel", orDash(strings.TrimSpace(info.KernelVersion + " " + info.KernelArch))},
+		{"CPU", info.CPUModel},
+		{"Topology", topology},
+		{"Memory", formatBytes(info.TotalRAM)},
+		{"Virtualization", virtualization},
+		{"Boot Time", bootTime},
+		{"Uptime", uptime},
+	}
+}
+
+func (h *HostInfoDataProvider) GetTitle() string {
+	return "System"
+}
+
+// countOf formats a count of a noun, "1 core" or "8 cores"
+func countOf(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// orDash returns value, or "-" when it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// formatUptime formats a duration as "3d 04h 12m", leaving out the days below one day
+func formatUptime(d time.Duration) string {
+	minutes := int(d.Minutes())
+	days, hours := minutes/(24*60), minutes/60%24
+	if days > 0 {
+		return fmt.Sprintf("%dd %02dh %02dm", days, hours, minutes%60)
+	}
+	return fmt.Sprintf("%dh %02dm", hours, minutes%60)
+}
@@ -0,0 +1,665 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-dummy-monitor/collectors"
+	"go-dummy-monitor/constants"
+	"go-dummy-monitor/procctl"
+)
+
+type filesystemTableCollector struct {
+	filesystems []collectors.FilesystemUsage
+}
+
+func (c *filesystemTableCollector) Name() string {
+	return "filesystem"
+}
+
+func (c *filesystemTableCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *filesystemTableCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	var samples []collectors.Sample
+	for _, fs := range c.filesystems {
+		samples = append(samples, collectors.Sample{Name: collectors.FilesystemUsageMetric(fs.Mountpoint), Value: fs.UsedPercent})
+	}
+	return samples, nil
+}
+
+func (c *filesystemTableCollector) Details() any {
+	return c.filesystems
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[uint64]string{
+		0:                  "0.0 B",
+		512:                "512.0 B",
+		1536:               "1.5 KB",
+		5 * 1024 * 1024:    "5.0 MB",
+		1024 * 1024 * 1024: "1.0 GB",
+	}
+	for bytes, want := range cases {
+		if got := formatBytes(bytes); got != want {
+			t.Errorf("Expected formatBytes(%d) to be %q, got %q", bytes, want, got)
+		}
+	}
+}
+
+func TestFilesystemDataProvider(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	// Replace the built-in filesystem collector output with a fixed table
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(&filesystemTableCollector{filesystems: []collectors.FilesystemUsage{
+		{Mountpoint: "/", Device: "/dev/sda1", Fstype: "ext4", Total: 1024, Used: 512, Free: 512, UsedPercent: 50, InodesTotal: 10, InodesUsedPercent: 10},
+		{Mountpoint: "/data", Device: "/dev/sdb1", Fstype: "xfs", Total: 2048, Used: 512, Free: 1536, UsedPercent: 25},
+	}})
+	system.UpdateSystemStats()
+
+	provider := &FilesystemDataProvider{System: system}
+	rows := provider.GetRows()
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	if len(rows[0]) != len(provider.GetHeaders()) {
+		t.Errorf("Expected one cell per header, got %d cells for %d headers", len(rows[0]), len(provider.GetHeaders()))
+	}
+	if rows[1][0] != "/data" || rows[1][6] != "25.0%" || rows[1][7] != "-" {
+		t.Errorf("Unexpected second row %v", rows[1])
+	}
+
+	// The usage row follows the chosen mount point
+	system.SetSelection(SelectionDiskMount, "/data")
+	if mount, usage := selectedMountUsage(system); mount != "/data" || usage != 25 {
+		t.Errorf("Expected /data at 25%%, got %s at %f", mount, usage)
+	}
+
+	// Unknown mount points fall back to the root filesystem
+	system.SetSelection(SelectionDiskMount, "/missing")
+	if mount, _ := selectedMountUsage(system); mount != "/" {
+		t.Errorf("Expected fallback to /, got %s", mount)
+	}
+}
+
+type listeningCollector struct {
+	listeners []collectors.ListeningSocket
+}
+
+func (c *listeningCollector) Name() string {
+	return "connections"
+}
+
+func (c *listeningCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *listeningCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	return []collectors.Sample{
+		{Name: collectors.TCPStateMetric("CLOSE_WAIT"), Value: 7},
+		{Name: collectors.MetricConnListening, Value: float64(len(c.listeners))},
+	}, nil
+}
+
+func (c *listeningCollector) Details() any {
+	return c.listeners
+}
+
+func TestListeningDataProvider(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	// Replace the built-in connections collector output with fixed sockets
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(&listeningCollector{listeners: []collectors.ListeningSocket{
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 22, PID: 1, Process: "sshd"},
+		{Protocol: "udp", Address: "0.0.0.0", Port: 53},
+	}})
+	system.UpdateSystemStats()
+
+	provider := &ListeningDataProvider{System: system}
+	rows := provider.GetRows()
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	if rows[0][2] != "22" || rows[0][3] != "1" || rows[0][4] != "sshd" {
+		t.Errorf("Unexpected first row %v", rows[0])
+	}
+	// Sockets without a readable owner show placeholders
+	if rows[1][3] != "-" || rows[1][4] != "-" {
+		t.Errorf("Unexpected second row %v", rows[1])
+	}
+
+	infoRows := GetConnectionsInfoProvider(system)
+	if infoRows[2].Label != "Close Wait" || infoRows[2].GetValue() != "7" {
+		t.Errorf("Expected 7 sockets in close wait, got %s %q", infoRows[2].Label, infoRows[2].GetValue())
+	}
+}
+
+type processTableCollector struct {
+	processes []collectors.ProcessInfo
+}
+
+func (c *processTableCollector) Name() string {
+	return "processes"
+}
+
+func (c *processTableCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *processTableCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	return []collectors.Sample{{Name: collectors.MetricProcCount, Value: float64(len(c.processes))}}, nil
+}
+
+func (c *processTableCollector) Details() any {
+	return c.processes
+}
+
+func TestProcessesDataProvider(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	// Replace the built-in process collector output with a fixed table
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(&processTableCollector{processes: []collectors.ProcessInfo{
+		{PID: 1, Name: "init", User: "root", CPUPercent: 0.5, RSS: 1024},
+		{PID: 200, Name: "gopls", User: "dev", CPUPercent: 80, RSS: 512 * 1024 * 1024, Threads: 12},
+		{PID: 300, Name: "postgres", User: "postgres", CPUPercent: 10, RSS: 2048, ReadRate: 4096},
+	}})
+	system.UpdateSystemStats()
+
+	provider := &ProcessesDataProvider{System: system}
+	rows := provider.GetRows()
+	if len(rows) != 3 || rows[0][1] != "gopls" || rows[0][4] != "512.0 MB" {
+		t.Fatalf("Expected gopls first by CPU, got %v", rows)
+	}
+	if len(rows[0]) != len(provider.GetHeaders()) {
+		t.Errorf("Expected one cell per header, got %d cells for %d headers", len(rows[0]), len(provider.GetHeaders()))
+	}
+
+	// The sort selector and filter input change the rows shown
+	GetProcessSelectors(system)[0].OnChanged(string(collectors.SortByIO))
+	if rows := provider.GetRows(); rows[0][1] != "postgres" || rows[0][6] != "4.0 KB/s" {
+		t.Errorf("Expected postgres first by I/O, got %v", rows[0])
+	}
+	GetProcessInputs(system)[0].OnChanged("root")
+	if rows := provider.GetRows(); len(rows) != 1 || rows[0][0] != "1" {
+		t.Errorf("Expected only init for filter root, got %v", rows)
+	}
+	if provider.GetTitle() != "Processes 1 of 1" {
+		t.Errorf("Expected filtered title, got %q", provider.GetTitle())
+	}
+
+	// The CPU widget lists the top consumers, missing ranks show a dash
+	infoRows := GetCPUInfoProvider(system)
+	top := infoRows[len(infoRows)-TopProcessCount:]
+	if top[0].Label != "Top 1" || top[0].GetValue() != "gopls (200) 80.0%" {
+		t.Errorf("Expected gopls as top CPU consumer, got %s %q", top[0].Label, top[0].GetValue())
+	}
+	if top[TopProcessCount-1].GetValue() != "-" {
+		t.Errorf("Expected a dash for a missing rank, got %q", top[TopProcessCount-1].GetValue())
+	}
+}
+
+func TestProcessTreeDataProvider(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(&processTableCollector{processes: []collectors.ProcessInfo{
+		{PID: 1, Name: "init", User: "root"},
+		{PID: 10, PPID: 1, Name: "make", User: "dev", CPUPercent: 2},
+		{PID: 11, PPID: 10, Name: "cc1", User: "dev", CPUPercent: 99, RSS: 2048},
+		{PID: 20, PPID: 1, Name: "sshd", User: "root"},
+	}})
+	system.UpdateSystemStats()
+
+	provider := &ProcessTreeDataProvider{System: system}
+	rows := provider.GetRows()
+	if len(rows) != 4 {
+		t.Fatalf("Expected 4 rows, got %v", rows)
+	}
+	if rows[0][1] != "init" || rows[1][1] != "└─ make" || rows[2][1] != "  └─ cc1" || rows[3][1] != "└─ sshd" {
+		t.Errorf("Expected the names indented by depth, got %v", rows)
+	}
+	if len(rows[0]) != len(provider.GetHeaders()) {
+		t.Errorf("Expected one cell per header, got %d cells for %d headers", len(rows[0]), len(provider.GetHeaders()))
+	}
+
+	// Filtering keeps the ancestors of a match
+	GetProcessTreeInputs(system)[0].OnChanged("cc1")
+	if rows := provider.GetRows(); len(rows) != 3 || rows[2][0] != "11" {
+		t.Errorf("Expected cc1 below init and make, got %v", rows)
+	}
+	if provider.GetTitle() != "Process tree 3 of 3" {
+		t.Errorf("Expected filtered title, got %q", provider.GetTitle())
+	}
+}
+
+func TestAuditLogDataProvider(t *testing.T) {
+	log := procctl.NewAuditLog(nil)
+	start := time.Date(2024, 5, 1, 9, 30, 0, 0, time.Local)
+	for i := 0; i < AuditLogRows+2; i++ {
+		_ = log.Record(procctl.AuditEntry{Time: start.Add(time.Duration(i) * time.Second), Action: "SIGTERM", PID: int32(i), Process: "make"})
+	}
+	_ = log.Record(procctl.AuditEntry{Time: start.Add(time.Minute), Action: "renice -5", PID: 42, Process: "make", Error: "permission denied"})
+
+	provider := &AuditLogDataProvider{Log: log}
+	rows := provider.GetRows()
+	if len(rows) != AuditLogRows {
+		t.Fatalf("Expected %d rows, got %d", AuditLogRows, len(rows))
+	}
+	// The newest action comes first and shows its error as the result
+	if rows[0][0] != "09:31:00" || rows[0][1] != "renice -5" || rows[0][4] != "permission denied" {
+		t.Errorf("Expected the failed renice first, got %v", rows[0])
+	}
+	if rows[1][4] != "OK" {
+		t.Errorf("Expected a successful action to show OK, got %q", rows[1][4])
+	}
+}
+
+func TestHostInfoDataProvider(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+	system.hostInfo.Store(&collectors.HostInfo{
+		Hostname:        "build-01",
+		OS:              "linux",
+		Platform:        "ubuntu",
+		PlatformVersion: "24.04",
+		KernelVersion:   "6.8.0-31-generic",
+		KernelArch:      "x86_64",
+		CPUModel:        "AMD EPYC 7763",
+		Sockets:         1,
+		PhysicalCores:   8,
+		LogicalCores:    16,
+		TotalRAM:        32 * 1024 * 1024 * 1024,
+		BootTime:        time.Now().Add(-(50*time.Hour + 5*time.Minute + 30*time.Second)),
+		Virtualization:  "kvm guest",
+	})
+
+	rows := (&HostInfoDataProvider{System: system}).GetRows()
+	values := make(map[string]string)
+	for _, row := range rows {
+		values[row[0]] = row[1]
+	}
+	expected := map[string]string{
+		"Hostname":       "build-01",
+		"OS":             "ubuntu 24.04",
+		"Kernel":         "6.8.0-31-generic x86_64",
+		"CPU":            "AMD EPYC 7763",
+		"Topology":       "1 socket, 8 cores, 16 threads",
+		"Memory":         "32.0 GB",
+		"Virtualization": "kvm guest",
+		"Uptime":         "2d 02h 05m",
+	}
+	for label, want := range expected {
+		if values[label] != want {
+			t.Errorf("Expected %s to be %q, got %q", label, want, values[label])
+		}
+	}
+
+	// Unknown values are shown as such rather than left blank
+	system.hostInfo.Store(&collectors.HostInfo{OS: "linux", CPUModel: "Unknown CPU", PhysicalCores: 1, LogicalCores: 1})
+	values = make(map[string]string)
+	for _, row := range (&HostInfoDataProvider{System: system}).GetRows() {
+		values[row[0]] = row[1]
+	}
+	if values["OS"] != "linux" || values["Hostname"] != "-" || values["Uptime"] != "-" ||
+		values["Virtualization"] != "none" || values["Topology"] != "1 core, 1 thread" {
+		t.Errorf("Unexpected rows for unknown host information %v", values)
+	}
+}
+
+type sensorsCollector struct {
+	readings []collectors.SensorReading
+}
+
+func (c *sensorsCollector) Name() string {
+	return "sensors"
+}
+
+func (c *sensorsCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *sensorsCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	samples := []collectors.Sample{{Name: collectors.MetricSensorCPU, Value: c.readings[0].Value}}
+	for _, reading := range c.readings {
+		samples = append(samples, collectors.Sample{Name: collectors.SensorMetric(reading.Kind, reading.Key), Value: reading.Value})
+	}
+	return samples, nil
+}
+
+func (c *sensorsCollector) Details() any {
+	return c.readings
+}
+
+func TestSensorsDataProvider(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	sensors := &sensorsCollector{readings: []collectors.SensorReading{
+		{Key: "coretemp Package id 0", Kind: collectors.SensorTemperature, Value: 62, High: 80, Critical: 100},
+		{Key: "nvme Composite", Kind: collectors.SensorTemperature, Value: 45},
+		{Key: "thinkpad fan1", Kind: collectors.SensorFan, Value: 2900},
+	}}
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(sensors)
+	system.UpdateSystemStats()
+
+	provider := &SensorsDataProvider{System: system}
+	if provider.GetCurrentValue() != 62 {
+		t.Errorf("Expected a CPU temperature of 62, got %f", provider.GetCurrentValue())
+	}
+	if provider.GetHighlight() != nil {
+		t.Error("Expected no highlight below the thresholds")
+	}
+
+	// Crossing the high threshold warns, crossing the critical one is an error
+	sensors.readings[0].Value = 85
+	system.UpdateSystemStats()
+	if provider.GetHighlight() != system.GetColorScheme().Warning {
+		t.Error("Expected the warning color above the high threshold")
+	}
+	sensors.readings[0].Value = 101
+	system.UpdateSystemStats()
+	if provider.GetHighlight() != system.GetColorScheme().Error {
+		t.Error("Expected the error color above the critical threshold")
+	}
+	// The axis grows once the temperature exceeds 100
+	if provider.GetMaxValue() != 200 {
+		t.Errorf("Expected the axis to grow to 200, got %f", provider.GetMaxValue())
+	}
+
+	fans := (&SensorSeriesDataProvider{System: system, Kind: collectors.SensorFan, MaxValue: 1000}).GetSeries()
+	if len(fans) != 1 || fans[0].Label != "thinkpad fan1" || len(fans[0].Data) == 0 {
+		t.Errorf("Expected one fan series, got %+v", fans)
+	}
+
+	rows := (&SensorsTableDataProvider{System: system}).GetRows()
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %v", rows)
+	}
+	if rows[0][1] != "101.0 °C" || rows[0][3] != "100.0 °C" || rows[1][2] != "-" || rows[2][1] != "2900 RPM" {
+		t.Errorf("Unexpected sensor rows %v", rows)
+	}
+}
+
+type batteryCollector struct {
+	percent, ac float64
+}
+
+func (c *batteryCollector) Name() string {
+	return "battery"
+}
+
+func (c *batteryCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *batteryCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	return []collectors.Sample{
+		{Name: collectors.MetricBatteryPercent, Value: c.percent},
+		{Name: collectors.MetricBatteryAC, Value: c.ac},
+		{Name: collectors.MetricBatteryEmpty, Value: 125},
+		{Name: collectors.MetricBatteryDischarge, Value: 9.5},
+	}, nil
+}
+
+func (c *batteryCollector) Details() any {
+	return []collectors.BatteryInfo{{Name: "BAT0", Status: collectors.BatteryDischarging, FullWh: 45, DesignWh: 50}}
+}
+
+func TestBatteryDataProvider(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	battery := &batteryCollector{percent: 15, ac: 1}
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(battery)
+	system.UpdateSystemStats()
+
+	provider := &BatteryDataProvider{System: system}
+	// A low battery is fine while on external power
+	if provider.GetHighlight() != nil {
+		t.Error("Expected no highlight on external power")
+	}
+
+	battery.ac = 0
+	system.UpdateSystemStats()
+	if provider.GetHighlight() != system.GetColorScheme().Warning {
+		t.Error("Expected the warning color on a low battery")
+	}
+	battery.percent = 5
+	system.UpdateSystemStats()
+	if provider.GetHighlight() != system.GetColorScheme().Error {
+		t.Error("Expected the error color on a critical battery")
+	}
+
+	values := make(map[string]string)
+	for _, row := range GetBatteryInfoProvider(system) {
+		values[row.Label] = row.GetValue()
+	}
+	if values["Status"] != "BAT0 Discharging" || values["AC"] != "offline" || values["Time Left"] != "2h 05m" || values["Health"] != "BAT0 90%" ||
+		values["Power"] != "9.5 W out" {
+		t.Errorf("Unexpected battery info %v", values)
+	}
+}
+
+type pressureCollector struct {
+	memoryFull float64
+}
+
+func (c *pressureCollector) Name() string {
+	return "pressure"
+}
+
+func (c *pressureCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *pressureCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	return []collectors.Sample{
+		{Name: collectors.PressureMetric("cpu", collectors.PressureSome, collectors.PressureAvg10), Value: 12},
+		{Name: collectors.PressureMetric("memory", collectors.PressureSome, collectors.PressureAvg10), Value: c.memoryFull},
+		{Name: collectors.PressureMetric("memory", collectors.PressureFull, collectors.PressureAvg10), Value: c.memoryFull},
+		{Name: collectors.PressureMetric("io", collectors.PressureSome, collectors.PressureStall), Value: 40},
+		{Name: collectors.MetricPressure, Value: 12},
+	}, nil
+}
+
+func TestPressureDataProvider(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	pressure := &pressureCollector{memoryFull: 2}
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(pressure)
+	system.UpdateSystemStats()
+
+	provider := &PressureDataProvider{System: system}
+	if provider.GetHighlight() != system.GetColorScheme().Warning {
+		t.Error("Expected the warning color when some tasks stall on the CPU")
+	}
+	if provider.GetMaxValue() != 20 {
+		t.Errorf("Expected the axis to grow to 20, got %f", provider.GetMaxValue())
+	}
+
+	// Every task stalling on memory is worse than some tasks stalling on the CPU
+	pressure.memoryFull = 15
+	system.UpdateSystemStats()
+	if provider.GetHighlight() != system.GetColorScheme().Error {
+		t.Error("Expected the error color when all tasks stall on memory")
+	}
+
+	infoRows := GetPressureInfoProvider(system)
+	if value := infoRows[0].GetValue(); value != "some 12.0 / 0.0 / 0.0%" {
+		t.Errorf("Expected the CPU row without a full line, got %q", value)
+	}
+	if value := infoRows[1].GetValue(); value != "some 15.0 / 0.0 / 0.0%, full 15.0 / 0.0 / 0.0%" {
+		t.Errorf("Expected the memory row with a full line, got %q", value)
+	}
+	if value := infoRows[3].GetValue(); value != "CPU 0.0% / Mem 0.0% / I/O 40.0%" {
+		t.Errorf("Unexpected stall row %q", value)
+	}
+}
+
+type cgroupsCollector struct{}
+
+func (c *cgroupsCollector) Name() string {
+	return "cgroups"
+}
+
+func (c *cgroupsCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *cgroupsCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	container := "/system.slice/docker-1.scope"
+	return []collectors.Sample{
+		{Name: collectors.MetricCPUUsage, Value: 10},
+		{Name: collectors.MetricRAMUsage, Value: 30},
+		{Name: collectors.CgroupMetric(container, collectors.CgroupCPU), Value: 90},
+		{Name: collectors.CgroupMetric(container, collectors.CgroupMemory), Value: 25},
+	}, nil
+}
+
+func (c *cgroupsCollector) Details() any {
+	return []collectors.CgroupUsage{
+		{Path: "/system.slice/docker-1.scope", CPUCores: 0.45, CPULimit: 0.5, MemoryBytes: 64 * 1024 * 1024, MemoryMax: 256 * 1024 * 1024,
+			ReadRate: 2 * 1024 * 1024, WriteRate: 512 * 1024, Pids: 12, PidsMax: 100},
+	}
+}
+
+func TestScopeSelection(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(&cgroupsCollector{})
+	system.UpdateSystemStats()
+
+	cpu := &CPUDataProvider{System: system}
+	ram := &RAMDataProvider{System: system}
+	selector := GetScopeSelectors(system)[0]
+
+	// The host is shown by default
+	if cpu.GetCurrentValue() != 10 || ram.GetCurrentValue() != 30 || selector.GetSelected() != HostScopeOption {
+		t.Errorf("Expected host usage by default, got CPU %f RAM %f", cpu.GetCurrentValue(), ram.GetCurrentValue())
+	}
+	if options := selector.GetOptions(); len(options) != 2 || options[1] != "/system.slice/docker-1.scope" {
+		t.Errorf("Expected the host and the container as scopes, got %v", options)
+	}
+
+	// Both widgets switch to the chosen cgroup, measured against its limits
+	selector.OnChanged("/system.slice/docker-1.scope")
+	if cpu.GetCurrentValue() != 90 || ram.GetCurrentValue() != 25 {
+		t.Errorf("Expected the container usage, got CPU %f RAM %f", cpu.GetCurrentValue(), ram.GetCurrentValue())
+	}
+	if cpu.GetTitle() != "CPU /system.slice/docker-1.scope" {
+		t.Errorf("Expected the scope in the title, got %q", cpu.GetTitle())
+	}
+	for _, row := range GetCPUInfoProvider(system) {
+		if row.Label == "Cgroup" && row.GetValue() != "0.45 of 0.50 CPUs" {
+			t.Errorf("Unexpected CPU cgroup row %q", row.GetValue())
+		}
+		if row.Label == "Cgroup Tasks" && row.GetValue() != "12 of 100" {
+			t.Errorf("Unexpected cgroup tasks row %q", row.GetValue())
+		}
+	}
+	for _, row := range GetDiskInfoProvider(system) {
+		if row.Label == "Cgroup Read / Write" && row.GetValue() != "2.0 MB/s / 512.0 KB/s" {
+			t.Errorf("Unexpected disk cgroup row %q", row.GetValue())
+		}
+	}
+	for _, row := range GetRAMInfoProvider(system) {
+		if row.Label == "Cgroup" && row.GetValue() != "64.0 MB of 256.0 MB" {
+			t.Errorf("Unexpected RAM cgroup row %q", row.GetValue())
+		}
+	}
+
+	// A cgroup that went away shows the host again
+	system.SetSelection(SelectionScope, "/gone.slice")
+	if cpu.GetCurrentValue() != 10 {
+		t.Errorf("Expected host usage for a missing cgroup, got %f", cpu.GetCurrentValue())
+	}
+}
+
+func TestScaledMaxValue(t *testing.T) {
+	cases := []struct {
+		minimum float64
+		history []float64
+		want    float64
+	}{
+		{100, []float64{10, 99}, 100}, // Fits within the minimum
+		{100, []float64{130}, 200},    // Rounded up to 2 x 100
+		{100, []float64{201}, 500},    // Rounded up to 5 x 100
+		{100, []float64{501}, 1000},   // Rounded up to the next power of ten
+		{1, []float64{0.2, 1.5}, 2},   // Small rates
+		{100, nil, 100},               // No history yet
+	}
+	for _, c := range cases {
+		if got := scaledMaxValue(c.minimum, c.history); got != c.want {
+			t.Errorf("Expected %.1f for minimum %.1f and history %v, got %.1f", c.want, c.minimum, c.history, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"sort"
+	"time"
+)
+
+// Snapshot is an immutable view of every metric at the end of one update.
+// A new Snapshot is published on each update; existing ones are never modified,
+// so a reader holding a Snapshot always sees values and histories that belong together.
+type Snapshot struct {
+	Timestamp time.Time
+	values    map[string]float64
+	history   map[string][]float64
+	details   map[string]any
+}
+
+// newSnapshot creates an empty snapshot with zeroed histories of the given length
+func newSnapshot(dataPoints int, metrics []string) *Snapshot {
+	snapshot := &Snapshot{
+		values:  make(map[string]float64),
+		history: make(map[string][]float64, len(metrics)),
+		details: make(map[string]any),
+	}
+	for _, metric := range metrics {
+		snapshot.history[metric] = make([]float64, dataPoints)
+	}
+	return snapshot
+}
+
+// Value returns the current value of the named metric
+func (s *Snapshot) Value(name string) float64 {
+	return s.values[name]
+}
+
+// History returns a copy of the named metric history, or nil if the metric is unknown
+func (s *Snapshot) History(name string) []float64 {
+	data, ok := s.history[name]
+	if !ok {
+		return nil
+	}
+
+	result := make([]float64, len(data))
+	copy(result, data)
+	return result
+}
+
+// Detail returns the structured data published by the named collector, or nil
+func (s *Snapshot) Detail(collector string) any {
+	return s.details[collector]
+}
+
+// Has reports whether the snapshot contains the named metric
+func (s *Snapshot) Has(name string) bool {
+	_, ok := s.history[name]
+	return ok
+}
+
+// Names returns the sorted names of all metrics in the snapshot
+func (s *Snapshot) Names() []string {
+	names := make([]string, 0, len(s.history))
+	for name := range s.history {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-dummy-monitor/collectors"
+	"go-dummy-monitor/constants"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	snapshot := newSnapshot(10, []string{"a", "b"})
+
+	if !snapshot.Has("a") || !snapshot.Has("b") {
+		t.Error("Expected snapshot to contain the pre-created metrics")
+	}
+	if snapshot.Has("c") {
+		t.Error("Expected snapshot not to contain unknown metrics")
+	}
+	if len(snapshot.History("a")) != 10 {
+		t.Errorf("Expected history length to be 10, got %d", len(snapshot.History("a")))
+	}
+	if snapshot.History("c") != nil {
+		t.Error("Expected nil history for unknown metric")
+	}
+	if names := snapshot.Names(); len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("Expected sorted names [a b], got %v", names)
+	}
+}
+
+func TestSnapshotHistoryIsCopy(t *testing.T) {
+	snapshot := newSnapshot(3, []string{"a"})
+
+	data := snapshot.History("a")
+	data[0] = 99
+
+	if snapshot.History("a")[0] != 0 {
+		t.Error("Expected modifying returned history not to affect the snapshot")
+	}
+}
+
+func TestPublishedSnapshotsAreImmutable(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		5, // dataPoints
+	)
+	_ = system.RegisterCollector(&constantCollector{value: 1})
+
+	system.UpdateSystemStats()
+	first := system.GetSnapshot()
+	firstHistory := first.History("constant.value")
+
+	time.Sleep(time.Millisecond)
+	system.UpdateSystemStats()
+	second := system.GetSnapshot()
+
+	if first == second {
+		t.Fatal("Expected a new snapshot to be published on update")
+	}
+	if !second.Timestamp.After(first.Timestamp) {
+		t.Error("Expected the new snapshot to have a later timestamp")
+	}
+	for i, v := range first.History("constant.value") {
+		if v != firstHistory[i] {
+			t.Fatalf("Expected old snapshot history to stay unchanged at %d: %f != %f", i, v, firstHistory[i])
+		}
+	}
+	if got := second.History("constant.value"); got[len(got)-2] != 1 || got[len(got)-1] != 1 {
+		t.Errorf("Expected two recorded samples in the new snapshot, got %v", got)
+	}
+	if first.Has(collectors.MetricCPUUsage) != second.Has(collectors.MetricCPUUsage) {
+		t.Error("Expected built-in metrics to carry over between snapshots")
+	}
+}
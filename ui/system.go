@@ -1,40 +1,43 @@
 package ui
 
 import (
-	"fmt"
-	"go-dummy-monitor/constants"
+	"context"
+	"go-dummy-monitor/collectors"
+	"go-dummy-monitor/utils"
 	"image/color"
-	"net"
-	"os/exec"
-	"runtime"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/shirou/gopsutil/cpu"
+	"fyne.io/fyne/v2"
 	"github.com/shirou/gopsutil/disk"
-	"github.com/shirou/gopsutil/mem"
-	psnet "github.com/shirou/gopsutil/net"
 )
 
-// MonitorSystem implements the MonitoringSystem interface
+// MonitorSystem implements the MonitoringSystem interface.
+// Updates run under updateMu and publish a new immutable Snapshot; readers
+// only ever load the latest published Snapshot and never block the updater.
 type MonitorSystem struct {
-	cpuData          []float64
-	cpuUsage         float64
-	ramData          []float64
-	ramUsage         float64
-	diskReadData     []float64
-	diskWriteData    []float64
-	diskUsage        float64
-	networkReadData  []float64
-	networkWriteData []float64
-	prevDiskStats    map[string]disk.IOCountersStat
-	maxNetworkSpeed  float64
-	colorScheme      ColorScheme
-	emptyRectangle   color.Color
-	darkMode         bool
+	updateMu        sync.Mutex
+	registry        *collectors.Registry
+	snapshot        atomic.Pointer[Snapshot]
+	hostInfo        atomic.Pointer[collectors.HostInfo]
+	dataPoints      int
+	maxNetworkSpeed float64
+
+	themeMu        sync.RWMutex
+	colorScheme    ColorScheme
+	emptyRectangle color.Color
+	darkMode       bool
+
+	selectionMu sync.RWMutex
+	selections  map[string]string
+	preferences fyne.Preferences
 }
 
-// NewMonitorSystem creates a new MonitorSystem
+// selectionPreferencePrefix starts the preference keys under which selections are persisted
+const selectionPreferencePrefix = "selection."
+
+// NewMonitorSystem creates a new MonitorSystem with the built-in collectors registered
 func NewMonitorSystem(
 	maxNetworkSpeed float64,
 	darkMode bool,
@@ -44,15 +47,12 @@ func NewMonitorSystem(
 	dataPoints int,
 ) *MonitorSystem {
 	system := &MonitorSystem{
-		cpuData:          make([]float64, dataPoints),
-		ramData:          make([]float64, dataPoints),
-		diskReadData:     make([]float64, dataPoints),
-		diskWriteData:    make([]float64, dataPoints),
-		networkReadData:  make([]float64, dataPoints),
-		networkWriteData: make([]float64, dataPoints),
-		maxNetworkSpeed:  maxNetworkSpeed,
-		emptyRectangle:   emptyRectangle,
-		darkMode:         darkMode,
+		registry:        collectors.NewRegistry(),
+		dataPoints:      dataPoints,
+		maxNetworkSpeed: maxNetworkSpeed,
+		emptyRectangle:  emptyRectangle,
+		darkMode:        darkMode,
+		selections:      make(map[string]string),
 	}
 
 	// Set initial color scheme based on mode
@@ -62,18 +62,36 @@ func NewMonitorSystem(
 		system.colorScheme = lightColorScheme
 	}
 
-	// Initialize previous disk stats
-	var err error
-	system.prevDiskStats, err = disk.IOCounters()
-	if err != nil {
-		system.prevDiskStats = make(map[string]disk.IOCountersStat)
+	// Pre-create history for the built-in metrics so graphs have data before the first tick
+	system.snapshot.Store(newSnapshot(dataPoints, []string{
+		collectors.MetricCPUUsage,
+		collectors.MetricRAMUsage,
+		collectors.MetricDiskRead,
+		collectors.MetricDiskWrite,
+		collectors.MetricNetRead,
+		collectors.MetricNetWrite,
+	}))
+
+	for _, c := range collectors.Builtin() {
+		_ = system.RegisterCollector(c)
 	}
 
 	return system
 }
 
+// RegisterCollector adds a collector to be driven by UpdateSystemStats
+func (s *MonitorSystem) RegisterCollector(c collectors.Collector) error {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	return s.registry.Register(c)
+}
+
 // UpdateTheme updates the color scheme based on dark mode
 func (s *MonitorSystem) UpdateTheme(darkMode bool, lightColorScheme ColorScheme, darkColorScheme ColorScheme) {
+	s.themeMu.Lock()
+	defer s.themeMu.Unlock()
+
 	s.darkMode = darkMode
 	if darkMode {
 		s.colorScheme = darkColorScheme
@@ -82,99 +100,128 @@ func (s *MonitorSystem) UpdateTheme(darkMode bool, lightColorScheme ColorScheme,
 	}
 }
 
-// UpdateSystemStats collects and updates system stats
-func (s *MonitorSystem) UpdateSystemStats() {
-	cpuUsage, _ := cpu.Percent(0, false)
-	memStats, _ := mem.VirtualMemory()
-	diskStats, _ := disk.Usage("/")
-	initialStats, _ := psnet.IOCounters(true)
+// collectWait is how long an update waits for its collectors, slower ones are published by a later update
+const collectWait = collectors.DefaultInterval / 2
 
-	time.Sleep(time.Millisecond * 500)
+// UpdateSystemStats runs all due collectors and publishes a new snapshot
+func (s *MonitorSystem) UpdateSystemStats() {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
 
-	netStats, _ := psnet.IOCounters(true)
+	ctx, cancel := context.WithTimeout(context.Background(), collectWait)
+	defer cancel()
 
-	activeNetInterfaceName := s.GetActiveNetInterfaceName()
+	// Failing and overrunning collectors simply leave their metrics untouched for this tick
+	now := time.Now()
+	samples, _ := s.registry.CollectDue(ctx, now)
 
-	// Initialize with some values to make the graph visible
-	netReadSpeed := 0.0
-	netWriteSpeed := 0.0
+	next := s.nextSnapshot(now, samples)
+	next.details = s.registry.Details()
+	s.snapshot.Store(next)
+}
 
-	// Get the actual network usage if available
-	if activeNetInterfaceName != "" {
-		for i := 0; i < len(netStats); i++ {
-			if strings.Contains(strings.ToLower(netStats[i].Name), strings.ToLower(activeNetInterfaceName)) {
-				bytesReceived := netStats[i].BytesRecv - initialStats[i].BytesRecv
-				mbReceived := float64(bytesReceived) / (1024 * 1024)
-				netReadSpeed = mbReceived * 2 // Convert to per second (500ms interval)
+// nextSnapshot builds a new snapshot from the current one and the collected samples.
+// Histories of metrics without a new sample are shared with the previous snapshot,
+// which is safe because published histories are never modified. Metrics of things
+// that are gone, such as removed cgroups, are left out.
+func (s *MonitorSystem) nextSnapshot(now time.Time, samples []collectors.Sample) *Snapshot {
+	prev := s.snapshot.Load()
+	next := &Snapshot{
+		Timestamp: now,
+		values:    make(map[string]float64, len(prev.values)+len(samples)),
+		history:   make(map[string][]float64, len(prev.history)+len(samples)),
+	}
+	for name, value := range prev.values {
+		if !s.registry.Expired(name) {
+			next.values[name] = value
+		}
+	}
+	for name, data := range prev.history {
+		if !s.registry.Expired(name) {
+			next.history[name] = data
+		}
+	}
 
-				bytesSent := netStats[i].BytesSent - initialStats[i].BytesSent
-				mbSent := float64(bytesSent) / (1024 * 1024)
-				netWriteSpeed = mbSent * 2 // Convert to per second (500ms interval)
+	for _, sample := range samples {
+		next.values[sample.Name] = sample.Value
 
-				// Debug print
-				//fmt.Printf("Net speed: D=%.2f MB/s, U=%.2f MB/s\n", netReadSpeed, netWriteSpeed)
-				break
-			}
+		data, ok := next.history[sample.Name]
+		if !ok {
+			data = make([]float64, s.dataPoints)
 		}
+		shifted := make([]float64, len(data))
+		if len(data) > 0 {
+			copy(shifted, data[1:])
+			shifted[len(shifted)-1] = sample.Value
+		}
+		next.history[sample.Name] = shifted
 	}
 
-	ioStats, _ := disk.IOCounters()
-	// No debug output needed in production
+	return next
+}
+
+// GetSnapshot returns the latest published snapshot
+func (s *MonitorSystem) GetSnapshot() *Snapshot {
+	return s.snapshot.Load()
+}
 
-	var readSpeed, writeSpeed float64
-	// Initialize ioStats if prev is empty
-	if len(s.prevDiskStats) == 0 {
-		s.prevDiskStats = ioStats
+// GetMetricData returns a copy of the named metric history
+func (s *MonitorSystem) GetMetricData(name string) []float64 {
+	data := s.GetSnapshot().History(name)
+	if data == nil {
+		return make([]float64, s.dataPoints)
 	}
+	return data
+}
 
-	// Initialize with some values to make the graph visible
-	readSpeed = 0.0
-	writeSpeed = 0.0
-
-	// Get real disk activity if available
-	for diskName, stats := range ioStats {
-		prevStats, exists := s.prevDiskStats[diskName]
-		if exists {
-			// The values are in bytes per interval (500ms), convert to MB/s
-			bytesRead := float64(stats.ReadBytes - prevStats.ReadBytes)
-			bytesWritten := float64(stats.WriteBytes - prevStats.WriteBytes)
-
-			// Convert bytes to MB and adjust for time interval (500ms = 0.5s)
-			readSpeed = (bytesRead / 1024 / 1024) * 2     // Convert to MB/s
-			writeSpeed = (bytesWritten / 1024 / 1024) * 2 // Convert to MB/s
-
-			// Avoid stopping at the first disk with no activity
-			if readSpeed > 0 || writeSpeed > 0 {
-				break
-			}
-		}
+// GetMetricValue returns the current value of the named metric
+func (s *MonitorSystem) GetMetricValue(name string) float64 {
+	return s.GetSnapshot().Value(name)
+}
+
+// PersistSelections keeps selector choices in the given preferences so they survive restarts
+func (s *MonitorSystem) PersistSelections(preferences fyne.Preferences) {
+	s.selectionMu.Lock()
+	defer s.selectionMu.Unlock()
+
+	s.preferences = preferences
+}
+
+// GetSelection returns the value chosen in the named selector, or an empty string
+func (s *MonitorSystem) GetSelection(key string) string {
+	s.selectionMu.RLock()
+	defer s.selectionMu.RUnlock()
+
+	if value, ok := s.selections[key]; ok || s.preferences == nil {
+		return value
 	}
+	return s.preferences.String(selectionPreferencePrefix + key)
+}
 
-	// Update system data
-	s.cpuUsage = cpuUsage[0]
-	s.ramUsage = memStats.UsedPercent
-	s.diskUsage = diskStats.UsedPercent
-	s.prevDiskStats = ioStats
-
-	// Update historical data
-	s.cpuData = append(s.cpuData[1:], s.cpuUsage)
-	s.ramData = append(s.ramData[1:], s.ramUsage)
-	// readSpeed and writeSpeed are already in MB/s
-	s.diskReadData = append(s.diskReadData[1:], readSpeed)
-	s.diskWriteData = append(s.diskWriteData[1:], writeSpeed)
-	s.networkReadData = append(s.networkReadData[1:], netReadSpeed)
-	s.networkWriteData = append(s.networkWriteData[1:], netWriteSpeed)
-
-	// Values updated successfully
+// SetSelection remembers the value chosen in the named selector
+func (s *MonitorSystem) SetSelection(key, value string) {
+	s.selectionMu.Lock()
+	defer s.selectionMu.Unlock()
+
+	s.selections[key] = value
+	if s.preferences != nil {
+		s.preferences.SetString(selectionPreferencePrefix+key, value)
+	}
 }
 
 // IsDarkMode returns whether the system is in dark mode
 func (s *MonitorSystem) IsDarkMode() bool {
+	s.themeMu.RLock()
+	defer s.themeMu.RUnlock()
+
 	return s.darkMode
 }
 
 // GetColorScheme returns the current color scheme
 func (s *MonitorSystem) GetColorScheme() ColorScheme {
+	s.themeMu.RLock()
+	defer s.themeMu.RUnlock()
+
 	return s.colorScheme
 }
 
@@ -185,183 +232,76 @@ func (s *MonitorSystem) GetEmptyRectangle() color.Color {
 
 // GetCPUData returns the CPU usage data
 func (s *MonitorSystem) GetCPUData() []float64 {
-	return s.cpuData
+	return s.GetMetricData(collectors.MetricCPUUsage)
 }
 
 // GetCPUUsage returns the current CPU usage
 func (s *MonitorSystem) GetCPUUsage() float64 {
-	return s.cpuUsage
+	return s.GetMetricValue(collectors.MetricCPUUsage)
 }
 
 // GetRAMData returns the RAM usage data
 func (s *MonitorSystem) GetRAMData() []float64 {
-	return s.ramData
+	return s.GetMetricData(collectors.MetricRAMUsage)
 }
 
 // GetRAMUsage returns the current RAM usage
 func (s *MonitorSystem) GetRAMUsage() float64 {
-	return s.ramUsage
+	return s.GetMetricValue(collectors.MetricRAMUsage)
 }
 
 // GetDiskReadData returns the disk read data
 func (s *MonitorSystem) GetDiskReadData() []float64 {
-	return s.diskReadData
+	return s.GetMetricData(collectors.MetricDiskRead)
 }
 
 // GetDiskWriteData returns the disk write data
 func (s *MonitorSystem) GetDiskWriteData() []float64 {
-	return s.diskWriteData
+	return s.GetMetricData(collectors.MetricDiskWrite)
 }
 
 // GetDiskUsage returns the current disk usage
 func (s *MonitorSystem) GetDiskUsage() float64 {
-	return s.diskUsage
+	return s.GetMetricValue(collectors.MetricDiskUsage)
 }
 
 // GetNetworkReadData returns the network read data
 func (s *MonitorSystem) GetNetworkReadData() []float64 {
-	return s.networkReadData
+	return s.GetMetricData(collectors.MetricNetRead)
 }
 
 // GetNetworkWriteData returns the network write data
 func (s *MonitorSystem) GetNetworkWriteData() []float64 {
-	return s.networkWriteData
+	return s.GetMetricData(collectors.MetricNetWrite)
 }
 
 // GetMaxNetworkSpeed returns the max network speed
 func (s *MonitorSystem) GetMaxNetworkSpeed() float64 {
+	// Prefer the speed of the currently active interface once the network collector has run
+	if speed := s.GetSnapshot().Value(collectors.MetricNetMaxSpeed); speed > 0 {
+		return speed
+	}
 	return s.maxNetworkSpeed
 }
 
 // GetActiveNetInterfaceName returns the active network interface name
 func (s *MonitorSystem) GetActiveNetInterfaceName() string {
-	interfaces, err := net.Interfaces()
-	if err != nil {
-		fmt.Println("Error:", err)
-		return ""
-	}
-
-	// Don't need debug output in production
-
-	for _, iface := range interfaces {
-		// Skip interfaces that are down or loopback interfaces
-		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
-			continue
-		}
-
-		// Check if the interface has an IP address
-		addrs, err := iface.Addrs()
-		if err == nil && len(addrs) > 0 {
-			// Found a usable interface
-
-			// For macOS, we need to handle different interface names
-			if runtime.GOOS == "darwin" {
-				// TODO: On macOS, we look for en0 (WiFi) or en1 (Ethernet)
-				if iface.Name == "en0" || iface.Name == "en1" {
-					return iface.Name
-				}
-			} else {
-				// Always accept these common types
-				if strings.Contains(strings.ToLower(iface.Name), "wi-fi") ||
-					strings.Contains(strings.ToLower(iface.Name), "wlan") ||
-					strings.Contains(strings.ToLower(iface.Name), "eth") ||
-					strings.Contains(strings.ToLower(iface.Name), "en") ||
-					strings.Contains(strings.ToLower(iface.Name), "wlp") {
-					return iface.Name
-				}
-			}
-
-			// Return first valid interface if no specific match
-			return iface.Name
-		}
-	}
-	return ""
-}
-
-// getCPUInfoDarwin gets CPU info on Darwin systems
-func getCPUInfoDarwin() (string, int, int) {
-	// Get CPU model name
-	cmd := exec.Command("sysctl", "-n", "machdep.cpu.brand_string")
-	output, err := cmd.Output()
-	modelName := "Apple Silicon M1" // FIXME: this is a subject to change in future to handle M{1,2,3,4}* silicons!!
-	if err == nil {
-		modelName = strings.TrimSpace(string(output))
-	}
-
-	// Get CPU cores
-	cmd = exec.Command("sysctl", "-n", "hw.ncpu")
-	output, err = cmd.Output()
-	logicalCores := 8
-	physicalCores := 4
-	if err == nil {
-		count := strings.TrimSpace(string(output))
-		_, err := fmt.Sscanf(count, "%d", &logicalCores)
-		if err != nil {
-			return "", 0, 0
-		}
-		physicalCores = logicalCores / 2
-	}
-
-	return modelName, logicalCores, physicalCores
+	return utils.DefaultInterfaceResolver.Active()
 }
 
-// GetCPUModelName returns the CPU model name
-func (s *MonitorSystem) GetCPUModelName() string {
-	if runtime.GOOS == "darwin" {
-		modelName, _, _ := getCPUInfoDarwin()
-		return modelName
+// GetHostInfo returns the host information, collected on the first call and kept until RefreshHostInfo
+func (s *MonitorSystem) GetHostInfo() collectors.HostInfo {
+	if info := s.hostInfo.Load(); info != nil {
+		return *info
 	}
-
-	cpuInfo, err := cpu.Info()
-	if err != nil {
-		return constants.UNKNOWN_CPU
-	}
-
-	if len(cpuInfo) > 0 {
-		return cpuInfo[0].ModelName
-	}
-
-	return constants.UNKNOWN_CPU
+	return s.RefreshHostInfo()
 }
 
-// GetPhysicalCPUCount returns the number of physical CPU cores
-func (s *MonitorSystem) GetPhysicalCPUCount() int {
-	if runtime.GOOS == "darwin" {
-		_, _, physicalCores := getCPUInfoDarwin()
-		return physicalCores
-	}
-
-	count, err := cpu.Counts(false)
-	if err != nil {
-		return 1
-	}
-
-	return count
-}
-
-// GetLogicalCPUCount returns the number of logical CPU cores
-func (s *MonitorSystem) GetLogicalCPUCount() int {
-	if runtime.GOOS == "darwin" {
-		_, logicalCores, _ := getCPUInfoDarwin()
-		return logicalCores
-	}
-
-	count, err := cpu.Counts(true)
-	if err != nil {
-		return 1
-	}
-
-	return count
-}
-
-// GetVirtualMemory returns the virtual memory stats
-func (s *MonitorSystem) GetVirtualMemory() *mem.VirtualMemoryStat {
-	v, err := mem.VirtualMemory()
-	if err != nil {
-		return &mem.VirtualMemoryStat{}
-	}
-
-	return v
+// RefreshHostInfo collects the host information again, e.g. after the hostname or the memory changed
+func (s *MonitorSystem) RefreshHostInfo() collectors.HostInfo {
+	info := collectors.LoadHostInfo(context.Background())
+	s.hostInfo.Store(&info)
+	return info
 }
 
 // GetPartitionInfo returns disk partition information
@@ -1,8 +1,14 @@
 package ui
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
+	"fyne.io/fyne/v2/test"
+
+	"go-dummy-monitor/collectors"
 	"go-dummy-monitor/constants"
 )
 
@@ -23,23 +29,23 @@ func TestNewMonitorSystem(t *testing.T) {
 	if system.darkMode != false {
 		t.Error("Expected darkMode to be false")
 	}
-	if len(system.cpuData) != 60 {
-		t.Errorf("Expected cpuData length to be 60, got %d", len(system.cpuData))
+	if len(system.GetCPUData()) != 60 {
+		t.Errorf("Expected cpuData length to be 60, got %d", len(system.GetCPUData()))
 	}
-	if len(system.ramData) != 60 {
-		t.Errorf("Expected ramData length to be 60, got %d", len(system.ramData))
+	if len(system.GetRAMData()) != 60 {
+		t.Errorf("Expected ramData length to be 60, got %d", len(system.GetRAMData()))
 	}
-	if len(system.diskReadData) != 60 {
-		t.Errorf("Expected diskReadData length to be 60, got %d", len(system.diskReadData))
+	if len(system.GetDiskReadData()) != 60 {
+		t.Errorf("Expected diskReadData length to be 60, got %d", len(system.GetDiskReadData()))
 	}
-	if len(system.diskWriteData) != 60 {
-		t.Errorf("Expected diskWriteData length to be 60, got %d", len(system.diskWriteData))
+	if len(system.GetDiskWriteData()) != 60 {
+		t.Errorf("Expected diskWriteData length to be 60, got %d", len(system.GetDiskWriteData()))
 	}
-	if len(system.networkReadData) != 60 {
-		t.Errorf("Expected networkReadData length to be 60, got %d", len(system.networkReadData))
+	if len(system.GetNetworkReadData()) != 60 {
+		t.Errorf("Expected networkReadData length to be 60, got %d", len(system.GetNetworkReadData()))
 	}
-	if len(system.networkWriteData) != 60 {
-		t.Errorf("Expected networkWriteData length to be 60, got %d", len(system.networkWriteData))
+	if len(system.GetNetworkWriteData()) != 60 {
+		t.Errorf("Expected networkWriteData length to be 60, got %d", len(system.GetNetworkWriteData()))
 	}
 }
 
@@ -125,3 +131,351 @@ func TestGetMaxNetworkSpeed(t *testing.T) {
 		t.Errorf("Expected max network speed to be 100.0, got %f", system.GetMaxNetworkSpeed())
 	}
 }
+
+type constantCollector struct {
+	value float64
+}
+
+func (c *constantCollector) Name() string {
+	return "constant"
+}
+
+func (c *constantCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *constantCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	return []collectors.Sample{{Name: "constant.value", Value: c.value}}, nil
+}
+
+func TestRegisterCollector(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	if err := system.RegisterCollector(&constantCollector{value: 42}); err != nil {
+		t.Fatalf("Expected collector to register, got %v", err)
+	}
+	if err := system.RegisterCollector(&constantCollector{value: 42}); err == nil {
+		t.Error("Expected duplicate collector registration to fail")
+	}
+
+	system.UpdateSystemStats()
+
+	if system.GetMetricValue("constant.value") != 42 {
+		t.Errorf("Expected metric value to be 42, got %f", system.GetMetricValue("constant.value"))
+	}
+	data := system.GetMetricData("constant.value")
+	if len(data) != 60 {
+		t.Errorf("Expected metric history length to be 60, got %d", len(data))
+	}
+	if data[len(data)-1] != 42 {
+		t.Errorf("Expected latest history value to be 42, got %f", data[len(data)-1])
+	}
+}
+
+func TestConcurrentUpdatesAndReads(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+	_ = system.RegisterCollector(&constantCollector{value: 7})
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// Readers behave like widgets rendering while the ticker updates
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				snapshot := system.GetSnapshot()
+				data := snapshot.History("constant.value")
+				if data != nil && len(data) != 60 {
+					t.Errorf("Expected consistent history length 60, got %d", len(data))
+				}
+				_ = system.GetCPUData()
+				_ = system.GetNetworkReadData()
+				_ = system.GetColorScheme()
+				_ = system.IsDarkMode()
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			system.UpdateTheme(i%2 == 0, constants.LightColors, constants.DarkColors)
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		system.UpdateSystemStats()
+	}
+	close(done)
+	wg.Wait()
+
+	if system.GetMetricValue("constant.value") != 7 {
+		t.Errorf("Expected metric value to be 7, got %f", system.GetMetricValue("constant.value"))
+	}
+}
+
+type diskDeviceCollector struct{}
+
+func (c *diskDeviceCollector) Name() string {
+	return "disk-devices"
+}
+
+func (c *diskDeviceCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *diskDeviceCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	return []collectors.Sample{
+		{Name: collectors.DiskDeviceReadMetric("sda"), Value: 3},
+		{Name: collectors.DiskDeviceWriteMetric("sda"), Value: 4},
+		{Name: collectors.DiskDeviceMetric("sda", collectors.DiskUtil), Value: 42},
+		{Name: collectors.DiskDeviceMetric("sda", collectors.DiskReadIOPS), Value: 3500},
+	}, nil
+}
+
+func TestDiskDeviceSelection(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+	_ = system.RegisterCollector(&diskDeviceCollector{})
+	system.UpdateSystemStats()
+
+	provider := &DiskDataProvider{System: system}
+	selector := GetDiskSelectors(system)[0]
+
+	// All devices are shown until a device is chosen
+	if selector.GetSelected() != AllDevicesOption {
+		t.Errorf("Expected %q to be selected, got %q", AllDevicesOption, selector.GetSelected())
+	}
+	if provider.GetTitle() != "Disk" {
+		t.Errorf("Expected title Disk, got %q", provider.GetTitle())
+	}
+
+	options := selector.GetOptions()
+	if len(options) < 2 || options[0] != AllDevicesOption {
+		t.Fatalf("Expected all devices option followed by devices, got %v", options)
+	}
+
+	selector.OnChanged("sda")
+	if system.GetSelection(SelectionDiskDevice) != "sda" {
+		t.Errorf("Expected selection to be stored, got %q", system.GetSelection(SelectionDiskDevice))
+	}
+	if provider.GetCurrentReadValue() != 3 || provider.GetCurrentWriteValue() != 4 {
+		t.Errorf("Expected sda values 3/4, got %f/%f", provider.GetCurrentReadValue(), provider.GetCurrentWriteValue())
+	}
+	if provider.GetTitle() != "Disk sda" {
+		t.Errorf("Expected title Disk sda, got %q", provider.GetTitle())
+	}
+
+	// The utilization and await graphs follow the chosen device too, each on its own axis
+	extra := diskSeriesProviders(system)
+	if util := extra[1].GetSeries()[0].Data; util[len(util)-1] != 42 || extra[1].GetMaxValue() != 100 {
+		t.Errorf("Expected sda util 42 of 100, got %f", util[len(util)-1])
+	}
+	if await := extra[2].GetSeries(); len(await) != 1 || await[0].Label != "await ms" {
+		t.Errorf("Expected await alone on its graph, got %v", await)
+	}
+	// An SSD is not clipped at the floor of the IOPS axis
+	if max := extra[0].GetMaxValue(); max != 5000 {
+		t.Errorf("Expected the IOPS axis to grow to 5000, got %f", max)
+	}
+
+	// A device that disappeared falls back to all devices
+	system.SetSelection(SelectionDiskDevice, "sdz")
+	if provider.GetTitle() != "Disk" {
+		t.Errorf("Expected fallback title Disk, got %q", provider.GetTitle())
+	}
+}
+
+type networkInterfaceCollector struct{}
+
+func (c *networkInterfaceCollector) Name() string {
+	return "net-interfaces"
+}
+
+func (c *networkInterfaceCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *networkInterfaceCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	return []collectors.Sample{
+		{Name: collectors.NetInterfaceMetric("wlan0", collectors.NetRead), Value: 5},
+		{Name: collectors.NetInterfaceMetric("wlan0", collectors.NetWrite), Value: 6},
+		{Name: collectors.MetricNetAllRead, Value: 7},
+		{Name: collectors.MetricNetAllWrite, Value: 8},
+		{Name: collectors.NetInterfaceMetric("wlan0", collectors.NetDropsIn), Value: 1},
+	}, nil
+}
+
+func TestNetworkInterfaceSelection(t *testing.T) {
+	preferences := test.NewTempApp(t).Preferences()
+
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+	system.PersistSelections(preferences)
+	system.registry = collectors.NewRegistry()
+	_ = system.RegisterCollector(&networkInterfaceCollector{})
+	system.UpdateSystemStats()
+
+	provider := &NetworkDataProvider{System: system}
+	selector := GetNetworkSelectors(system)[0]
+
+	// The active interface is shown until another option is chosen
+	if selector.GetSelected() != ActiveInterfaceOption {
+		t.Errorf("Expected %q to be selected, got %q", ActiveInterfaceOption, selector.GetSelected())
+	}
+
+	options := selector.GetOptions()
+	if len(options) != 3 || options[1] != AllPhysicalOption || options[2] != "wlan0" {
+		t.Errorf("Expected active, all physical and wlan0 options, got %v", options)
+	}
+
+	selector.OnChanged(AllPhysicalOption)
+	if provider.GetCurrentReadValue() != 7 || provider.GetCurrentWriteValue() != 8 {
+		t.Errorf("Expected aggregate values 7/8, got %f/%f", provider.GetCurrentReadValue(), provider.GetCurrentWriteValue())
+	}
+
+	if provider.GetHighlight() != nil {
+		t.Errorf("Expected no highlight without errors or drops")
+	}
+
+	selector.OnChanged("wlan0")
+	if provider.GetCurrentReadValue() != 5 || provider.GetTitle() != "Net wlan0" {
+		t.Errorf("Expected wlan0 read value 5 and title, got %f %q", provider.GetCurrentReadValue(), provider.GetTitle())
+	}
+	if provider.GetHighlight() != constants.LightColors.Warning {
+		t.Errorf("Expected warning highlight for drops on wlan0")
+	}
+
+	// A new system reading the same preferences restores the choice
+	restarted := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+	restarted.PersistSelections(preferences)
+	if restarted.GetSelection(SelectionNetInterface) != "wlan0" {
+		t.Errorf("Expected persisted selection wlan0, got %q", restarted.GetSelection(SelectionNetInterface))
+	}
+}
+
+func TestHostInfoCache(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+
+	// The host information is collected once and served from the cache afterwards
+	first := system.GetHostInfo()
+	if first.LogicalCores < 1 {
+		t.Errorf("Expected at least one logical core, got %d", first.LogicalCores)
+	}
+	if cached := system.GetHostInfo(); !cached.CollectedAt.Equal(first.CollectedAt) {
+		t.Errorf("Expected the cached host information, collected at %s, got one collected at %s",
+			first.CollectedAt, cached.CollectedAt)
+	}
+
+	// Refreshing collects it again
+	time.Sleep(time.Millisecond)
+	refreshed := system.RefreshHostInfo()
+	if !refreshed.CollectedAt.After(first.CollectedAt) {
+		t.Error("Expected the host information to be collected again")
+	}
+	if cached := system.GetHostInfo(); !cached.CollectedAt.Equal(refreshed.CollectedAt) {
+		t.Error("Expected the refreshed host information to replace the cached one")
+	}
+}
+
+// ownerCollector reports one metric per name under its prefix, like a cgroup or interface collector
+type ownerCollector struct {
+	prefix string
+	names  []string
+}
+
+func (c *ownerCollector) Name() string {
+	return "owner"
+}
+
+func (c *ownerCollector) Interval() time.Duration {
+	return 0
+}
+
+func (c *ownerCollector) MetricPrefix() string {
+	return c.prefix
+}
+
+func (c *ownerCollector) Collect(_ context.Context) ([]collectors.Sample, error) {
+	samples := make([]collectors.Sample, 0, len(c.names))
+	for _, name := range c.names {
+		samples = append(samples, collectors.Sample{Name: c.prefix + name, Value: 1})
+	}
+	return samples, nil
+}
+
+func TestRemovedCgroupsArePruned(t *testing.T) {
+	system := NewMonitorSystem(
+		100.0, // maxNetworkSpeed
+		false, // darkMode
+		constants.LightColors,
+		constants.DarkColors,
+		constants.EmptyRectangle,
+		60, // dataPoints
+	)
+	system.registry = collectors.NewRegistry()
+	owner := &ownerCollector{prefix: collectors.CgroupPrefix, names: []string{"/a.cpu", "/b.cpu"}}
+	_ = system.RegisterCollector(owner)
+	_ = system.RegisterCollector(&constantCollector{value: 1})
+	system.UpdateSystemStats()
+
+	owner.names = []string{"/b.cpu"}
+	system.UpdateSystemStats()
+
+	snapshot := system.GetSnapshot()
+	if snapshot.Has(collectors.CgroupPrefix+"/a.cpu") || snapshot.Value(collectors.CgroupPrefix+"/a.cpu") != 0 {
+		t.Error("Expected the metrics of the removed cgroup to be dropped")
+	}
+	if !snapshot.Has(collectors.CgroupPrefix+"/b.cpu") || !snapshot.Has("constant.value") || !snapshot.Has(collectors.MetricCPUUsage) {
+		t.Errorf("Expected the other metrics to be kept, got %v", snapshot.Names())
+	}
+}
@@ -17,7 +17,9 @@ type DualValueWidget struct {
 	InfoRows        []InfoRow
 	ReadLabel       string
 	WriteLabel      string
-	CompactValueFmt string // Format string for compact view values (e.g. "D:%.1f U:%.1f MB/s")
+	CompactValueFmt string               // Format string for compact view values (e.g. "D:%.1f U:%.1f MB/s")
+	Selectors       []Selector           // Optional choice controls shown above the info rows
+	Extra           []SeriesDataProvider // Optional additional series drawn as lines below the graph in the detailed view
 }
 
 // NewDualValueWidget creates a new DualValueWidget
@@ -62,7 +64,7 @@ func (d *DualValueWidget) CreateCompactView() *fyne.Container {
 		}
 
 		// Add title and subtitle
-		d.AddTitle(graphContainer, d.Provider.GetTitle(), d.TextColor)
+		titleLabel := d.AddTitle(graphContainer, d.Provider.GetTitle(), d.TextColor)
 
 		// Add IO info as subtitle
 		d.AddSubtitle(
@@ -76,8 +78,9 @@ func (d *DualValueWidget) CreateCompactView() *fyne.Container {
 		// Add axis labels
 		d.AddAxisLabels(graphContainer, "0", fmt.Sprintf("%.0f", d.Provider.GetMaxValue()))
 
-		// Add graph border
-		d.AddGraphBorder(graphContainer, containerWidth)
+		// Add graph border, highlighted when the provider flags a problem
+		border := d.AddGraphBorder(graphContainer, containerWidth)
+		d.ApplyHighlight(d.Provider, titleLabel, border)
 
 		// Draw the graph lines for both datasets
 		d.DrawDualGraph(
@@ -110,6 +113,11 @@ func (d *DualValueWidget) CreateDetailedView() *fyne.Container {
 	infoTitle.TextStyle = fyne.TextStyle{Bold: true}
 	infoContainer.Add(infoTitle)
 
+	// Add choice controls
+	for _, selector := range d.Selectors {
+		infoContainer.Add(CreateSelectorRow(selector))
+	}
+
 	// Create info for read/write values
 	var readInfo *widget.Label
 	if len(d.ReadLabel) > 0 {
@@ -153,8 +161,9 @@ func (d *DualValueWidget) CreateDetailedView() *fyne.Container {
 		// Add axis labels
 		d.AddAxisLabels(graphContainer, "0", fmt.Sprintf("%.0f", d.Provider.GetMaxValue()))
 
-		// Add graph border
-		d.AddGraphBorder(graphContainer, containerWidth)
+		// Add graph border, highlighted when the provider flags a problem
+		border := d.AddGraphBorder(graphContainer, containerWidth)
+		d.ApplyHighlight(d.Provider, titleLabel, border)
 
 		// Draw the graph lines for both datasets
 		d.DrawDualGraph(
@@ -185,8 +194,14 @@ func (d *DualValueWidget) CreateDetailedView() *fyne.Container {
 
 	drawGraph()
 
+	// Stack the optional additional graphs below the main one
+	graphColumn := container.NewVBox(graphContainer)
+	for _, extra := range d.Extra {
+		graphColumn.Add(d.CreateLinesContainer(extra))
+	}
+
 	// Create two-column layout
-	mainContainer := container.New(layout.NewGridLayoutWithColumns(2), graphContainer, infoContainer)
+	mainContainer := container.New(layout.NewGridLayoutWithColumns(2), graphColumn, infoContainer)
 
 	return mainContainer
 }
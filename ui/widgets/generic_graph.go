@@ -1,6 +1,7 @@
 package widgets
 
 import (
+	"fmt"
 	"image/color"
 
 	"go-dummy-monitor/constants"
@@ -70,6 +71,18 @@ func (b *GenericGraph) AddGraphBorder(graphContainer *fyne.Container, containerW
 	return border
 }
 
+// ApplyHighlight draws the title and border in the provider highlight color, if it has one
+func (b *GenericGraph) ApplyHighlight(provider any, title *canvas.Text, border *canvas.Rectangle) {
+	highlighter, ok := provider.(HighlightProvider)
+	if !ok {
+		return
+	}
+	if highlight := highlighter.GetHighlight(); highlight != nil {
+		title.Color = highlight
+		border.StrokeColor = highlight
+	}
+}
+
 // AddAxisLabels adds Y axis labels to the graph
 func (b *GenericGraph) AddAxisLabels(graphContainer *fyne.Container, min string, max string) {
 	// When the theme changes, GenericGraph.TextColor gets updated via factory.createBaseGraph()
@@ -180,3 +193,171 @@ func (b *GenericGraph) DrawDualGraph(graphContainer *fyne.Container, primaryData
 		b.DrawLine(graphContainer, x1, y1, x2, y2, secondaryColor, b.StrokeWidth)
 	}
 }
+
+// DrawHeatmap draws one row of cells per series, shading each cell by its value
+func (b *GenericGraph) DrawHeatmap(graphContainer *fyne.Container, rows [][]float64, maxValue float64, color color.Color, containerWidth float32) {
+	if len(rows) == 0 || maxValue <= 0 {
+		return
+	}
+
+	// Cells fill the same area that is framed by AddGraphBorder
+	areaWidth := containerWidth - b.ElementSpacing*2
+	areaHeight := b.GraphPadding * constants.GRAPH_HEIGHT_MULTIPLIER
+	rowHeight := areaHeight / float32(len(rows))
+
+	for r, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		cellWidth := areaWidth / float32(len(row))
+
+		for i, v := range row {
+			cell := canvas.NewRectangle(b.BlendColor(color, v/maxValue))
+			cell.Resize(fyne.NewSize(cellWidth, rowHeight))
+			cell.Move(fyne.NewPos(b.ElementSpacing+cellWidth*float32(i), b.GraphPadding+rowHeight*float32(r)))
+			graphContainer.Add(cell)
+		}
+	}
+}
+
+// BlendColor mixes the background color with the given color, intensity ranges from 0 to 1
+func (b *GenericGraph) BlendColor(c color.Color, intensity float64) color.Color {
+	if intensity < 0 {
+		intensity = 0
+	} else if intensity > 1 {
+		intensity = 1
+	}
+
+	bg := color.RGBAModel.Convert(b.BackgroundColor).(color.RGBA)
+	fg := color.RGBAModel.Convert(c).(color.RGBA)
+	mix := func(from, to uint8) uint8 {
+		return uint8(float64(from) + (float64(to)-float64(from))*intensity)
+	}
+
+	return color.RGBA{
+		R: mix(bg.R, fg.R),
+		G: mix(bg.G, fg.G),
+		B: mix(bg.B, fg.B),
+		A: constants.FULL_ALPHA,
+	}
+}
+
+// DrawStackedGraph draws the series as stacked columns, the first series at the bottom
+func (b *GenericGraph) DrawStackedGraph(graphContainer *fyne.Container, series []Series, maxValue float64, containerWidth float32) {
+	if len(series) == 0 || maxValue <= 0 {
+		return
+	}
+
+	points := 0
+	for _, s := range series {
+		if len(s.Data) > points {
+			points = len(s.Data)
+		}
+	}
+	if points == 0 {
+		return
+	}
+
+	// Columns fill the same area that is framed by AddGraphBorder
+	areaWidth := containerWidth - b.ElementSpacing*2
+	areaHeight := b.GraphPadding * constants.GRAPH_HEIGHT_MULTIPLIER
+	columnWidth := areaWidth / float32(points)
+	bottom := b.GraphPadding + areaHeight
+
+	for i := 0; i < points; i++ {
+		stacked := float32(0)
+		for _, s := range series {
+			if i >= len(s.Data) || s.Data[i] <= 0 {
+				continue
+			}
+
+			height := float32(s.Data[i]/maxValue) * areaHeight
+			if stacked+height > areaHeight {
+				height = areaHeight - stacked
+			}
+			if height <= 0 {
+				continue
+			}
+
+			segment := canvas.NewRectangle(s.Color)
+			segment.Resize(fyne.NewSize(columnWidth, height))
+			segment.Move(fyne.NewPos(b.ElementSpacing+columnWidth*float32(i), bottom-stacked-height))
+			graphContainer.Add(segment)
+
+			stacked += height
+		}
+	}
+}
+
+// DrawMultiGraph draws every series as its own line, scaled to a shared maximum
+func (b *GenericGraph) DrawMultiGraph(graphContainer *fyne.Container, series []Series, maxValue float64, containerWidth float32) {
+	dataMax := seriesMax(series, maxValue)
+	for _, s := range series {
+		if len(s.Data) > 1 {
+			b.DrawSingleGraph(graphContainer, s.Data, dataMax, s.Color, containerWidth)
+		}
+	}
+}
+
+// CreateStackedContainer creates a graph container showing the provider series stacked, with a legend
+func (b *GenericGraph) CreateStackedContainer(provider SeriesDataProvider) *fyne.Container {
+	return b.createSeriesContainer(provider, provider.GetMaxValue(), b.DrawStackedGraph)
+}
+
+// CreateLinesContainer creates a graph container showing the provider series as lines, with a legend
+func (b *GenericGraph) CreateLinesContainer(provider SeriesDataProvider) *fyne.Container {
+	series := provider.GetSeries()
+	return b.createSeriesContainer(provider, seriesMax(series, provider.GetMaxValue()), b.DrawMultiGraph)
+}
+
+// createSeriesContainer lays out a titled graph of several series with a legend below it
+func (b *GenericGraph) createSeriesContainer(
+	provider SeriesDataProvider,
+	axisMax float64,
+	draw func(*fyne.Container, []Series, float64, float32),
+) *fyne.Container {
+	graphContainer, graphBg := b.CreateGraphContainer()
+	series := provider.GetSeries()
+
+	containerWidth := graphBg.Size().Width
+	if containerWidth < b.GraphWidth {
+		containerWidth = b.GraphWidth
+	}
+
+	b.AddTitle(graphContainer, provider.GetTitle(), b.TextColor)
+	b.AddAxisLabels(graphContainer, "0", fmt.Sprintf("%.0f", axisMax))
+	draw(graphContainer, series, provider.GetMaxValue(), containerWidth)
+	b.AddGraphBorder(graphContainer, containerWidth)
+
+	// Legend entries are laid out in a row below the graph area
+	legendX := b.ElementSpacing
+	legendY := b.GraphPadding*(1+constants.GRAPH_HEIGHT_MULTIPLIER) + b.ElementSpacing/2
+	for _, s := range series {
+		legend := canvas.NewText(s.Label, s.Color)
+		legend.TextSize = constants.SMALL_TEXT_SIZE
+		legend.Move(fyne.NewPos(legendX, legendY))
+		graphContainer.Add(legend)
+
+		legendX += legend.MinSize().Width + b.ElementSpacing
+		if legendX > containerWidth-b.ElementSpacing {
+			legendX = b.ElementSpacing
+			legendY += constants.SMALL_TEXT_SIZE + constants.TEXT_PADDING
+		}
+	}
+
+	canvas.Refresh(graphContainer)
+	return graphContainer
+}
+
+// seriesMax returns the larger of maxValue and the highest value across all series
+func seriesMax(series []Series, maxValue float64) float64 {
+	dataMax := maxValue
+	for _, s := range series {
+		for _, v := range s.Data {
+			if v > dataMax {
+				dataMax = v
+			}
+		}
+	}
+	return dataMax
+}
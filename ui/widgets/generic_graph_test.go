@@ -1,6 +1,7 @@
 package widgets
 
 import (
+	"image/color"
 	"testing"
 
 	"go-dummy-monitor/constants"
@@ -151,3 +152,158 @@ func TestDrawDualGraph(t *testing.T) {
 	secondaryData := []float64{5, 15, 25, 35, 45}
 	graph.DrawDualGraph(container, primaryData, secondaryData, 100, constants.LightColors.Grid, constants.LightColors.Grid, float32(constants.GRAPH_WIDTH))
 }
+
+func TestDrawHeatmap(t *testing.T) {
+	graph := NewGenericGraph(
+		constants.GRAPH_WIDTH,
+		constants.GRAPH_HEIGHT,
+		constants.GRAPH_PADDING,
+		constants.ELEMENT_SPACING,
+		constants.LABEL_HEIGHT,
+		constants.LightColors.BG,
+		constants.LightColors.Grid,
+		constants.LightColors.Text,
+		constants.STROKE_WIDTH,
+		constants.EmptyRectangle,
+		constants.TRANSLUCENT_ALPHA,
+	)
+
+	container, _ := graph.CreateGraphContainer()
+	rows := [][]float64{
+		{10, 20, 30},
+		{90, 80, 70},
+	}
+	graph.DrawHeatmap(container, rows, 100, constants.LightColors.CPU, float32(constants.GRAPH_WIDTH))
+
+	// Background plus one cell per data point
+	if len(container.Objects) != 1+6 {
+		t.Errorf("Expected 7 objects in container, got %d", len(container.Objects))
+	}
+}
+
+func TestBlendColor(t *testing.T) {
+	graph := NewGenericGraph(
+		constants.GRAPH_WIDTH,
+		constants.GRAPH_HEIGHT,
+		constants.GRAPH_PADDING,
+		constants.ELEMENT_SPACING,
+		constants.LABEL_HEIGHT,
+		constants.LightColors.BG,
+		constants.LightColors.Grid,
+		constants.LightColors.Text,
+		constants.STROKE_WIDTH,
+		constants.EmptyRectangle,
+		constants.TRANSLUCENT_ALPHA,
+	)
+
+	if graph.BlendColor(constants.LightColors.CPU, 0) != constants.LightColors.BG {
+		t.Error("Expected zero intensity to produce the background color")
+	}
+	if graph.BlendColor(constants.LightColors.CPU, 1) != constants.LightColors.CPU {
+		t.Error("Expected full intensity to produce the series color")
+	}
+	if graph.BlendColor(constants.LightColors.CPU, 2) != constants.LightColors.CPU {
+		t.Error("Expected intensity above 1 to be clamped")
+	}
+}
+
+func TestDrawStackedGraph(t *testing.T) {
+	graph := NewGenericGraph(
+		constants.GRAPH_WIDTH,
+		constants.GRAPH_HEIGHT,
+		constants.GRAPH_PADDING,
+		constants.ELEMENT_SPACING,
+		constants.LABEL_HEIGHT,
+		constants.LightColors.BG,
+		constants.LightColors.Grid,
+		constants.LightColors.Text,
+		constants.STROKE_WIDTH,
+		constants.EmptyRectangle,
+		constants.TRANSLUCENT_ALPHA,
+	)
+
+	container, _ := graph.CreateGraphContainer()
+	series := []Series{
+		{Label: "user", Data: []float64{10, 20, 0}, Color: constants.LightColors.CPU},
+		{Label: "sys", Data: []float64{5, 0, 5}, Color: constants.LightColors.RAM},
+	}
+	graph.DrawStackedGraph(container, series, 100, float32(constants.GRAPH_WIDTH))
+
+	// Background plus one segment per non-zero value
+	if len(container.Objects) != 1+4 {
+		t.Errorf("Expected 5 objects in container, got %d", len(container.Objects))
+	}
+}
+
+func TestDrawMultiGraph(t *testing.T) {
+	graph := NewGenericGraph(
+		constants.GRAPH_WIDTH,
+		constants.GRAPH_HEIGHT,
+		constants.GRAPH_PADDING,
+		constants.ELEMENT_SPACING,
+		constants.LABEL_HEIGHT,
+		constants.LightColors.BG,
+		constants.LightColors.Grid,
+		constants.LightColors.Text,
+		constants.STROKE_WIDTH,
+		constants.EmptyRectangle,
+		constants.TRANSLUCENT_ALPHA,
+	)
+
+	container, _ := graph.CreateGraphContainer()
+	series := []Series{
+		{Label: "read", Data: []float64{10, 20, 30}, Color: constants.LightColors.DISK},
+		{Label: "write", Data: []float64{5, 250, 5}, Color: constants.LightColors.Warning},
+		{Label: "empty", Data: []float64{1}, Color: constants.LightColors.NET},
+	}
+	graph.DrawMultiGraph(container, series, 100, float32(constants.GRAPH_WIDTH))
+
+	// Background plus two lines for each series with more than one point
+	if len(container.Objects) != 1+4 {
+		t.Errorf("Expected 5 objects in container, got %d", len(container.Objects))
+	}
+
+	if got := seriesMax(series, 100); got != 250 {
+		t.Errorf("Expected max value 250, got %f", got)
+	}
+}
+
+type highlightProvider struct {
+	highlight color.Color
+}
+
+func (h *highlightProvider) GetHighlight() color.Color {
+	return h.highlight
+}
+
+func TestApplyHighlight(t *testing.T) {
+	graph := NewGenericGraph(
+		constants.GRAPH_WIDTH,
+		constants.GRAPH_HEIGHT,
+		constants.GRAPH_PADDING,
+		constants.ELEMENT_SPACING,
+		constants.LABEL_HEIGHT,
+		constants.LightColors.BG,
+		constants.LightColors.Grid,
+		constants.LightColors.Text,
+		constants.STROKE_WIDTH,
+		constants.EmptyRectangle,
+		constants.TRANSLUCENT_ALPHA,
+	)
+
+	container, _ := graph.CreateGraphContainer()
+	title := graph.AddTitle(container, "Net", graph.TextColor)
+	border := graph.AddGraphBorder(container, float32(constants.GRAPH_WIDTH))
+
+	// Providers without a highlight keep the default colors
+	graph.ApplyHighlight(&highlightProvider{}, title, border)
+	graph.ApplyHighlight("not a provider", title, border)
+	if title.Color != graph.TextColor || border.StrokeColor != graph.GridColor {
+		t.Errorf("Expected default colors without a highlight")
+	}
+
+	graph.ApplyHighlight(&highlightProvider{highlight: constants.LightColors.Error}, title, border)
+	if title.Color != constants.LightColors.Error || border.StrokeColor != constants.LightColors.Error {
+		t.Errorf("Expected title and border in the highlight color")
+	}
+}
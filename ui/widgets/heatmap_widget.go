@@ -0,0 +1,129 @@
+package widgets
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// HeatmapWidget is a widget that displays several series as a row-by-time heatmap
+type HeatmapWidget struct {
+	GenericGraph
+	Provider HeatmapDataProvider
+	InfoRows []InfoRow
+}
+
+// NewHeatmapWidget creates a new HeatmapWidget
+func NewHeatmapWidget(baseGraph GenericGraph, provider HeatmapDataProvider, infoRows []InfoRow) *HeatmapWidget {
+	return &HeatmapWidget{
+		GenericGraph: baseGraph,
+		Provider:     provider,
+		InfoRows:     infoRows,
+	}
+}
+
+// CreateViewWithOptions creates a view with optional details
+func (h *HeatmapWidget) CreateViewWithOptions(showDetails bool) *fyne.Container {
+	if showDetails {
+		return h.CreateDetailedView()
+	}
+	return h.CreateCompactView()
+}
+
+// CreateCompactView creates a compact view of the widget
+func (h *HeatmapWidget) CreateCompactView() *fyne.Container {
+	graphContainer, graphBg := h.CreateGraphContainer()
+
+	// Draw the actual heatmap
+	drawGraph := func() {
+		graphContainer.Objects = []fyne.CanvasObject{graphBg}
+
+		// Get container width for responsive layout
+		containerWidth := graphBg.Size().Width
+		if containerWidth < h.GraphWidth {
+			containerWidth = h.GraphWidth
+		}
+
+		rows := h.Provider.GetRows()
+
+		// Add header with the number of series
+		h.AddTitle(graphContainer, fmt.Sprintf("%s: %d", h.Provider.GetTitle(), len(rows)), h.TextColor)
+
+		// Draw the cells first so the border stays visible on top
+		h.DrawHeatmap(graphContainer, rows, h.Provider.GetMaxValue(), h.Provider.GetColor(), containerWidth)
+
+		// Add graph border
+		h.AddGraphBorder(graphContainer, containerWidth)
+
+		canvas.Refresh(graphContainer)
+	}
+
+	drawGraph()
+	return graphContainer
+}
+
+// CreateDetailedView creates a detailed view of the widget
+func (h *HeatmapWidget) CreateDetailedView() *fyne.Container {
+	// Create left column for graph visualization
+	graphContainer, graphBg := h.CreateGraphContainer()
+
+	// Create right column for info display
+	infoContainer := container.NewVBox()
+
+	// Info content
+	infoTitle := widget.NewLabel(fmt.Sprintf("%s INFO", h.Provider.GetTitle()))
+	infoTitle.TextStyle = fyne.TextStyle{Bold: true}
+	infoContainer.Add(infoTitle)
+
+	// Create dynamic info rows
+	infoLabels := make([]*widget.Label, len(h.InfoRows))
+	for i, row := range h.InfoRows {
+		infoLabels[i] = widget.NewLabel(fmt.Sprintf("%s: %s", row.Label, row.GetValue()))
+		infoContainer.Add(infoLabels[i])
+	}
+
+	// Draw the actual heatmap
+	drawGraph := func() {
+		graphContainer.Objects = []fyne.CanvasObject{graphBg}
+
+		// Get container width for responsive layout
+		containerWidth := graphBg.Size().Width
+		if containerWidth < h.GraphWidth {
+			containerWidth = h.GraphWidth
+		}
+
+		// Add header information
+		titleLabel := h.AddTitle(graphContainer, h.Provider.GetTitle(), h.TextColor)
+		titleLabel.Move(fyne.NewPos(h.ElementSpacing, h.ElementSpacing))
+
+		// Add axis labels for the first and last series
+		rows := h.Provider.GetRows()
+		if len(rows) > 0 {
+			h.AddAxisLabels(graphContainer, fmt.Sprintf("%d", len(rows)-1), "0")
+		}
+
+		// Draw the cells first so the border stays visible on top
+		h.DrawHeatmap(graphContainer, rows, h.Provider.GetMaxValue(), h.Provider.GetColor(), containerWidth)
+
+		// Add graph border
+		h.AddGraphBorder(graphContainer, containerWidth)
+
+		// Update info rows
+		for i, row := range h.InfoRows {
+			infoLabels[i].SetText(fmt.Sprintf("%s: %s", row.Label, row.GetValue()))
+		}
+
+		canvas.Refresh(graphContainer)
+	}
+
+	drawGraph()
+
+	// Create two-column layout
+	mainContainer := container.New(layout.NewGridLayoutWithColumns(2), graphContainer, infoContainer)
+
+	return mainContainer
+}
@@ -26,6 +26,41 @@ type DualGraphDataProvider interface {
 	GetColor() color.Color
 }
 
+// HighlightProvider is optionally implemented by data providers that can flag a problem
+type HighlightProvider interface {
+	// GetHighlight returns the color to draw the title and border in, or nil when nothing is wrong
+	GetHighlight() color.Color
+}
+
+// HeatmapDataProvider is an interface for components that provide several series to be drawn as a heatmap
+type HeatmapDataProvider interface {
+	GetRows() [][]float64
+	GetMaxValue() float64
+	GetTitle() string
+	GetColor() color.Color
+}
+
+// TableDataProvider is an interface for components that provide rows of text to be shown as a table
+type TableDataProvider interface {
+	GetHeaders() []string
+	GetRows() [][]string
+	GetTitle() string
+}
+
+// Series is a labelled set of data points drawn in its own color
+type Series struct {
+	Label string
+	Data  []float64
+	Color color.Color
+}
+
+// SeriesDataProvider is an interface for components that provide several series to be drawn in one graph
+type SeriesDataProvider interface {
+	GetSeries() []Series
+	GetMaxValue() float64
+	GetTitle() string
+}
+
 // MonitorWidget is an interface for all monitoring widgets
 type MonitorWidget interface {
 	CreateDetailedView() *fyne.Container
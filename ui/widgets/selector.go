@@ -0,0 +1,75 @@
+package widgets
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Selector represents a choice control shown in the detailed view
+type Selector struct {
+	Label       string
+	GetOptions  func() []string
+	GetSelected func() string
+	OnChanged   func(string)
+}
+
+// CreateSelectorRow creates a labelled drop-down for the selector
+func CreateSelectorRow(selector Selector) fyne.CanvasObject {
+	selected := selector.GetSelected()
+	options := selector.GetOptions()
+
+	choice := widget.NewSelect(options, nil)
+	choice.SetSelected(selected)
+
+	// Attach the handler after the initial selection so rebuilding the view does not fire it
+	choice.OnChanged = func(value string) {
+		if value != selected {
+			selected = value
+			selector.OnChanged(value)
+		}
+	}
+
+	label := widget.NewLabel(selector.Label + ":")
+	return container.New(layout.NewBorderLayout(nil, nil, label, nil), label, choice)
+}
+
+// TextInput represents a free text control shown in the detailed view
+type TextInput struct {
+	Label       string
+	Placeholder string
+	GetText     func() string
+	OnChanged   func(string)
+}
+
+// NewTextInputEntry creates the entry of a text input; views are rebuilt on every update,
+// so the entry must be created once and reused to keep focus and cursor while typing
+func NewTextInputEntry(input TextInput) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(input.Placeholder)
+	entry.SetText(input.GetText())
+	entry.OnChanged = input.OnChanged
+	return entry
+}
+
+// CreateTextInputRow creates a labelled row around an entry made by NewTextInputEntry
+func CreateTextInputRow(input TextInput, entry *widget.Entry) fyne.CanvasObject {
+	label := widget.NewLabel(input.Label + ":")
+	return container.New(layout.NewBorderLayout(nil, nil, label, nil), label, entry)
+}
+
+// Button represents a push button shown above a widget, e.g. to reload what it shows
+type Button struct {
+	Label    string
+	OnTapped func()
+}
+
+// CreateButtonRow creates a row of the buttons aligned to the left
+func CreateButtonRow(buttons []Button) fyne.CanvasObject {
+	row := container.NewHBox()
+	for _, button := range buttons {
+		row.Add(widget.NewButton(button.Label, button.OnTapped))
+	}
+	return row
+}
@@ -13,8 +13,11 @@ import (
 // SingleValueWidget is a widget that displays a single value graph
 type SingleValueWidget struct {
 	GenericGraph
-	Provider GraphDataProvider
-	InfoRows []InfoRow
+	Provider  GraphDataProvider
+	InfoRows  []InfoRow
+	Stacked   SeriesDataProvider   // Optional breakdown drawn stacked below the graph in the detailed view
+	Extra     []SeriesDataProvider // Optional additional series drawn as lines below the graph in the detailed view
+	Selectors []Selector           // Optional choice controls shown above the info rows
 }
 
 // InfoRow represents a row of information in the detailed view
@@ -55,14 +58,15 @@ func (s *SingleValueWidget) CreateCompactView() *fyne.Container {
 		}
 
 		// Add header with combined info
-		s.AddTitle(graphContainer, fmt.Sprintf("%s: %.1f",
+		titleLabel := s.AddTitle(graphContainer, fmt.Sprintf("%s: %.1f",
 			s.Provider.GetTitle(), s.Provider.GetCurrentValue()), s.TextColor)
 
 		// Add axis labels
 		s.AddAxisLabels(graphContainer, "0", fmt.Sprintf("%.0f", s.Provider.GetMaxValue()))
 
-		// Add graph border
-		s.AddGraphBorder(graphContainer, containerWidth)
+		// Add graph border, highlighted when the provider flags a problem
+		border := s.AddGraphBorder(graphContainer, containerWidth)
+		s.ApplyHighlight(s.Provider, titleLabel, border)
 
 		// Draw the graph lines
 		s.DrawSingleGraph(
@@ -93,6 +97,11 @@ func (s *SingleValueWidget) CreateDetailedView() *fyne.Container {
 	infoTitle.TextStyle = fyne.TextStyle{Bold: true}
 	infoContainer.Add(infoTitle)
 
+	// Add choice controls
+	for _, selector := range s.Selectors {
+		infoContainer.Add(CreateSelectorRow(selector))
+	}
+
 	// Create dynamic info rows
 	infoLabels := make([]*widget.Label, len(s.InfoRows))
 	for i, row := range s.InfoRows {
@@ -121,8 +130,9 @@ func (s *SingleValueWidget) CreateDetailedView() *fyne.Container {
 		// Add axis labels
 		s.AddAxisLabels(graphContainer, "0", fmt.Sprintf("%.0f", s.Provider.GetMaxValue()))
 
-		// Add graph border
-		s.AddGraphBorder(graphContainer, containerWidth)
+		// Add graph border, highlighted when the provider flags a problem
+		border := s.AddGraphBorder(graphContainer, containerWidth)
+		s.ApplyHighlight(s.Provider, titleLabel, border)
 
 		// Draw the graph lines
 		s.DrawSingleGraph(
@@ -143,8 +153,17 @@ func (s *SingleValueWidget) CreateDetailedView() *fyne.Container {
 
 	drawGraph()
 
+	// Stack the optional breakdown and additional graphs below the main one
+	graphColumn := container.NewVBox(graphContainer)
+	if s.Stacked != nil {
+		graphColumn.Add(s.CreateStackedContainer(s.Stacked))
+	}
+	for _, extra := range s.Extra {
+		graphColumn.Add(s.CreateLinesContainer(extra))
+	}
+
 	// Create two-column layout
-	mainContainer := container.New(layout.NewGridLayoutWithColumns(2), graphContainer, infoContainer)
+	mainContainer := container.New(layout.NewGridLayoutWithColumns(2), graphColumn, infoContainer)
 
 	return mainContainer
 }
@@ -0,0 +1,141 @@
+package widgets
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// RowAction is an entry of the menu opened from a table row
+type RowAction struct {
+	Label      string
+	OnSelected func(row []string)
+}
+
+// TableWidget is a widget that displays rows of text under column headers
+type TableWidget struct {
+	Provider       TableDataProvider
+	CompactColumns []int       // Columns shown in the compact view, all columns when empty
+	Selectors      []Selector  // Optional choice controls shown above the table
+	Inputs         []TextInput // Optional free text controls shown above the table, e.g. a filter
+	RowActions     []RowAction // Optional actions offered in a menu at the end of every row
+	Buttons        []Button    // Optional buttons shown above the table
+	entries        []*widget.Entry
+}
+
+// NewTableWidget creates a new TableWidget
+func NewTableWidget(provider TableDataProvider, compactColumns []int) *TableWidget {
+	return &TableWidget{
+		Provider:       provider,
+		CompactColumns: compactColumns,
+	}
+}
+
+// CreateViewWithOptions creates a view with optional details
+func (t *TableWidget) CreateViewWithOptions(showDetails bool) *fyne.Container {
+	if showDetails {
+		return t.CreateDetailedView()
+	}
+	return t.CreateCompactView()
+}
+
+// CreateCompactView creates a compact view with only the compact columns
+func (t *TableWidget) CreateCompactView() *fyne.Container {
+	return t.createTable(t.CompactColumns)
+}
+
+// CreateDetailedView creates a detailed view with all columns
+func (t *TableWidget) CreateDetailedView() *fyne.Container {
+	return t.createTable(nil)
+}
+
+// createTable lays out the title, selectors and a grid of the chosen columns
+func (t *TableWidget) createTable(columns []int) *fyne.Container {
+	headers := t.Provider.GetHeaders()
+	if len(columns) == 0 {
+		columns = make([]int, len(headers))
+		for i := range headers {
+			columns[i] = i
+		}
+	}
+
+	tableContainer := container.NewVBox()
+
+	title := widget.NewLabel(fmt.Sprintf("%s INFO", t.Provider.GetTitle()))
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	tableContainer.Add(title)
+
+	if len(t.Buttons) > 0 {
+		tableContainer.Add(CreateButtonRow(t.Buttons))
+	}
+	for _, selector := range t.Selectors {
+		tableContainer.Add(CreateSelectorRow(selector))
+	}
+
+	// The entries outlive the rebuilt views so typing is not interrupted by updates
+	if t.entries == nil {
+		for _, input := range t.Inputs {
+			t.entries = append(t.entries, NewTextInputEntry(input))
+		}
+	}
+	for i, input := range t.Inputs {
+		tableContainer.Add(CreateTextInputRow(input, t.entries[i]))
+	}
+
+	gridColumns := len(columns)
+	if len(t.RowActions) > 0 {
+		gridColumns++
+	}
+	grid := container.NewGridWithColumns(gridColumns)
+	for _, column := range columns {
+		header := widget.NewLabel(headers[column])
+		header.TextStyle = fyne.TextStyle{Bold: true}
+		grid.Add(header)
+	}
+	if len(t.RowActions) > 0 {
+		grid.Add(widget.NewLabel(""))
+	}
+	for _, row := range t.Provider.GetRows() {
+		for _, column := range columns {
+			value := ""
+			if column < len(row) {
+				value = row[column]
+			}
+			cell := widget.NewLabel(value)
+			cell.Truncation = fyne.TextTruncateEllipsis
+			grid.Add(cell)
+		}
+		if len(t.RowActions) > 0 {
+			grid.Add(t.createRowActionsButton(row))
+		}
+	}
+	tableContainer.Add(grid)
+
+	return tableContainer
+}
+
+// createRowActionsButton creates a button opening the row actions menu below itself
+func (t *TableWidget) createRowActionsButton(row []string) fyne.CanvasObject {
+	var button *widget.Button
+	button = widget.NewButtonWithIcon("", theme.MoreHorizontalIcon(), func() {
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(button)
+		if canvas == nil {
+			return
+		}
+		widget.ShowPopUpMenuAtRelativePosition(rowActionsMenu(t.RowActions, row), canvas, fyne.NewPos(0, button.Size().Height), button)
+	})
+	return button
+}
+
+// rowActionsMenu creates the menu of row actions bound to one row
+func rowActionsMenu(actions []RowAction, row []string) *fyne.Menu {
+	items := make([]*fyne.MenuItem, len(actions))
+	for i, action := range actions {
+		onSelected := action.OnSelected
+		items[i] = fyne.NewMenuItem(action.Label, func() { onSelected(row) })
+	}
+	return fyne.NewMenu("", items...)
+}
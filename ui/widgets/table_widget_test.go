@@ -0,0 +1,132 @@
+package widgets
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+type staticTable struct{}
+
+func (s *staticTable) GetHeaders() []string {
+	return []string{"Name", "Value", "Extra"}
+}
+
+func (s *staticTable) GetRows() [][]string {
+	return [][]string{
+		{"a", "1", "x"},
+		{"b", "2"},
+	}
+}
+
+func (s *staticTable) GetTitle() string {
+	return "Static"
+}
+
+func TestTableWidgetViews(t *testing.T) {
+	test.NewApp()
+	defer test.NewApp()
+
+	table := NewTableWidget(&staticTable{}, []int{0, 1})
+
+	detailed := table.CreateDetailedView()
+	if detailed == nil {
+		t.Fatal("Expected non-nil detailed view")
+	}
+	// Title followed by the grid of headers and cells
+	if len(detailed.Objects) != 2 {
+		t.Fatalf("Expected 2 objects in detailed view, got %d", len(detailed.Objects))
+	}
+}
+
+func TestTableWidgetInputs(t *testing.T) {
+	test.NewApp()
+	defer test.NewApp()
+
+	filter := "a"
+	table := NewTableWidget(&staticTable{}, nil)
+	table.Inputs = []TextInput{
+		{
+			Label:     "Filter",
+			GetText:   func() string { return filter },
+			OnChanged: func(value string) { filter = value },
+		},
+	}
+
+	// Title, the input row and the grid
+	first := table.CreateDetailedView()
+	if len(first.Objects) != 3 {
+		t.Fatalf("Expected 3 objects in detailed view, got %d", len(first.Objects))
+	}
+	if table.entries[0].Text != "a" {
+		t.Errorf("Expected the entry to start with the current text, got %q", table.entries[0].Text)
+	}
+
+	// Rebuilding the view keeps the same entry so typing is not interrupted
+	entry := table.entries[0]
+	table.CreateDetailedView()
+	if table.entries[0] != entry {
+		t.Error("Expected the entry to be reused across views")
+	}
+
+	entry.SetText("b")
+	if filter != "b" {
+		t.Errorf("Expected the filter to follow the entry, got %q", filter)
+	}
+}
+
+func TestTableWidgetRowActions(t *testing.T) {
+	test.NewApp()
+	defer test.NewApp()
+
+	var selected []string
+	table := NewTableWidget(&staticTable{}, []int{0, 1})
+	table.RowActions = []RowAction{
+		{Label: "Inspect", OnSelected: func(row []string) { selected = row }},
+	}
+
+	// The actions column follows the shown columns
+	view := table.CreateCompactView()
+	grid := view.Objects[len(view.Objects)-1].(*fyne.Container)
+	if len(grid.Objects) != 3*3 {
+		t.Fatalf("Expected a header and 2 rows of 3 cells, got %d cells", len(grid.Objects))
+	}
+
+	menu := rowActionsMenu(table.RowActions, []string{"b", "2"})
+	if len(menu.Items) != 1 || menu.Items[0].Label != "Inspect" {
+		t.Fatalf("Expected one Inspect item, got %+v", menu.Items)
+	}
+	menu.Items[0].Action()
+	if len(selected) != 2 || selected[0] != "b" {
+		t.Errorf("Expected the action to receive its row, got %v", selected)
+	}
+}
+
+func TestTableWidgetButtons(t *testing.T) {
+	test.NewApp()
+	defer test.NewApp()
+
+	refreshed := 0
+	table := NewTableWidget(&staticTable{}, nil)
+	table.Buttons = []Button{{Label: "Refresh", OnTapped: func() { refreshed++ }}}
+
+	// Title, the button row and the grid
+	view := table.CreateCompactView()
+	if len(view.Objects) != 3 {
+		t.Fatalf("Expected 3 objects in compact view, got %d", len(view.Objects))
+	}
+	row, ok := view.Objects[1].(*fyne.Container)
+	if !ok || len(row.Objects) != 1 {
+		t.Fatalf("Expected a row with one button, got %T", view.Objects[1])
+	}
+	button, ok := row.Objects[0].(*widget.Button)
+	if !ok || button.Text != "Refresh" {
+		t.Fatalf("Expected the Refresh button, got %T", row.Objects[0])
+	}
+	test.Tap(button)
+	if refreshed != 1 {
+		t.Errorf("Expected the button to call its handler once, got %d", refreshed)
+	}
+}
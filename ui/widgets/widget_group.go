@@ -0,0 +1,45 @@
+package widgets
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+)
+
+// WidgetGroup shows several widgets as one panel entry, one below the other
+type WidgetGroup struct {
+	Widgets []MonitorWidget
+}
+
+// NewWidgetGroup creates a new WidgetGroup
+func NewWidgetGroup(widgets ...MonitorWidget) *WidgetGroup {
+	return &WidgetGroup{
+		Widgets: widgets,
+	}
+}
+
+// CreateViewWithOptions creates a view with optional details
+func (g *WidgetGroup) CreateViewWithOptions(showDetails bool) *fyne.Container {
+	if showDetails {
+		return g.CreateDetailedView()
+	}
+	return g.CreateCompactView()
+}
+
+// CreateCompactView creates the compact views of all widgets
+func (g *WidgetGroup) CreateCompactView() *fyne.Container {
+	return g.createGroup(false)
+}
+
+// CreateDetailedView creates the detailed views of all widgets
+func (g *WidgetGroup) CreateDetailedView() *fyne.Container {
+	return g.createGroup(true)
+}
+
+// createGroup stacks the views of the widgets
+func (g *WidgetGroup) createGroup(showDetails bool) *fyne.Container {
+	group := container.NewVBox()
+	for _, widget := range g.Widgets {
+		group.Add(widget.CreateViewWithOptions(showDetails))
+	}
+	return group
+}
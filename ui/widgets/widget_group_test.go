@@ -0,0 +1,28 @@
+package widgets
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestWidgetGroupViews(t *testing.T) {
+	test.NewApp()
+	defer test.NewApp()
+
+	group := NewWidgetGroup(
+		NewTableWidget(&staticTable{}, []int{0}),
+		NewTableWidget(&staticTable{}, nil),
+	)
+
+	for _, showDetails := range []bool{false, true} {
+		view := group.CreateViewWithOptions(showDetails)
+		if len(view.Objects) != 2 {
+			t.Errorf("Expected one entry per widget with details %v, got %d", showDetails, len(view.Objects))
+		}
+	}
+
+	if empty := NewWidgetGroup().CreateDetailedView(); len(empty.Objects) != 0 {
+		t.Errorf("Expected an empty group to have no entries, got %d", len(empty.Objects))
+	}
+}
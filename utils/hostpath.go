@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Environment variables locating the host filesystems when monitoring a host from a container.
+// gopsutil reads the same variables, so setting them redirects every collector at once.
+const (
+	EnvHostRoot = "HOST_ROOT"
+	EnvHostProc = "HOST_PROC"
+	EnvHostSys  = "HOST_SYS"
+	EnvHostEtc  = "HOST_ETC"
+	EnvHostVar  = "HOST_VAR"
+	EnvHostRun  = "HOST_RUN"
+)
+
+// hostDirs maps the variables set by SetHostRoot to the directory each one points at below the root
+var hostDirs = map[string]string{
+	EnvHostProc: "proc",
+	EnvHostSys:  "sys",
+	EnvHostEtc:  "etc",
+	EnvHostVar:  "var",
+	EnvHostRun:  "run",
+}
+
+// HostRoot returns the path of the host root filesystem joined with elem, "/" unless HOST_ROOT is set
+func HostRoot(elem ...string) string {
+	return hostPath(EnvHostRoot, "/", elem)
+}
+
+// HostProc returns the path of the host procfs joined with elem, "/proc" unless HOST_PROC is set
+func HostProc(elem ...string) string {
+	return hostPath(EnvHostProc, "/proc", elem)
+}
+
+// HostSys returns the path of the host sysfs joined with elem, "/sys" unless HOST_SYS is set
+func HostSys(elem ...string) string {
+	return hostPath(EnvHostSys, "/sys", elem)
+}
+
+// hostPath joins elem to the directory named by the environment variable, or to fallback
+func hostPath(env, fallback string, elem []string) string {
+	base := os.Getenv(env)
+	if base == "" {
+		base = fallback
+	}
+	return filepath.Join(append([]string{base}, elem...)...)
+}
+
+// SetHostRoot points every host filesystem below root, e.g. /host with the host's / bind-mounted there.
+// Variables already set in the environment are more specific and keep their value.
+func SetHostRoot(root string) error {
+	if err := os.Setenv(EnvHostRoot, root); err != nil {
+		return err
+	}
+	for env, dir := range hostDirs {
+		if os.Getenv(env) != "" {
+			continue
+		}
+		if err := os.Setenv(env, filepath.Join(root, dir)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SharesHostPIDs reports whether the PIDs read from the host procfs name the same processes for this
+// process, as with the local procfs or in a container sharing the host PID namespace. Signalled from
+// another namespace, such a PID reaches an unrelated process or none at all.
+func SharesHostPIDs() bool {
+	if os.Getenv(EnvHostProc) == "" {
+		return true
+	}
+	// The procfs resolves self to the PID of the reader as seen in its own namespace
+	self, err := os.Readlink(HostProc("self"))
+	return err == nil && self == strconv.Itoa(os.Getpid())
+}
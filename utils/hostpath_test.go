@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// clearHostEnv unsets the host path variables for the test, restoring them afterwards
+func clearHostEnv(t *testing.T) {
+	t.Helper()
+	for _, env := range []string{EnvHostRoot, EnvHostProc, EnvHostSys, EnvHostEtc, EnvHostVar, EnvHostRun} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestHostPathDefaults(t *testing.T) {
+	clearHostEnv(t)
+
+	if got := HostRoot(); got != "/" {
+		t.Errorf("Expected /, got %q", got)
+	}
+	if got := HostProc("net", "route"); got != "/proc/net/route" {
+		t.Errorf("Expected /proc/net/route, got %q", got)
+	}
+	if got := HostSys("class", "net"); got != "/sys/class/net" {
+		t.Errorf("Expected /sys/class/net, got %q", got)
+	}
+}
+
+func TestSetHostRoot(t *testing.T) {
+	clearHostEnv(t)
+	// A variable set explicitly is more specific than the root and is kept
+	t.Setenv(EnvHostSys, "/custom/sys")
+
+	if err := SetHostRoot("/host"); err != nil {
+		t.Fatalf("Expected the host root to be set, got %v", err)
+	}
+	if got := HostRoot("home"); got != "/host/home" {
+		t.Errorf("Expected /host/home, got %q", got)
+	}
+	if got := HostProc("stat"); got != "/host/proc/stat" {
+		t.Errorf("Expected /host/proc/stat, got %q", got)
+	}
+	if got := HostSys("block"); got != "/custom/sys/block" {
+		t.Errorf("Expected /custom/sys/block, got %q", got)
+	}
+	// gopsutil reads these directly
+	if got := os.Getenv(EnvHostEtc); got != "/host/etc" {
+		t.Errorf("Expected HOST_ETC to be /host/etc, got %q", got)
+	}
+}
+
+func TestSharesHostPIDs(t *testing.T) {
+	clearHostEnv(t)
+	if !SharesHostPIDs() {
+		t.Error("Expected the local procfs to share the PIDs")
+	}
+
+	// A procfs of another PID namespace resolves self to another PID
+	root := t.TempDir()
+	if err := os.Symlink("4242", filepath.Join(root, "self")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvHostProc, root)
+	if SharesHostPIDs() {
+		t.Error("Expected a procfs resolving self to another PID not to share the PIDs")
+	}
+
+	if runtime.GOOS == "linux" {
+		t.Setenv(EnvHostProc, "/proc")
+		if _, err := os.Readlink("/proc/self"); err == nil && !SharesHostPIDs() {
+			t.Error("Expected the procfs of this namespace to share the PIDs")
+		}
+	}
+}
+
+func TestInterfaceResolverHostProc(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("route tables are only read on Linux")
+	}
+	clearHostEnv(t)
+
+	proc := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(proc, "net"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(proc, "net", "route"), []byte(testIPv4Routes), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvHostProc, proc)
+
+	// Without a path the resolver follows the host procfs
+	if name := NewInterfaceResolver("", time.Hour).Active(); name != "eth0" {
+		t.Errorf("Expected eth0 from the host procfs, got %q", name)
+	}
+}
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Route flags from linux/route.h
+const (
+	routeFlagUp     = 0x0001
+	routeFlagReject = 0x0200
+)
+
+// InterfaceResolver finds the network interface carrying the default route.
+// The result is cached and only resolved again once it is older than the refresh interval,
+// so it can be queried on every tick.
+type InterfaceResolver struct {
+	mu          sync.Mutex
+	procNetPath string
+	refresh     time.Duration
+	name        string
+	resolvedAt  time.Time
+	changes     int
+}
+
+// DefaultInterfaceResolver is the resolver shared by the network collector and the speed detection
+var DefaultInterfaceResolver = NewInterfaceResolver("", InterfaceRefreshInterval)
+
+// NewInterfaceResolver creates a resolver reading the route tables from procNetPath,
+// or from the net directory of the host procfs when procNetPath is empty
+func NewInterfaceResolver(procNetPath string, refresh time.Duration) *InterfaceResolver {
+	return &InterfaceResolver{
+		procNetPath: procNetPath,
+		refresh:     refresh,
+	}
+}
+
+// Active returns the name of the active interface, resolving it again when the cached one is stale
+func (r *InterfaceResolver) Active() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.resolvedAt.IsZero() || time.Since(r.resolvedAt) >= r.refresh {
+		r.resolveLocked()
+	}
+	return r.name
+}
+
+// Refresh resolves the active interface immediately and reports whether it changed
+func (r *InterfaceResolver) Refresh() (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := r.resolveLocked()
+	return r.name, changed
+}
+
+// Changes returns how many times the active interface changed after the first resolution
+func (r *InterfaceResolver) Changes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.changes
+}
+
+// resolveLocked updates the cached interface and reports whether it changed, r.mu must be held
+func (r *InterfaceResolver) resolveLocked() bool {
+	name := r.resolve()
+	changed := !r.resolvedAt.IsZero() && name != r.name
+	if changed {
+		r.changes++
+	}
+
+	r.name = name
+	r.resolvedAt = time.Now()
+	return changed
+}
+
+// resolve looks up the default route interface, falling back to the first usable interface
+func (r *InterfaceResolver) resolve() string {
+	if runtime.GOOS == "linux" {
+		// The host procfs is looked up on every resolve since it is configured after startup
+		procNetPath := r.procNetPath
+		if procNetPath == "" {
+			procNetPath = HostProc("net")
+		}
+		if data, err := os.ReadFile(filepath.Join(procNetPath, "route")); err == nil {
+			if name, ok := parseIPv4DefaultRoute(data); ok {
+				return name
+			}
+		}
+		if data, err := os.ReadFile(filepath.Join(procNetPath, "ipv6_route")); err == nil {
+			if name, ok := parseIPv6DefaultRoute(data); ok {
+				return name
+			}
+		}
+	}
+
+	return firstUsableInterface()
+}
+
+// parseIPv4DefaultRoute returns the interface of the default route with the lowest metric in /proc/net/route
+func parseIPv4DefaultRoute(data []byte) (string, bool) {
+	best, bestMetric := "", uint64(0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // Skip the header line
+	for scanner.Scan() {
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask MTU Window IRTT
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil || flags&routeFlagUp == 0 || flags&routeFlagReject != 0 {
+			continue
+		}
+		metric, err := strconv.ParseUint(fields[6], 10, 32)
+		if err != nil {
+			continue
+		}
+
+		if best == "" || metric < bestMetric {
+			best, bestMetric = fields[0], metric
+		}
+	}
+
+	return best, best != ""
+}
+
+// parseIPv6DefaultRoute returns the interface of the default route with the lowest metric in /proc/net/ipv6_route
+func parseIPv6DefaultRoute(data []byte) (string, bool) {
+	best, bestMetric := "", uint64(0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// dest dest_prefix src src_prefix next_hop metric refcnt use flags iface
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || strings.Trim(fields[0], "0") != "" || fields[1] != "00" || fields[9] == "lo" {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[8], 16, 32)
+		if err != nil || flags&routeFlagUp == 0 || flags&routeFlagReject != 0 {
+			continue
+		}
+		metric, err := strconv.ParseUint(fields[5], 16, 32)
+		if err != nil {
+			continue
+		}
+
+		if best == "" || metric < bestMetric {
+			best, bestMetric = fields[9], metric
+		}
+	}
+
+	return best, best != ""
+}
+
+// firstUsableInterface returns the first interface that is up, not a loopback and has an address
+func firstUsableInterface() string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+
+	var fallback string
+	for _, iface := range interfaces {
+		// Skip interfaces that are down or loopback interfaces
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil || len(addrs) == 0 {
+			continue
+		}
+
+		// On macOS en0 (WiFi) or en1 (Ethernet) is normally the primary interface
+		if runtime.GOOS != "darwin" || iface.Name == "en0" || iface.Name == "en1" {
+			return iface.Name
+		}
+		if fallback == "" {
+			fallback = iface.Name
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+const testIPv4Routes = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+wlan0	00000000	0101A8C0	0003	0	0	600	00000000	0	0	0
+eth0	00000000	010200C0	0003	0	0	100	00000000	0	0	0
+eth0	000200C0	00000000	0001	0	0	0	00FFFFFF	0	0	0
+veth12	00000000	00000000	0000	0	0	0	00000000	0	0	0
+`
+
+const testIPv6Routes = `fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000002 00000000 00000001     eth1
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 fd000000000000000000000000000001 00000400 00000001 00000000 00000003     eth1
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo
+`
+
+func TestParseIPv4DefaultRoute(t *testing.T) {
+	// The default route with the lowest metric wins, routes that are not up are ignored
+	name, ok := parseIPv4DefaultRoute([]byte(testIPv4Routes))
+	if !ok || name != "eth0" {
+		t.Errorf("Expected eth0, got %q (found %v)", name, ok)
+	}
+
+	// A table without a default route resolves nothing
+	_, ok = parseIPv4DefaultRoute([]byte("Iface\tDestination\n" +
+		"eth0\t000200C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n"))
+	if ok {
+		t.Errorf("Expected no default route to be found")
+	}
+}
+
+func TestParseIPv6DefaultRoute(t *testing.T) {
+	// The unreachable loopback default route is ignored
+	name, ok := parseIPv6DefaultRoute([]byte(testIPv6Routes))
+	if !ok || name != "eth1" {
+		t.Errorf("Expected eth1, got %q (found %v)", name, ok)
+	}
+}
+
+func TestInterfaceResolver(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("route tables are only read on Linux")
+	}
+
+	dir := t.TempDir()
+	writeRoutes := func(routes string) {
+		if err := os.WriteFile(filepath.Join(dir, "route"), []byte(routes), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRoutes(testIPv4Routes)
+
+	resolver := NewInterfaceResolver(dir, time.Hour)
+	if resolver.Active() != "eth0" {
+		t.Errorf("Expected eth0, got %q", resolver.Active())
+	}
+
+	// The cached interface is kept until it is stale or refreshed
+	writeRoutes("Iface\tDestination\tGateway\tFlags\tRefCnt\tUse\tMetric\tMask\n" +
+		"wlan0\t00000000\t0101A8C0\t0003\t0\t0\t600\t00000000\n")
+	if resolver.Active() != "eth0" {
+		t.Errorf("Expected cached eth0, got %q", resolver.Active())
+	}
+
+	name, changed := resolver.Refresh()
+	if name != "wlan0" || !changed {
+		t.Errorf("Expected change to wlan0, got %q (changed %v)", name, changed)
+	}
+	if resolver.Changes() != 1 {
+		t.Errorf("Expected 1 change, got %d", resolver.Changes())
+	}
+
+	// Without an IPv4 default route the IPv6 table is used
+	writeRoutes("Iface\tDestination\n")
+	if err := os.WriteFile(filepath.Join(dir, "ipv6_route"), []byte(testIPv6Routes), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := resolver.Refresh(); name != "eth1" {
+		t.Errorf("Expected eth1, got %q", name)
+	}
+}
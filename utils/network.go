@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"net"
 	"os"
 	"os/exec"
 	"runtime"
@@ -9,32 +8,29 @@ import (
 	"strings"
 )
 
-// GetMaxNetworkSpeed returns the maximum network speed in MB/s for the current interface.
+// GetMaxNetworkSpeed returns the maximum network speed in MB/s for the active interface.
 // Returns DefaultNetworkSpeed as a fallback if the actual speed cannot be determined.
 func GetMaxNetworkSpeed() float64 {
-	// Get the primary network interface
-	ifaces, err := net.Interfaces()
-	if err != nil {
+	return NetworkSpeed(DefaultInterfaceResolver.Active())
+}
+
+// NetworkSpeed returns the link speed in MB/s of the given interface.
+// Returns DefaultNetworkSpeed as a fallback if the actual speed cannot be determined.
+func NetworkSpeed(iface string) float64 {
+	if iface == "" {
 		return DefaultNetworkSpeed
 	}
 
-	// Find the first non-loopback interface
-	for _, iface := range ifaces {
-		if iface.Flags&net.FlagLoopback == 0 && iface.Flags&net.FlagUp != 0 {
-			switch runtime.GOOS {
-			case "darwin":
-				return getMacNetworkSpeed(iface.Name)
-			case "linux":
-				return getLinuxNetworkSpeed(iface.Name)
-			case "windows":
-				return getWindowsNetworkSpeed(iface.Name)
-			default:
-				return DefaultNetworkSpeed
-			}
-		}
+	switch runtime.GOOS {
+	case "darwin":
+		return getMacNetworkSpeed(iface)
+	case "linux":
+		return getLinuxNetworkSpeed(iface)
+	case "windows":
+		return getWindowsNetworkSpeed(iface)
+	default:
+		return DefaultNetworkSpeed
 	}
-
-	return DefaultNetworkSpeed
 }
 
 func getMacNetworkSpeed(iface string) float64 {
@@ -91,9 +87,8 @@ func getMacNetworkSpeed(iface string) float64 {
 }
 
 func getLinuxNetworkSpeed(iface string) float64 {
-	// Read the speed from /sys/class/net/<iface>/speed
-	speedPath := "/sys/class/net/" + iface + "/speed"
-	speedBytes, err := os.ReadFile(speedPath)
+	// Read the speed from /sys/class/net/<iface>/speed of the monitored host
+	speedBytes, err := os.ReadFile(HostSys("class", "net", iface, "speed"))
 	if err != nil {
 		return DefaultNetworkSpeed
 	}
@@ -101,7 +96,8 @@ func getLinuxNetworkSpeed(iface string) float64 {
 	// Parse the speed value
 	speedStr := strings.TrimSpace(string(speedBytes))
 	speed, err := strconv.ParseFloat(speedStr, 64)
-	if err != nil {
+	// Virtual and disconnected interfaces report -1 or 0
+	if err != nil || speed <= 0 {
 		return DefaultNetworkSpeed
 	}
 
@@ -1,5 +1,7 @@
 package utils
 
+import "time"
+
 // Default network speed in MB/s (1 Gbps)
 // Can be overridden at build time with -ldflags "-X go-dummy-monitor/utils.DefaultNetworkSpeed=250"
 var DefaultNetworkSpeed float64 = 125 // 1 Gbps in MB/s
@@ -9,3 +11,6 @@ const (
 	MbpsToMBs = 8.0                 // Convert Mbps to MB/s
 	BpsToMBs  = 8 * 1024.0 * 1024.0 // Convert bps to MB/s (bits to bytes * bytes to MB)
 )
+
+// InterfaceRefreshInterval is how long a resolved active interface is reused before the route table is read again
+const InterfaceRefreshInterval = 10 * time.Second